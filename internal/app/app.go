@@ -3,13 +3,10 @@ package app
 import (
 	"Fuploader/internal/config"
 	"Fuploader/internal/database"
-	"Fuploader/internal/platform/baijiahao"
-	"Fuploader/internal/platform/bilibili"
 	"Fuploader/internal/platform/browser"
-	"Fuploader/internal/platform/douyin"
-	"Fuploader/internal/platform/kuaishou"
-	"Fuploader/internal/platform/tiktok"
-	"Fuploader/internal/platform/xiaohongshu"
+	"Fuploader/internal/platform/platformutils"
+	"Fuploader/internal/platform/ratelimit"
+	"Fuploader/internal/platform/vault"
 	"Fuploader/internal/scheduler"
 	"Fuploader/internal/service"
 	"Fuploader/internal/types"
@@ -19,6 +16,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
@@ -35,9 +33,20 @@ type App struct {
 	scheduleService   *service.ScheduleService
 	logService        *service.LogService
 	screenshotService *service.ScreenshotService
+	exportService     *service.ExportService
+	tagSetService     *service.TagSetService
+	presetService     *service.PresetService
+	recurringService  *service.RecurringScheduleService
+	queueService      *service.QueueService
+	releasePlanner    *service.ReleasePlanner
+	calendarService   *service.CalendarService
 	scheduler         *scheduler.EnhancedScheduler
 	initialized       bool
 	initError         string
+
+	fingerprintService *service.FingerprintService
+
+	browserPool *browser.Pool
 }
 
 func NewApp() *App {
@@ -65,13 +74,37 @@ func (a *App) Startup(ctx context.Context) {
 		return
 	}
 
+	// 初始化 Cookie 凭据库，并加密旧版本的明文 Cookie 文件
+	a.initVault()
+
+	// 结束上次崩溃遗留的浏览器进程，再创建所有平台共用的浏览器池
+	if killed, err := browser.KillOrphanBrowsers(); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 清理遗留浏览器进程失败: %v", err))
+	} else if killed > 0 {
+		utils.Info(fmt.Sprintf("[+] 已结束 %d 个遗留浏览器进程", killed))
+	}
+	a.browserPool = browser.NewPoolFromConfig()
+	browser.SetDefaultPool(a.browserPool)
+
 	db := database.GetDB()
-	a.accountService = service.NewAccountService(db)
+	a.accountService = service.NewAccountService(db, a.browserPool)
 	a.fileService = service.NewFileService(db)
-	a.uploadService = service.NewUploadService(db)
+	a.uploadService = service.NewUploadService(db, a.browserPool)
 	a.scheduleService = service.NewScheduleService(db)
 	a.logService = service.NewLogService()
 	a.screenshotService = service.NewScreenshotService()
+	a.exportService = service.NewExportService(db)
+	a.tagSetService = service.NewTagSetService(db)
+	a.presetService = service.NewPresetService(db)
+	a.queueService = service.NewQueueService(db, a.uploadService, a.scheduleService)
+	a.recurringService = service.NewRecurringScheduleService(db, a.uploadService, a.scheduleService, a.queueService)
+	a.releasePlanner = service.NewReleasePlanner(db, a.uploadService, a.scheduleService)
+	a.calendarService = service.NewCalendarService(db, a.uploadService, a.scheduleService)
+	a.fingerprintService = service.NewFingerprintService(db)
+
+	// 浏览器池为账号创建上下文时使用账号的固定指纹和绑定的代理
+	browser.SetFingerprintStore(a.fingerprintService)
+	browser.SetProxyProvider(a.uploadService.Proxies())
 
 	// 将 LogService 注入到 logger，使日志同时输出到前端
 	utils.SetLogService(a.logService)
@@ -81,6 +114,19 @@ func (a *App) Startup(ctx context.Context) {
 	// 设置并启动增强调度器
 	a.setupScheduler(db)
 
+	// 从发布台账恢复限流状态
+	a.uploadService.RestoreRateLimitState()
+
+	// 应用中断时正在取出的队列条目标记为失败，避免重复发布
+	if count, err := a.queueService.RecoverClaimedItems(a.ctx); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 恢复队列条目失败: %v", err))
+	} else if count > 0 {
+		utils.Warn(fmt.Sprintf("[-] %d 个队列条目在发布时被中断，已标记为失败", count))
+	}
+
+	// 启动周期性发布计划
+	a.recurringService.Start()
+
 	a.initialized = true
 	utils.Info("Application started successfully")
 }
@@ -93,6 +139,10 @@ func (a *App) setupScheduler(db *gorm.DB) {
 	// 注册各平台上传器
 	a.registerUploaders()
 
+	// 本地定时的上传任务到期后交给上传服务执行
+	a.scheduler.SetTaskExecutor(a.uploadService.ExecuteLocalScheduledTask)
+	a.uploadService.SetJobScheduler(a.scheduler)
+
 	// 启动调度器
 	a.scheduler.Start()
 
@@ -110,30 +160,14 @@ func (a *App) registerUploaders() {
 
 	for _, account := range accounts {
 		cookiePath := a.accountService.GetCookiePath(account.Platform, uint(account.ID))
-		var uploader types.Uploader
-
-		switch account.Platform {
-		case "xiaohongshu":
-			uploader = xiaohongshu.NewUploader(cookiePath)
-		case "douyin":
-			uploader = douyin.NewUploader(cookiePath)
-		case "bilibili":
-			uploader = bilibili.NewUploader(cookiePath)
-		case "kuaishou":
-			uploader = kuaishou.NewUploader(cookiePath)
-		case "tiktok":
-			uploader = tiktok.NewUploader(cookiePath)
-		case "baijiahao":
-			uploader = baijiahao.NewUploader(cookiePath)
-		default:
+		uploader := service.NewUploader(account.Platform, uint(account.ID), cookiePath, a.browserPool)
+		if uploader == nil {
 			utils.Warn(fmt.Sprintf("[-] 未知平台: %s", account.Platform))
 			continue
 		}
 
-		if uploader != nil {
-			a.scheduler.RegisterUploader(account.Platform, uploader)
-			utils.Info(fmt.Sprintf("[+] 已注册上传器 - 平台: %s, 账号: %s", account.Platform, account.Name))
-		}
+		a.scheduler.RegisterUploader(account.Platform, uploader)
+		utils.Info(fmt.Sprintf("[+] 已注册上传器 - 平台: %s, 账号: %s", account.Platform, account.Name))
 	}
 }
 
@@ -153,6 +187,23 @@ func (a *App) Shutdown(ctx context.Context) {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
+	// 本地定时任务依赖应用运行，关闭前提示
+	if a.uploadService != nil {
+		if count := a.uploadService.CountPendingLocalTasks(); count > 0 {
+			utils.Warn(fmt.Sprintf("[-] 还有 %d 个本地定时任务等待执行，应用关闭期间不会发布，下次启动后将补发", count))
+		}
+	}
+	if a.scheduler != nil {
+		if count := a.scheduler.CountRunningTasks(); count > 0 {
+			utils.Warn(fmt.Sprintf("[-] 还有 %d 个定时任务正在执行，等待完成；若被强制退出，下次启动后将重新执行", count))
+		}
+	}
+
+	// 停止周期性发布计划
+	if a.recurringService != nil {
+		a.recurringService.Stop()
+	}
+
 	// 停止调度器
 	if a.scheduler != nil {
 		a.scheduler.Stop()
@@ -167,6 +218,22 @@ func (a *App) Shutdown(ctx context.Context) {
 		// 给一点时间让日志写入
 	}
 
+	// 保存各上下文的 Cookie 并关闭浏览器池中的所有浏览器
+	if a.browserPool != nil {
+		done := make(chan error, 1)
+		go func() { done <- a.browserPool.Close() }()
+		select {
+		case err := <-done:
+			if err != nil {
+				utils.Error(fmt.Sprintf("[-] 浏览器池关闭失败: %v", err))
+			} else {
+				utils.Info("[-] 浏览器池已关闭")
+			}
+		case <-shutdownCtx.Done():
+			utils.Warn("[-] 浏览器池关闭超时")
+		}
+	}
+
 	// 关闭数据库
 	if err := database.Close(); err != nil {
 		utils.Error(fmt.Sprintf("[-] 数据库关闭失败: %v", err))
@@ -371,6 +438,11 @@ func (a *App) UpdateVideo(video database.Video) error {
 	return a.fileService.UpdateVideo(a.ctx, &video)
 }
 
+// SetVideoCustomFields 设置视频自定义字段（模板变量），fields 为空时清空
+func (a *App) SetVideoCustomFields(videoID int, fields map[string]string) error {
+	return a.fileService.SetVideoCustomFields(a.ctx, videoID, fields)
+}
+
 func (a *App) DeleteVideo(id int) error {
 	return a.fileService.DeleteVideo(a.ctx, id)
 }
@@ -398,169 +470,54 @@ func (a *App) CreateUploadTask(
 	return a.uploadService.CreateUploadTask(a.ctx, videoID, accountIDs, nil, taskMetadata)
 }
 
-// createScheduledTasks 创建定时任务
-func (a *App) createScheduledTasks(
+// CreateUploadTaskWithReport 创建上传任务并返回每个账号的创建结果
+// 触发限流的账号会创建排队任务（rate_limited），结果中包含推迟原因和最早可发布时间
+func (a *App) CreateUploadTaskWithReport(
 	videoID int,
 	accountIDs []int,
-	scheduleTimeStr string,
-	metadata *service.UploadTaskMetadata,
-) ([]database.UploadTask, error) {
-	// 获取视频信息
-	videos, err := a.fileService.GetVideos(a.ctx)
-	if err != nil {
-		return nil, fmt.Errorf("获取视频列表失败: %w", err)
-	}
-
-	var video *database.Video
-	for i := range videos {
-		if videos[i].ID == videoID {
-			video = &videos[i]
-			break
-		}
-	}
-	if video == nil {
-		return nil, fmt.Errorf("视频不存在 (ID: %d)", videoID)
-	}
-
-	// 解析定时时间
-	scheduleTime, err := time.Parse(time.RFC3339, scheduleTimeStr)
-	if err != nil {
-		// 尝试前端格式: 2006-01-02T15:04:05
-		scheduleTime, err = time.Parse("2006-01-02T15:04:05", scheduleTimeStr)
-		if err != nil {
-			// 尝试其他格式: 2006-01-02 15:04
-			scheduleTime, err = time.Parse("2006-01-02 15:04", scheduleTimeStr)
-			if err != nil {
-				return nil, fmt.Errorf("定时时间格式错误: %w", err)
-			}
+	scheduleTime *string,
+	metadata *string,
+) (*types.UploadTaskResult, error) {
+	var taskMetadata *service.UploadTaskMetadata
+	if metadata != nil && *metadata != "" {
+		taskMetadata = &service.UploadTaskMetadata{}
+		if err := json.Unmarshal([]byte(*metadata), taskMetadata); err != nil {
+			return nil, fmt.Errorf("failed to parse metadata: %w", err)
 		}
 	}
 
-	// 验证时间范围（≥2小时且≤15天）
-	now := time.Now()
-	minTime := now.Add(2 * time.Hour)
-	maxTime := now.Add(15 * 24 * time.Hour)
-
-	if scheduleTime.Before(minTime) {
-		return nil, fmt.Errorf("定时时间必须至少提前2小时")
-	}
-	if scheduleTime.After(maxTime) {
-		return nil, fmt.Errorf("定时时间不能超过15天")
+	tasks, reports, err := a.uploadService.CreateUploadTaskWithReport(a.ctx, videoID, accountIDs, scheduleTime, taskMetadata)
+	if err != nil && reports == nil {
+		return nil, err
 	}
-
-	var tasks []database.UploadTask
-	var errors []string
-
-	for _, accountID := range accountIDs {
-		account, err := a.accountService.GetAccountByID(a.ctx, accountID)
-		if err != nil {
-			errMsg := fmt.Sprintf("获取账号 %d 失败: %v", accountID, err)
-			utils.Warn(fmt.Sprintf("[-] %s", errMsg))
-			errors = append(errors, errMsg)
-			continue
-		}
-
-		// 构建任务标题
-		title := video.Title
-		if metadata != nil && metadata.Common.Title != "" {
-			title = metadata.Common.Title
-		}
-
-		// 应用平台特定字段
-		platformFields := types.PlatformFields{}
-		if metadata != nil && metadata.Platforms != nil {
-			if pf, ok := metadata.Platforms[account.Platform]; ok {
-				platformFields = pf
-				if pf.Title != "" {
-					title = pf.Title
-				}
-			}
-		}
-
-		// 创建定时任务
-		scheduledTask := &database.ScheduledTask{
-			ID:           fmt.Sprintf("task_%d_%d_%d", videoID, accountID, time.Now().Unix()),
-			AccountID:    uint(accountID),
-			Platform:     account.Platform,
-			VideoPath:    video.FilePath,
-			Title:        title,
-			Description:  video.Description,
-			Tags:         joinTags(video.Tags),
-			ScheduleTime: scheduleTime,
-			Status:       database.TaskStatusPending,
-			Priority:     database.PriorityNormal,
-			MaxRetries:   3,
-		}
-
-		// 添加到调度器
-		if err := a.scheduler.AddTask(scheduledTask); err != nil {
-			errMsg := fmt.Sprintf("添加定时任务到调度器失败 (账号: %s): %v", account.Name, err)
-			utils.Warn(fmt.Sprintf("[-] %s", errMsg))
-			errors = append(errors, errMsg)
-			continue
-		}
-
-		// 同时创建 UploadTask 用于前端展示
-		uploadTask := database.UploadTask{
-			VideoID:      videoID,
-			AccountID:    accountID,
-			Platform:     account.Platform,
-			Status:       config.TaskStatusPending,
-			Progress:     0,
-			ScheduleTime: &scheduleTimeStr,
-			Title:        title,
-		}
-		// 应用平台特定字段
-		if platformFields.Collection != "" {
-			uploadTask.Collection = platformFields.Collection
-		}
-		if platformFields.Thumbnail != "" {
-			uploadTask.Thumbnail = platformFields.Thumbnail
-		}
-		uploadTask.IsOriginal = platformFields.IsOriginal
-		uploadTask.IsDraft = platformFields.IsDraft
-
-		// 保存到数据库
-		if err := database.DB.Create(&uploadTask).Error; err != nil {
-			errMsg := fmt.Sprintf("创建上传任务记录失败 (账号: %s): %v", account.Name, err)
-			utils.Warn(fmt.Sprintf("[-] %s", errMsg))
-			errors = append(errors, errMsg)
-			continue
-		}
-
-		tasks = append(tasks, uploadTask)
-		utils.Info(fmt.Sprintf("[+] 定时任务已创建 - 视频: %s, 平台: %s, 时间: %s", video.Title, account.Platform, scheduleTimeStr))
+	result := &types.UploadTaskResult{Reports: reports}
+	for _, task := range tasks {
+		result.TaskIDs = append(result.TaskIDs, task.ID)
 	}
+	return result, nil
+}
 
-	// 如果没有成功创建任何任务，返回错误
-	if len(tasks) == 0 {
-		if len(errors) > 0 {
-			return nil, fmt.Errorf("创建任务失败: %s", strings.Join(errors, "; "))
+// PreviewUploadTask 预览模板渲染后各账号的标题与描述
+func (a *App) PreviewUploadTask(
+	videoID int,
+	accountIDs []int,
+	scheduleTime *string,
+	metadata *string,
+) ([]types.TaskTextPreview, error) {
+	var taskMetadata *service.UploadTaskMetadata
+	if metadata != nil && *metadata != "" {
+		taskMetadata = &service.UploadTaskMetadata{}
+		if err := json.Unmarshal([]byte(*metadata), taskMetadata); err != nil {
+			return nil, fmt.Errorf("failed to parse metadata: %w", err)
 		}
-		return nil, fmt.Errorf("没有成功创建任何任务")
 	}
 
-	// 如果部分成功，记录警告
-	if len(errors) > 0 {
-		utils.Warn(fmt.Sprintf("[-] 部分任务创建失败: %s", strings.Join(errors, "; ")))
-	}
-
-	return tasks, nil
+	return a.uploadService.PreviewUploadTask(a.ctx, videoID, accountIDs, scheduleTime, taskMetadata)
 }
 
-// joinTags 将标签数组拼接为字符串
-func joinTags(tags []string) string {
-	if len(tags) == 0 {
-		return ""
-	}
-	result := ""
-	for i, tag := range tags {
-		if i > 0 {
-			result += ","
-		}
-		result += tag
-	}
-	return result
+// NormalizeText 按平台规则预览文本规范化结果（字段：title/shortTitle/description）
+func (a *App) NormalizeText(platform string, field string, text string) types.TextNormalization {
+	return platformutils.NormalizeText(platform, field, text)
 }
 
 func (a *App) GetUploadTasks(status string) ([]database.UploadTask, error) {
@@ -587,6 +544,20 @@ func (a *App) UpdateScheduleConfig(config database.ScheduleConfig) error {
 	return a.scheduleService.UpdateScheduleConfig(a.ctx, &config)
 }
 
+// SetAccountTimeZone 设置账号时区（IANA 名称，如 America/Los_Angeles），为空表示使用发布配置的时区
+func (a *App) SetAccountTimeZone(accountID int, timeZone string) error {
+	return a.scheduleService.SetAccountTimeZone(a.ctx, accountID, timeZone)
+}
+
+// CheckScheduleTime 按账号时区和平台定时规则校验定时时间，返回实际发布时刻及填写到平台页面的时间
+func (a *App) CheckScheduleTime(accountID int, scheduleTime string) (types.ScheduleTimeCheck, error) {
+	account, err := a.accountService.GetAccountByID(a.ctx, accountID)
+	if err != nil {
+		return types.ScheduleTimeCheck{}, err
+	}
+	return a.uploadService.CheckScheduleTime(a.ctx, account, scheduleTime)
+}
+
 func (a *App) GenerateScheduleTimes(videoCount int) ([]string, error) {
 	times, err := a.scheduleService.GenerateScheduleTimes(a.ctx, videoCount)
 	if err != nil {
@@ -928,6 +899,452 @@ func (a *App) OpenScreenshotDir(platform string) error {
 	return a.OpenDirectory(dir)
 }
 
+// ============================================
+// 上传录屏 API
+// ============================================
+
+// GetRecordingConfig 获取录屏配置
+func (a *App) GetRecordingConfig() (*types.RecordingConfig, error) {
+	return a.uploadService.Recordings().GetConfig(), nil
+}
+
+// UpdateRecordingConfig 更新录屏配置（按平台开关、尺寸、保留天数和总大小）
+func (a *App) UpdateRecordingConfig(config types.RecordingConfig) error {
+	return a.uploadService.Recordings().UpdateConfig(&config)
+}
+
+// GetRecordings 获取录屏文件列表
+func (a *App) GetRecordings() ([]types.RecordingInfo, error) {
+	return a.uploadService.Recordings().ListRecordings()
+}
+
+// CleanRecordings 按保留天数和总大小清理录屏
+func (a *App) CleanRecordings() (int, error) {
+	return a.uploadService.Recordings().CleanRecordings()
+}
+
+// OpenRecordingDir 打开录屏目录
+func (a *App) OpenRecordingDir() error {
+	return a.OpenDirectory(a.uploadService.Recordings().GetDir())
+}
+
+// ============================================
+// 标签组 API
+// ============================================
+
+// GetTagSets 获取标签组列表
+func (a *App) GetTagSets() ([]database.TagSet, error) {
+	return a.tagSetService.GetTagSets(a.ctx)
+}
+
+// CreateTagSet 创建标签组
+func (a *App) CreateTagSet(tagSet database.TagSet) (*database.TagSet, error) {
+	if err := a.tagSetService.CreateTagSet(a.ctx, &tagSet); err != nil {
+		return nil, err
+	}
+	return &tagSet, nil
+}
+
+// UpdateTagSet 更新标签组
+func (a *App) UpdateTagSet(tagSet database.TagSet) error {
+	return a.tagSetService.UpdateTagSet(a.ctx, &tagSet)
+}
+
+// DeleteTagSet 删除标签组
+func (a *App) DeleteTagSet(id int) error {
+	return a.tagSetService.DeleteTagSet(a.ctx, id)
+}
+
+// ExpandTagSet 预览标签组在指定平台展开后的标签
+func (a *App) ExpandTagSet(id int, platform string) ([]string, error) {
+	return a.tagSetService.ExpandTagSet(a.ctx, id, platform)
+}
+
+// ============================================
+// 发布预设 API
+// ============================================
+
+// GetPublishPresets 获取发布预设列表，platform 为空时返回全部
+func (a *App) GetPublishPresets(platform string) ([]database.PublishPreset, error) {
+	return a.presetService.GetPresets(a.ctx, platform)
+}
+
+// CreatePublishPreset 创建发布预设
+func (a *App) CreatePublishPreset(preset database.PublishPreset) (*database.PublishPreset, error) {
+	if err := a.presetService.CreatePreset(a.ctx, &preset); err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// UpdatePublishPreset 更新发布预设
+func (a *App) UpdatePublishPreset(preset database.PublishPreset) error {
+	return a.presetService.UpdatePreset(a.ctx, &preset)
+}
+
+// DeletePublishPreset 删除发布预设
+func (a *App) DeletePublishPreset(id int) error {
+	return a.presetService.DeletePreset(a.ctx, id)
+}
+
+// SetAccountDefaultPreset 设置账号默认发布预设，presetID 为 0 表示取消
+func (a *App) SetAccountDefaultPreset(accountID int, presetID int) error {
+	return a.presetService.SetAccountDefaultPreset(a.ctx, accountID, presetID)
+}
+
+// ============================================
+// 周期性发布计划 API
+// ============================================
+
+// GetRecurringSchedules 获取周期性发布计划列表
+func (a *App) GetRecurringSchedules() ([]database.RecurringSchedule, error) {
+	return a.recurringService.GetSchedules(a.ctx)
+}
+
+// CreateRecurringSchedule 创建周期性发布计划（cron 表达式或星期/时间规则）
+func (a *App) CreateRecurringSchedule(schedule database.RecurringSchedule) (*database.RecurringSchedule, error) {
+	if err := a.recurringService.CreateSchedule(a.ctx, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// UpdateRecurringSchedule 更新周期性发布计划
+func (a *App) UpdateRecurringSchedule(schedule database.RecurringSchedule) error {
+	return a.recurringService.UpdateSchedule(a.ctx, &schedule)
+}
+
+// DeleteRecurringSchedule 删除周期性发布计划
+func (a *App) DeleteRecurringSchedule(id int) error {
+	return a.recurringService.DeleteSchedule(a.ctx, id)
+}
+
+// SetRecurringScheduleEnabled 启用/停用周期性发布计划
+func (a *App) SetRecurringScheduleEnabled(id int, enabled bool) error {
+	return a.recurringService.SetScheduleEnabled(a.ctx, id, enabled)
+}
+
+// GetUpcomingSlots 预览即将到来的发布时段，scheduleID 为 0 时返回所有启用的计划
+func (a *App) GetUpcomingSlots(scheduleID int, count int) ([]types.UpcomingSlot, error) {
+	return a.recurringService.GetUpcomingSlots(a.ctx, scheduleID, count)
+}
+
+// GetScheduleSlotHistory 获取计划的时段消费记录
+func (a *App) GetScheduleSlotHistory(scheduleID int, limit int) ([]database.ScheduleSlot, error) {
+	return a.recurringService.GetSlotHistory(a.ctx, scheduleID, limit)
+}
+
+// ============================================
+// 内容队列 API
+// ============================================
+
+// GetContentQueues 获取内容队列列表
+func (a *App) GetContentQueues() ([]database.ContentQueue, error) {
+	return a.queueService.GetQueues(a.ctx)
+}
+
+// CreateContentQueue 创建内容队列
+func (a *App) CreateContentQueue(queue database.ContentQueue) (*database.ContentQueue, error) {
+	if err := a.queueService.CreateQueue(a.ctx, &queue); err != nil {
+		return nil, err
+	}
+	return &queue, nil
+}
+
+// UpdateContentQueue 更新内容队列（目标账号、预设等）
+func (a *App) UpdateContentQueue(queue database.ContentQueue) error {
+	return a.queueService.UpdateQueue(a.ctx, &queue)
+}
+
+// DeleteContentQueue 删除内容队列
+func (a *App) DeleteContentQueue(id int) error {
+	return a.queueService.DeleteQueue(a.ctx, id)
+}
+
+// SetContentQueuePaused 暂停/恢复内容队列
+func (a *App) SetContentQueuePaused(id int, paused bool) error {
+	return a.queueService.SetQueuePaused(a.ctx, id, paused)
+}
+
+// GetQueueItems 获取队列条目，pendingOnly 为 true 时只返回待发布条目
+func (a *App) GetQueueItems(queueID int, pendingOnly bool) ([]database.QueueItem, error) {
+	return a.queueService.GetQueueItems(a.ctx, queueID, pendingOnly)
+}
+
+// AddVideosToQueue 向队列添加视频，atFront 为 true 时插入队首
+func (a *App) AddVideosToQueue(queueID int, videoIDs []int, atFront bool) ([]database.QueueItem, error) {
+	return a.queueService.AddVideos(a.ctx, queueID, videoIDs, atFront)
+}
+
+// RemoveQueueItem 从队列移除条目
+func (a *App) RemoveQueueItem(itemID int) error {
+	return a.queueService.RemoveItem(a.ctx, itemID)
+}
+
+// RetryQueueItem 将创建上传任务失败的队列条目重新放回队列
+func (a *App) RetryQueueItem(itemID int) error {
+	return a.queueService.RetryItem(a.ctx, itemID)
+}
+
+// ReorderQueueItems 按给定顺序重排待发布条目
+func (a *App) ReorderQueueItems(queueID int, itemIDs []int) error {
+	return a.queueService.ReorderItems(a.ctx, queueID, itemIDs)
+}
+
+// ScheduleContentQueue 按发布配置的每日时间为队列中所有待发布视频创建定时任务
+func (a *App) ScheduleContentQueue(queueID int) ([]database.UploadTask, error) {
+	return a.queueService.ScheduleQueue(a.ctx, queueID)
+}
+
+// ============================================
+// 错峰发布 API
+// ============================================
+
+// PreviewReleasePlan 预览错峰发布计划：在发布窗口内为各账号分配发布时间
+func (a *App) PreviewReleasePlan(request types.ReleasePlanRequest) (types.ReleasePlan, error) {
+	return a.releasePlanner.Plan(a.ctx, request)
+}
+
+// CreateReleasePlan 按错峰发布计划为各账号创建定时上传任务
+func (a *App) CreateReleasePlan(request types.ReleasePlanRequest, metadata *string) ([]database.UploadTask, error) {
+	var taskMetadata *service.UploadTaskMetadata
+	if metadata != nil && *metadata != "" {
+		taskMetadata = &service.UploadTaskMetadata{}
+		if err := json.Unmarshal([]byte(*metadata), taskMetadata); err != nil {
+			return nil, fmt.Errorf("failed to parse metadata: %w", err)
+		}
+	}
+
+	tasks, plan, err := a.releasePlanner.Create(a.ctx, request, taskMetadata)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Warnings) > 0 {
+		utils.Warn(fmt.Sprintf("[-] 错峰发布部分账号未创建: %s", strings.Join(plan.Warnings, "; ")))
+	}
+	return tasks, nil
+}
+
+// ============================================
+// 发布日历 API
+// ============================================
+
+// GetPublishCalendar 获取日期范围内（格式 2006-01-02，含结束日期）的发布日历，按账号分组并标记冲突
+func (a *App) GetPublishCalendar(startDate string, endDate string) (*types.PublishCalendar, error) {
+	return a.calendarService.GetCalendar(a.ctx, startDate, endDate)
+}
+
+// RescheduleUploadTask 修改任务定时时间（日历拖拽改期）
+func (a *App) RescheduleUploadTask(id int, scheduleTime string) (*database.UploadTask, error) {
+	return a.uploadService.RescheduleUploadTask(a.ctx, id, scheduleTime)
+}
+
+// ============================================
+// 禁发时段 API
+// ============================================
+
+// GetBlackoutWindows 获取禁发时段列表
+func (a *App) GetBlackoutWindows() ([]database.BlackoutWindow, error) {
+	return a.scheduleService.Blackouts().GetWindows(a.ctx)
+}
+
+// CreateBlackoutWindow 创建禁发时段（全局/平台/账号，一次性时间段或每日静默时段）
+func (a *App) CreateBlackoutWindow(window database.BlackoutWindow) (*database.BlackoutWindow, error) {
+	if err := a.scheduleService.Blackouts().CreateWindow(a.ctx, &window); err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+// UpdateBlackoutWindow 更新禁发时段
+func (a *App) UpdateBlackoutWindow(window database.BlackoutWindow) error {
+	return a.scheduleService.Blackouts().UpdateWindow(a.ctx, &window)
+}
+
+// DeleteBlackoutWindow 删除禁发时段
+func (a *App) DeleteBlackoutWindow(id int) error {
+	return a.scheduleService.Blackouts().DeleteWindow(a.ctx, id)
+}
+
+// ============================================
+// 限流 API
+// ============================================
+
+// GetAllRateLimitStats 获取平台限流统计
+func (a *App) GetAllRateLimitStats() map[string]ratelimit.Stats {
+	return a.uploadService.GetAllRateLimitStats()
+}
+
+// GetAccountRateLimitStats 获取账号级限额统计（滚动窗口内发布数、最早可发布时间）
+func (a *App) GetAccountRateLimitStats() ([]types.AccountRateLimitStats, error) {
+	return a.uploadService.GetAccountRateLimitStats(a.ctx)
+}
+
+// GetAccountRateLimit 获取账号限额
+func (a *App) GetAccountRateLimit(accountID int) (*database.AccountRateLimit, error) {
+	return a.uploadService.AccountLimits().GetLimit(a.ctx, accountID)
+}
+
+// SetAccountRateLimit 设置账号限额（每日/每小时发布数、最小发布间隔，0 表示不限制）
+func (a *App) SetAccountRateLimit(limit database.AccountRateLimit) error {
+	return a.uploadService.AccountLimits().SetLimit(a.ctx, &limit)
+}
+
+// DeleteAccountRateLimit 删除账号限额
+func (a *App) DeleteAccountRateLimit(accountID int) error {
+	return a.uploadService.AccountLimits().DeleteLimit(a.ctx, accountID)
+}
+
+// ============================================
+// 浏览器指纹 API
+// ============================================
+
+// GetAccountFingerprint 获取账号的固定浏览器指纹，尚未生成时返回 nil
+func (a *App) GetAccountFingerprint(accountID int) (*browser.Fingerprint, error) {
+	return a.fingerprintService.GetFingerprint(a.ctx, accountID)
+}
+
+// ResetAccountFingerprint 重置账号指纹，下次打开浏览器时重新生成
+func (a *App) ResetAccountFingerprint(accountID int) error {
+	return a.fingerprintService.ResetFingerprint(a.ctx, accountID)
+}
+
+// ============================================
+// 账号代理 API
+// ============================================
+
+// GetAccountProxies 获取所有账号代理配置
+func (a *App) GetAccountProxies() ([]database.AccountProxy, error) {
+	return a.uploadService.Proxies().GetProxies(a.ctx)
+}
+
+// GetAccountProxy 获取账号代理配置，未配置时返回 nil
+func (a *App) GetAccountProxy(accountID int) (*database.AccountProxy, error) {
+	return a.uploadService.Proxies().GetProxy(a.ctx, accountID)
+}
+
+// SetAccountProxy 设置账号代理（http/https/socks5），新建的浏览器上下文生效
+func (a *App) SetAccountProxy(proxy database.AccountProxy) error {
+	return a.uploadService.Proxies().SetProxy(a.ctx, &proxy)
+}
+
+// DeleteAccountProxy 删除账号代理
+func (a *App) DeleteAccountProxy(accountID int) error {
+	return a.uploadService.Proxies().DeleteProxy(a.ctx, accountID)
+}
+
+// CheckAccountProxy 检查账号代理是否可用并返回出口 IP，未配置代理时返回 nil
+func (a *App) CheckAccountProxy(accountID int) (*types.ProxyCheckResult, error) {
+	return a.uploadService.Proxies().CheckProxy(a.ctx, accountID)
+}
+
+// ============================================
+// 上传日志 API
+// ============================================
+
+// GetTaskUploadLogs 获取任务的上传日志，失败日志附带 trace / HAR 诊断文件路径
+func (a *App) GetTaskUploadLogs(taskID int) ([]database.UploadLog, error) {
+	return a.uploadService.GetTaskLogs(a.ctx, taskID)
+}
+
+// ============================================
+// 任务历史导出 API
+// ============================================
+
+// ExportTaskHistory 导出任务历史及发布结果（CSV/JSON）
+func (a *App) ExportTaskHistory(query types.TaskExportQuery) (*types.TaskExportResult, error) {
+	result, err := a.exportService.ExportTaskHistory(a.ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	utils.Info(fmt.Sprintf("[+] 已导出 %d 条任务记录: %s", result.Count, result.Path))
+	return result, nil
+}
+
+// SelectExportFile 选择导出文件保存位置
+func (a *App) SelectExportFile(format string) (string, error) {
+	if a.ctx == nil {
+		return "", fmt.Errorf("context not initialized")
+	}
+
+	if format == "" {
+		format = types.ExportFormatCSV
+	}
+
+	selection, err := wailsRuntime.SaveFileDialog(a.ctx, wailsRuntime.SaveDialogOptions{
+		Title:            "导出任务历史",
+		DefaultDirectory: config.Config.ExportPath,
+		DefaultFilename:  fmt.Sprintf("tasks_%s.%s", time.Now().Format("20060102"), format),
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: fmt.Sprintf("%s 文件 (*.%s)", strings.ToUpper(format), format), Pattern: "*." + format},
+		},
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("save file dialog failed: %w", err)
+	}
+
+	return selection, nil
+}
+
+// OpenExportDir 打开导出目录
+func (a *App) OpenExportDir() error {
+	return a.OpenDirectory(config.Config.ExportPath)
+}
+
+// ============================================
+// 凭据库 API
+// ============================================
+
+// initVault 初始化凭据库，解锁后迁移明文 Cookie 文件和代理密码
+func (a *App) initVault() {
+	if err := vault.Init(filepath.Dir(config.Config.CookiePath)); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 凭据库初始化失败: %v", err))
+		return
+	}
+	a.migrateCookieFiles()
+}
+
+// migrateCookieFiles 一次性加密旧版本的明文 Cookie 文件和代理密码
+func (a *App) migrateCookieFiles() {
+	count, err := vault.MigrateDir(config.Config.CookiePath)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 迁移明文 Cookie 文件失败: %v", err))
+	}
+	if count > 0 {
+		utils.Info(fmt.Sprintf("[+] 已加密 %d 个明文 Cookie 文件", count))
+	}
+
+	// 凭据库在创建各服务之前初始化，这里直接使用数据库
+	count, err = service.NewProxyService(database.GetDB()).MigratePasswords(context.Background())
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 迁移明文代理密码失败: %v", err))
+	}
+	if count > 0 {
+		utils.Info(fmt.Sprintf("[+] 已加密 %d 个明文代理密码", count))
+	}
+}
+
+// GetVaultStatus 获取凭据库状态
+func (a *App) GetVaultStatus() types.VaultStatus {
+	source, err := vault.Status()
+	status := types.VaultStatus{Source: source, Locked: source == ""}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// UnlockVault 使用口令解锁凭据库（口令保护模式）
+func (a *App) UnlockVault(passphrase string) error {
+	if err := vault.Unlock(passphrase); err != nil {
+		return err
+	}
+	utils.Info("[+] 凭据库已解锁")
+	a.migrateCookieFiles()
+	return nil
+}
+
 // ============================================
 // 浏览器无头模式配置 API
 // ============================================
@@ -954,9 +1371,38 @@ func (a *App) SetHeadlessConfig(headless bool) error {
 	return nil
 }
 
+// GetBrowserPoolSnapshot 获取浏览器池实时状态：每个浏览器和上下文的占用任务、平台、账号、存活时间、最近活动和健康状况
+func (a *App) GetBrowserPoolSnapshot() (*browser.PoolSnapshot, error) {
+	return a.browserPool.Snapshot(), nil
+}
+
+// ForceCloseBrowserContext 强制关闭卡住的浏览器上下文（占用它的上传会失败）
+func (a *App) ForceCloseBrowserContext(contextID string) error {
+	return a.browserPool.ForceCloseContext(contextID)
+}
+
+// RestartPoolBrowser 重启浏览器池中的浏览器实例
+func (a *App) RestartPoolBrowser(browserID string) error {
+	return a.browserPool.RestartBrowser(browserID)
+}
+
 // GetBrowserPoolConfig 获取浏览器池配置
 func (a *App) GetBrowserPoolConfig() (types.BrowserPoolConfig, error) {
 	cfg := browser.LoadPoolConfig()
+	modes := make(map[string]string, len(cfg.PlatformStorageModes))
+	for platform, mode := range cfg.PlatformStorageModes {
+		modes[platform] = string(mode)
+	}
+	humanPresets := make(map[string]string, len(cfg.PlatformHumanPresets))
+	for platform, preset := range cfg.PlatformHumanPresets {
+		humanPresets[platform] = string(preset)
+	}
+	remotes := make(map[string]types.RemoteBrowserConfig, len(cfg.PlatformRemotes))
+	for platform, remote := range cfg.PlatformRemotes {
+		if remote != nil {
+			remotes[platform] = *toRemoteBrowserConfig(remote)
+		}
+	}
 	return types.BrowserPoolConfig{
 		MaxBrowsers:           cfg.MaxBrowsers,
 		MaxContextsPerBrowser: cfg.MaxContextsPerBrowser,
@@ -964,11 +1410,24 @@ func (a *App) GetBrowserPoolConfig() (types.BrowserPoolConfig, error) {
 		EnableHealthCheck:     cfg.EnableHealthCheck,
 		HealthCheckInterval:   cfg.HealthCheckInterval,
 		ContextReuseMode:      string(cfg.ContextReuseMode),
+		PlatformStorageModes:  modes,
+		MaxPersistentContexts: cfg.MaxPersistentContexts,
+		FailureTrace:          cfg.FailureTrace,
+		FailureHar:            cfg.FailureHar,
+		DiagnosticsMaxAgeDays: cfg.DiagnosticsMaxAgeDays,
+		DiagnosticsMaxSizeMB:  cfg.DiagnosticsMaxSizeMB,
+		Remote:                toRemoteBrowserConfig(cfg.Remote),
+		PlatformRemotes:       remotes,
+		HumanPreset:           string(cfg.HumanPresetFor("")),
+		PlatformHumanPresets:  humanPresets,
 	}, nil
 }
 
 // SetBrowserPoolConfig 设置浏览器池配置
 func (a *App) SetBrowserPoolConfig(cfg types.BrowserPoolConfig) error {
+	if cfg.DiagnosticsMaxAgeDays < 0 || cfg.DiagnosticsMaxSizeMB < 0 {
+		return fmt.Errorf("诊断记录保留天数和总大小不能为负数")
+	}
 	poolCfg := browser.PoolConfig{
 		MaxBrowsers:           cfg.MaxBrowsers,
 		MaxContextsPerBrowser: cfg.MaxContextsPerBrowser,
@@ -976,6 +1435,61 @@ func (a *App) SetBrowserPoolConfig(cfg types.BrowserPoolConfig) error {
 		EnableHealthCheck:     cfg.EnableHealthCheck,
 		HealthCheckInterval:   cfg.HealthCheckInterval,
 		ContextReuseMode:      browser.ContextReuseMode(cfg.ContextReuseMode),
+		MaxPersistentContexts: cfg.MaxPersistentContexts,
+		FailureTrace:          cfg.FailureTrace,
+		FailureHar:            cfg.FailureHar,
+		DiagnosticsMaxAgeDays: cfg.DiagnosticsMaxAgeDays,
+		DiagnosticsMaxSizeMB:  cfg.DiagnosticsMaxSizeMB,
+	}
+	for platform, mode := range cfg.PlatformStorageModes {
+		switch browser.StorageMode(mode) {
+		case browser.StorageModePersistent:
+			if poolCfg.PlatformStorageModes == nil {
+				poolCfg.PlatformStorageModes = make(map[string]browser.StorageMode)
+			}
+			poolCfg.PlatformStorageModes[platform] = browser.StorageModePersistent
+		case browser.StorageModeState, "":
+		default:
+			return fmt.Errorf("不支持的存储模式: %s", mode)
+		}
+	}
+	if cfg.Remote != nil && cfg.Remote.Endpoint != "" {
+		remote, err := fromRemoteBrowserConfig(*cfg.Remote)
+		if err != nil {
+			return err
+		}
+		poolCfg.Remote = remote
+	}
+	for platform, r := range cfg.PlatformRemotes {
+		if r.Endpoint == "" {
+			continue
+		}
+		remote, err := fromRemoteBrowserConfig(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", platform, err)
+		}
+		if poolCfg.PlatformRemotes == nil {
+			poolCfg.PlatformRemotes = make(map[string]*browser.RemoteBrowser)
+		}
+		poolCfg.PlatformRemotes[platform] = remote
+	}
+	if cfg.HumanPreset != "" {
+		if !browser.HumanPreset(cfg.HumanPreset).Valid() {
+			return fmt.Errorf("不支持的人类交互预设: %s", cfg.HumanPreset)
+		}
+		poolCfg.HumanPreset = browser.HumanPreset(cfg.HumanPreset)
+	}
+	for platform, preset := range cfg.PlatformHumanPresets {
+		if preset == "" {
+			continue
+		}
+		if !browser.HumanPreset(preset).Valid() {
+			return fmt.Errorf("不支持的人类交互预设: %s", preset)
+		}
+		if poolCfg.PlatformHumanPresets == nil {
+			poolCfg.PlatformHumanPresets = make(map[string]browser.HumanPreset)
+		}
+		poolCfg.PlatformHumanPresets[platform] = browser.HumanPreset(preset)
 	}
 
 	if err := browser.SavePoolConfig(&poolCfg); err != nil {
@@ -985,3 +1499,35 @@ func (a *App) SetBrowserPoolConfig(cfg types.BrowserPoolConfig) error {
 	utils.Info(fmt.Sprintf("[+] 浏览器池配置已更新 - 模式: %s", cfg.ContextReuseMode))
 	return nil
 }
+
+// GetHumanPresets 获取人类交互预设列表及各自的耗时/效果说明
+func (a *App) GetHumanPresets() []browser.HumanPresetInfo {
+	return browser.HumanPresets()
+}
+
+// toRemoteBrowserConfig 转换远程浏览器配置供前端使用
+func toRemoteBrowserConfig(remote *browser.RemoteBrowser) *types.RemoteBrowserConfig {
+	if remote == nil {
+		return nil
+	}
+	return &types.RemoteBrowserConfig{
+		Protocol: string(remote.Protocol),
+		Endpoint: remote.Endpoint,
+		Headers:  remote.Headers,
+		Timeout:  remote.Timeout,
+	}
+}
+
+// fromRemoteBrowserConfig 校验并转换前端提交的远程浏览器配置
+func fromRemoteBrowserConfig(cfg types.RemoteBrowserConfig) (*browser.RemoteBrowser, error) {
+	remote := &browser.RemoteBrowser{
+		Protocol: browser.RemoteProtocol(cfg.Protocol),
+		Endpoint: strings.TrimSpace(cfg.Endpoint),
+		Headers:  cfg.Headers,
+		Timeout:  cfg.Timeout,
+	}
+	if err := remote.Validate(); err != nil {
+		return nil, fmt.Errorf("远程浏览器配置无效: %w", err)
+	}
+	return remote, nil
+}
@@ -12,6 +12,10 @@ type AppConfig struct {
 	VideoPath         string
 	LogPath           string
 	ThumbnailPath     string
+	ExportPath        string
+	UserDataPath      string // 持久化浏览器模式下各账号的 Chrome 用户数据目录
+	DiagnosticsPath   string // 上传失败时保留的 Playwright trace / HAR
+	RecordingPath     string // 上传会话录屏的默认保存目录
 	UploadConcurrency int
 	DefaultTimeout    int
 	DebugMode         bool // 调试模式开关
@@ -36,6 +40,10 @@ func Init() error {
 		VideoPath:         filepath.Join(baseDir, DefaultVideoPath),
 		LogPath:           filepath.Join(baseDir, DefaultLogPath),
 		ThumbnailPath:     filepath.Join(baseDir, DefaultThumbnailPath),
+		ExportPath:        filepath.Join(baseDir, DefaultExportPath),
+		UserDataPath:      filepath.Join(baseDir, DefaultUserDataPath),
+		DiagnosticsPath:   filepath.Join(baseDir, DefaultDiagnosticsPath),
+		RecordingPath:     filepath.Join(baseDir, DefaultRecordingPath),
 		UploadConcurrency: UploadConcurrency,
 		DefaultTimeout:    DefaultTimeout,
 		DebugMode:         os.Getenv("FUPLOADER_DEBUG") == "true", // 通过环境变量控制调试模式
@@ -49,6 +57,7 @@ func Init() error {
 		Config.VideoPath,     // videos 目录
 		Config.LogPath,       // logs 目录
 		Config.ThumbnailPath, // thumbnails 目录
+		Config.ExportPath,    // exports 目录
 	}
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 const (
 	AppName    = "Fuploader"
 	AppVersion = "1.0.0"
@@ -37,6 +39,15 @@ const (
 	TaskStatusSuccess   = "success"
 	TaskStatusFailed    = "failed"
 	TaskStatusCancelled = "cancelled"
+
+	TaskStatusRateLimited = "rate_limited" // 触发限流，排队等待最早可发布时间
+)
+
+// 定时发布方式
+const (
+	ScheduleModeAuto   = ""       // 自动：平台支持时使用平台定时，否则本地定时
+	ScheduleModeNative = "native" // 平台定时：立即上传，由平台在定时时间发布
+	ScheduleModeLocal  = "local"  // 本地定时：任务保存在本地，到时间后由 Fuploader 上传并立即发布
 )
 
 const (
@@ -65,13 +76,78 @@ const (
 )
 
 const (
-	DefaultDbPath        = "storage/data.db"
-	DefaultCookiePath    = "storage/cookies"
-	DefaultVideoPath     = "storage/videos"
-	DefaultLogPath       = "storage/logs"
-	DefaultThumbnailPath = "storage/thumbnails"
+	DefaultDbPath          = "storage/data.db"
+	DefaultCookiePath      = "storage/cookies"
+	DefaultVideoPath       = "storage/videos"
+	DefaultLogPath         = "storage/logs"
+	DefaultThumbnailPath   = "storage/thumbnails"
+	DefaultExportPath      = "storage/exports"
+	DefaultUserDataPath    = "storage/userdata"
+	DefaultDiagnosticsPath = "storage/diagnostics"
+	DefaultRecordingPath   = "storage/recordings"
 )
 
+// BrowserTimezone 国内平台浏览器上下文使用的时区
+const BrowserTimezone = "Asia/Shanghai"
+
+// BrowserRegion 浏览器上下文所在地区：语言、时区和地理位置
+type BrowserRegion struct {
+	Locale     string
+	TimezoneID string
+	Latitude   float64
+	Longitude  float64
+}
+
+// defaultBrowserRegion 国内平台使用的地区（北京）
+var defaultBrowserRegion = BrowserRegion{Locale: "zh-CN", TimezoneID: BrowserTimezone, Latitude: 39.9042, Longitude: 116.4074}
+
+// platformBrowserRegions 面向海外的平台使用的地区
+var platformBrowserRegions = map[string]BrowserRegion{
+	PlatformTiktok: {Locale: "en-GB", TimezoneID: "Europe/London", Latitude: 51.5074, Longitude: -0.1278},
+}
+
+// PlatformBrowserRegion 平台浏览器上下文的默认地区，账号未设置时区时使用
+func PlatformBrowserRegion(platform string) BrowserRegion {
+	if region, ok := platformBrowserRegions[platform]; ok {
+		return region
+	}
+	return defaultBrowserRegion
+}
+
+// timeZoneCoordinates 常见时区对应的地理位置（取该时区的主要城市）
+var timeZoneCoordinates = map[string][2]float64{
+	"Asia/Shanghai":       {39.9042, 116.4074},
+	"Asia/Hong_Kong":      {22.3193, 114.1694},
+	"Asia/Taipei":         {25.0330, 121.5654},
+	"Asia/Singapore":      {1.3521, 103.8198},
+	"Asia/Tokyo":          {35.6762, 139.6503},
+	"Asia/Seoul":          {37.5665, 126.9780},
+	"Europe/London":       {51.5074, -0.1278},
+	"Europe/Paris":        {48.8566, 2.3522},
+	"Europe/Berlin":       {52.5200, 13.4050},
+	"America/New_York":    {40.7128, -74.0060},
+	"America/Chicago":     {41.8781, -87.6298},
+	"America/Denver":      {39.7392, -104.9903},
+	"America/Los_Angeles": {34.0522, -118.2437},
+	"Australia/Sydney":    {-33.8688, 151.2093},
+}
+
+// AccountBrowserRegion 账号浏览器上下文的地区：时区和地理位置取自账号时区，语言保持平台默认（上传流程按页面语言定位元素）
+// 账号未设置时区或时区无效时使用平台默认地区；时区不在 timeZoneCoordinates 中时经纬度为 0，表示不设置地理位置
+func AccountBrowserRegion(platform, timeZone string) BrowserRegion {
+	region := PlatformBrowserRegion(platform)
+	if timeZone == "" || timeZone == "Local" || timeZone == region.TimezoneID {
+		return region
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return region
+	}
+	region.TimezoneID = timeZone
+	coordinates := timeZoneCoordinates[timeZone]
+	region.Latitude, region.Longitude = coordinates[0], coordinates[1]
+	return region
+}
+
 const (
 	MaxUploadRetry    = 3
 	DefaultTimeout    = 30
@@ -0,0 +1,19 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccountFingerprint 账号的固定浏览器指纹档案（JSON），首次为账号创建浏览器上下文时生成
+type AccountFingerprint struct {
+	AccountID int    `json:"accountId" gorm:"primaryKey;autoIncrement:false"`
+	Profile   string `json:"profile" gorm:"type:text;not null"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func (f *AccountFingerprint) BeforeCreate(tx *gorm.DB) (err error) {
+	f.CreatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
@@ -0,0 +1,21 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccountRateLimit 账号级限额（覆盖平台限额之外的单账号限制），0 表示不限制
+type AccountRateLimit struct {
+	AccountID         int    `json:"accountId" gorm:"primaryKey;autoIncrement:false"`
+	DailyLimit        int    `json:"dailyLimit"`        // 滚动 24 小时内最多发布数
+	HourlyLimit       int    `json:"hourlyLimit"`       // 滚动 1 小时内最多发布数
+	MinSpacingMinutes int    `json:"minSpacingMinutes"` // 两次发布之间的最小间隔（分钟）
+	UpdatedAt         string `json:"updatedAt"`
+}
+
+func (l *AccountRateLimit) BeforeSave(tx *gorm.DB) (err error) {
+	l.UpdatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
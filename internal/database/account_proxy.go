@@ -0,0 +1,48 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 代理类型
+const (
+	ProxyTypeHTTP   = "http"
+	ProxyTypeHTTPS  = "https"
+	ProxyTypeSOCKS5 = "socks5"
+)
+
+// AccountProxy 账号绑定的出口代理，创建该账号的浏览器上下文时使用
+type AccountProxy struct {
+	AccountID int    `json:"accountId" gorm:"primaryKey;autoIncrement:false"`
+	Enabled   bool   `json:"enabled"`
+	Type      string `json:"type"` // http/https/socks5
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	Username  string `json:"username"`
+	// Password 代理密码，数据库中保存凭据库加密后的密文；读取配置时不返回（见 HasPassword），设置时为空表示保留原密码
+	Password  string `json:"password,omitempty"`
+	UpdatedAt string `json:"updatedAt"`
+
+	HasPassword   bool `json:"hasPassword" gorm:"-"`             // 是否已设置密码
+	ClearPassword bool `json:"clearPassword,omitempty" gorm:"-"` // 设置代理时清除已保存的密码
+
+	// 最近一次健康检查结果
+	LastIP        string `json:"lastIp"`
+	LastCheckedAt string `json:"lastCheckedAt"`
+	LastError     string `json:"lastError"`
+}
+
+func (p *AccountProxy) BeforeSave(tx *gorm.DB) (err error) {
+	p.UpdatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
+
+// Server 代理地址，如 socks5://127.0.0.1:1080
+func (p *AccountProxy) Server() string {
+	return fmt.Sprintf("%s://%s", p.Type, net.JoinHostPort(p.Host, strconv.Itoa(p.Port)))
+}
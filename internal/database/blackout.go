@@ -0,0 +1,78 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 禁发时段作用范围
+const (
+	BlackoutScopeGlobal   = "global"   // 所有账号
+	BlackoutScopePlatform = "platform" // 指定平台
+	BlackoutScopeAccount  = "account"  // 指定账号
+)
+
+// 禁发时段类型
+const (
+	BlackoutTypeRange = "range" // 一次性时间段（如公司活动、敏感日期）
+	BlackoutTypeDaily = "daily" // 每日静默时段（如非营业时间），可跨零点
+)
+
+// 命中禁发时段时的处理策略
+const (
+	BlackoutPolicyShift = "shift" // 顺延到禁发时段结束后
+	BlackoutPolicyBlock = "block" // 阻止发布
+)
+
+// BlackoutWindow 禁发时段
+type BlackoutWindow struct {
+	ID        int    `json:"id" gorm:"primaryKey"`
+	Name      string `json:"name" gorm:"not null"`
+	Enabled   bool   `json:"enabled"`
+	Scope     string `json:"scope" gorm:"index"`
+	Platform  string `json:"platform"`  // Scope 为 platform 时有效
+	AccountID int    `json:"accountId"` // Scope 为 account 时有效
+	Type      string `json:"type"`
+	Policy    string `json:"policy"`
+	TimeZone  string `json:"timeZone"` // 为空时使用账号时区（全局检查时使用发布配置的时区）
+
+	StartAt      string `json:"startAt"`           // range：开始时间，格式 2006-01-02 15:04
+	EndAt        string `json:"endAt"`             // range：结束时间
+	StartTime    string `json:"startTime"`         // daily：开始时间，格式 HH:MM
+	EndTime      string `json:"endTime"`           // daily：结束时间，早于开始时间表示跨零点
+	Weekdays     []int  `json:"weekdays" gorm:"-"` // daily：生效的星期（按开始时间所在日），为空表示每天
+	WeekdaysJSON string `json:"-" gorm:"column:weekdays"`
+
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+func (b *BlackoutWindow) BeforeCreate(tx *gorm.DB) (err error) {
+	now := time.Now().Format(time.RFC3339)
+	b.CreatedAt = now
+	b.UpdatedAt = now
+	return nil
+}
+
+func (b *BlackoutWindow) BeforeUpdate(tx *gorm.DB) (err error) {
+	b.UpdatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
+
+func (b *BlackoutWindow) BeforeSave(tx *gorm.DB) (err error) {
+	data, err := json.Marshal(b.Weekdays)
+	if err != nil {
+		return err
+	}
+	b.WeekdaysJSON = string(data)
+	return nil
+}
+
+func (b *BlackoutWindow) AfterFind(tx *gorm.DB) (err error) {
+	if b.WeekdaysJSON != "" {
+		json.Unmarshal([]byte(b.WeekdaysJSON), &b.Weekdays)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 队列条目状态
+const (
+	QueueItemPending   = "pending"   // 等待发布
+	QueueItemClaimed   = "claimed"   // 已取出，正在创建上传任务
+	QueueItemScheduled = "scheduled" // 已创建定时上传任务
+	QueueItemPublished = "published" // 已取出并创建上传任务
+	QueueItemFailed    = "failed"    // 创建上传任务失败
+)
+
+// ContentQueue 内容队列：按顺序存放待发布视频，由发布时段逐个消费
+type ContentQueue struct {
+	ID             int    `json:"id" gorm:"primaryKey"`
+	Name           string `json:"name" gorm:"uniqueIndex;not null"`
+	Description    string `json:"description"`
+	AccountIDs     []int  `json:"accountIds" gorm:"-"` // 目标账号
+	AccountIDsJSON string `json:"-" gorm:"column:account_ids"`
+	PresetID       int    `json:"presetId"` // 使用的发布预设，0 表示使用账号默认预设
+	Paused         bool   `json:"paused"`   // 暂停后发布时段不再从队列取视频
+	CreatedAt      string `json:"createdAt"`
+	UpdatedAt      string `json:"updatedAt"`
+}
+
+func (q *ContentQueue) BeforeCreate(tx *gorm.DB) (err error) {
+	now := time.Now().Format(time.RFC3339)
+	q.CreatedAt = now
+	q.UpdatedAt = now
+	return nil
+}
+
+func (q *ContentQueue) BeforeUpdate(tx *gorm.DB) (err error) {
+	q.UpdatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
+
+func (q *ContentQueue) BeforeSave(tx *gorm.DB) (err error) {
+	data, err := json.Marshal(q.AccountIDs)
+	if err != nil {
+		return err
+	}
+	q.AccountIDsJSON = string(data)
+	return nil
+}
+
+func (q *ContentQueue) AfterFind(tx *gorm.DB) (err error) {
+	if q.AccountIDsJSON != "" {
+		json.Unmarshal([]byte(q.AccountIDsJSON), &q.AccountIDs)
+	}
+	return nil
+}
+
+// QueueItem 队列条目
+type QueueItem struct {
+	ID          int        `json:"id" gorm:"primaryKey"`
+	QueueID     int        `json:"queueId" gorm:"index"`
+	VideoID     int        `json:"videoId" gorm:"index"`
+	Video       *Video     `json:"video,omitempty" gorm:"foreignKey:VideoID"`
+	Position    int        `json:"position" gorm:"index"` // 排序位置，越小越先发布
+	Status      string     `json:"status" gorm:"index"`
+	TaskIDs     []int      `json:"taskIds" gorm:"-"`
+	TaskIDsJSON string     `json:"-" gorm:"column:task_ids"`
+	Message     string     `json:"message"`
+	ConsumedAt  *time.Time `json:"consumedAt"`
+	CreatedAt   string     `json:"createdAt"`
+}
+
+func (i *QueueItem) BeforeCreate(tx *gorm.DB) (err error) {
+	i.CreatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
+
+func (i *QueueItem) BeforeSave(tx *gorm.DB) (err error) {
+	data, err := json.Marshal(i.TaskIDs)
+	if err != nil {
+		return err
+	}
+	i.TaskIDsJSON = string(data)
+	return nil
+}
+
+func (i *QueueItem) AfterFind(tx *gorm.DB) (err error) {
+	if i.TaskIDsJSON != "" {
+		json.Unmarshal([]byte(i.TaskIDsJSON), &i.TaskIDs)
+	}
+	return nil
+}
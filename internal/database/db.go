@@ -28,17 +28,29 @@ func Init() error {
 	}
 
 	DB = db
-	return migrate()
+	return Migrate(db)
 }
 
-func migrate() error {
-	return DB.AutoMigrate(
+// Migrate 创建或更新所有数据表
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(
 		&Account{},
 		&Video{},
 		&UploadTask{},
 		&ScheduleConfig{},
 		&ScheduledTask{},
 		&UploadLog{},
+		&TagSet{},
+		&PublishPreset{},
+		&RecurringSchedule{},
+		&ScheduleSlot{},
+		&ContentQueue{},
+		&QueueItem{},
+		&BlackoutWindow{},
+		&PublishRecord{},
+		&AccountRateLimit{},
+		&AccountFingerprint{},
+		&AccountProxy{},
 	)
 }
 
@@ -17,6 +17,9 @@ type Account struct {
 	Status     int    `json:"status" gorm:"default:0"`
 	CreatedAt  string `json:"createdAt"`
 	UpdatedAt  string `json:"updatedAt"`
+
+	DefaultPresetID int    `json:"defaultPresetId"` // 默认发布预设，0 表示不使用
+	TimeZone        string `json:"timeZone"`        // 账号时区（IANA 名称），为空时使用发布配置的时区
 }
 
 func (a *Account) BeforeCreate(tx *gorm.DB) (err error) {
@@ -45,6 +48,10 @@ type Video struct {
 	TagsJSON    string   `json:"-" gorm:"column:tags"`
 	Thumbnail   string   `json:"thumbnail"`
 	CreatedAt   string   `json:"createdAt"`
+
+	// 自定义字段（如系列名、集数），可在标题/描述模板中以 {字段名} 引用
+	CustomFields     map[string]string `json:"customFields" gorm:"-"`
+	CustomFieldsJSON string            `json:"-" gorm:"column:custom_fields"`
 }
 
 func (v *Video) BeforeCreate(tx *gorm.DB) (err error) {
@@ -60,14 +67,33 @@ func (v *Video) AfterFind(tx *gorm.DB) (err error) {
 	if v.TagsJSON != "" {
 		json.Unmarshal([]byte(v.TagsJSON), &v.Tags)
 	}
+	if v.CustomFieldsJSON != "" {
+		json.Unmarshal([]byte(v.CustomFieldsJSON), &v.CustomFields)
+	}
 	return nil
 }
 
+// BeforeSave 标签和自定义字段总是重新写入，全部删除时分别保存为空数组和空对象
+// 只更新部分列时（如 FileService.UpdateVideo 不写自定义字段）由调用方通过 Omit/Select 控制
 func (v *Video) BeforeSave(tx *gorm.DB) (err error) {
-	if len(v.Tags) > 0 {
-		data, _ := json.Marshal(v.Tags)
-		v.TagsJSON = string(data)
+	tags := v.Tags
+	if tags == nil {
+		tags = []string{}
 	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+	v.TagsJSON = string(data)
+
+	fields := v.CustomFields
+	if fields == nil {
+		fields = map[string]string{}
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return err
+	}
+	v.CustomFieldsJSON = string(data)
 	return nil
 }
 
@@ -87,8 +113,20 @@ type UploadTask struct {
 	CreatedAt    string  `json:"createdAt"`
 	UpdatedAt    string  `json:"updatedAt"`
 
+	// 标签：按平台展开后的标签（为空时使用视频标签）
+	Tags     []string `json:"tags" gorm:"-"`
+	TagsJSON string   `json:"-" gorm:"column:tags"`
+	TagSetID int      `json:"tagSetId"` // 使用的标签组
+	PresetID int      `json:"presetId"` // 使用的发布预设
+
+	ScheduleMode string `json:"scheduleMode"` // 定时方式：native（平台定时）/local（本地定时）
+	DeferReason  string `json:"deferReason"`  // 因限流/账号限额/禁发时段推迟的原因
+
+	RecordingPath string `json:"recordingPath"` // 最近一次上传会话的录屏
+
 	// 平台特定字段
 	Title               string `json:"title"`               // 用户自定义标题（覆盖视频标题）
+	Description         string `json:"description"`         // 用户自定义描述（覆盖视频描述）
 	Collection          string `json:"collection"`          // 视频号合集名称
 	ShortTitle          string `json:"shortTitle"`          // 视频号短标题
 	IsOriginal          bool   `json:"isOriginal"`          // 是否声明原创
@@ -123,6 +161,21 @@ func (t *UploadTask) BeforeUpdate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+func (t *UploadTask) BeforeSave(tx *gorm.DB) (err error) {
+	if len(t.Tags) > 0 {
+		data, _ := json.Marshal(t.Tags)
+		t.TagsJSON = string(data)
+	}
+	return nil
+}
+
+func (t *UploadTask) AfterFind(tx *gorm.DB) (err error) {
+	if t.TagsJSON != "" {
+		json.Unmarshal([]byte(t.TagsJSON), &t.Tags)
+	}
+	return nil
+}
+
 type ScheduleConfig struct {
 	ID             int      `json:"id" gorm:"primaryKey"`
 	VideosPerDay   int      `json:"videosPerDay" gorm:"default:1"`
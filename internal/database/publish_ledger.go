@@ -0,0 +1,24 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PublishRecord 发布台账：每次实际发布记录一条，用于按平台/账号统计滚动窗口内的发布次数
+type PublishRecord struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	TaskID      int       `json:"taskId" gorm:"index"`
+	AccountID   int       `json:"accountId" gorm:"index"`
+	Platform    string    `json:"platform" gorm:"index;not null"`
+	Scheduled   bool      `json:"scheduled"`                // 平台定时发布
+	PublishedAt time.Time `json:"publishedAt" gorm:"index"` // 发布时间（平台定时为定时时间），UTC
+	CreatedAt   string    `json:"createdAt"`
+}
+
+func (r *PublishRecord) BeforeCreate(tx *gorm.DB) (err error) {
+	r.CreatedAt = time.Now().Format(time.RFC3339)
+	r.PublishedAt = r.PublishedAt.UTC()
+	return nil
+}
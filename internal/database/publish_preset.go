@@ -0,0 +1,49 @@
+package database
+
+import (
+	"Fuploader/internal/types"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PublishPreset 发布预设：保存一套完整的上传元数据（原创声明、分类、合集、同步选项等）
+type PublishPreset struct {
+	ID           int                      `json:"id" gorm:"primaryKey"`
+	Name         string                   `json:"name" gorm:"uniqueIndex;not null"`
+	Platform     string                   `json:"platform" gorm:"index"` // 适用平台，为空表示通用
+	Description  string                   `json:"description"`
+	Metadata     types.UploadTaskMetadata `json:"metadata" gorm:"-"`
+	MetadataJSON string                   `json:"-" gorm:"column:metadata;type:text"`
+	CreatedAt    string                   `json:"createdAt"`
+	UpdatedAt    string                   `json:"updatedAt"`
+}
+
+func (p *PublishPreset) BeforeCreate(tx *gorm.DB) (err error) {
+	now := time.Now().Format(time.RFC3339)
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	return nil
+}
+
+func (p *PublishPreset) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.UpdatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
+
+func (p *PublishPreset) BeforeSave(tx *gorm.DB) (err error) {
+	data, err := json.Marshal(p.Metadata)
+	if err != nil {
+		return err
+	}
+	p.MetadataJSON = string(data)
+	return nil
+}
+
+func (p *PublishPreset) AfterFind(tx *gorm.DB) (err error) {
+	if p.MetadataJSON != "" {
+		json.Unmarshal([]byte(p.MetadataJSON), &p.Metadata)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 发布时段状态
+const (
+	SlotStatusPublished = "published" // 已取出视频并创建上传任务
+	SlotStatusEmpty     = "empty"     // 播放列表已用完
+	SlotStatusMissed    = "missed"    // 应用未运行，时段已错过
+	SlotStatusFailed    = "failed"    // 创建上传任务失败
+	SlotStatusPaused    = "paused"    // 内容队列已暂停，时段未消费
+)
+
+// WeekdayRule 按星期/时间的发布规则
+type WeekdayRule struct {
+	Weekdays []int    `json:"weekdays"` // 0=周日 ... 6=周六，为空表示每天
+	Times    []string `json:"times"`    // 发布时间，格式 HH:MM
+}
+
+// RecurringSchedule 周期性发布计划
+// 使用 cron 表达式或星期/时间规则生成发布时段，每个时段从播放列表中取出下一个视频发布到指定账号
+type RecurringSchedule struct {
+	ID       int    `json:"id" gorm:"primaryKey"`
+	Name     string `json:"name" gorm:"uniqueIndex;not null"`
+	Enabled  bool   `json:"enabled"`
+	TimeZone string `json:"timeZone"` // 为空时使用全局发布配置的时区
+
+	CronExpr         string        `json:"cronExpr"` // cron 表达式，为空时使用 Rules
+	Rules            []WeekdayRule `json:"rules" gorm:"-"`
+	RulesJSON        string        `json:"-" gorm:"column:rules;type:text"`
+	ExcludeDates     []string      `json:"excludeDates" gorm:"-"` // 排除日期（如节假日），格式 2006-01-02
+	ExcludeDatesJSON string        `json:"-" gorm:"column:exclude_dates;type:text"`
+
+	AccountIDs     []int  `json:"accountIds" gorm:"-"`
+	AccountIDsJSON string `json:"-" gorm:"column:account_ids"`
+	VideoIDs       []int  `json:"videoIds" gorm:"-"` // 播放列表（按顺序发布）
+	VideoIDsJSON   string `json:"-" gorm:"column:video_ids;type:text"`
+	NextVideoIndex int    `json:"nextVideoIndex"` // 下一个要发布的视频在播放列表中的位置
+	Loop           bool   `json:"loop"`           // 播放列表用完后从头开始
+	PresetID       int    `json:"presetId"`       // 使用的发布预设，0 表示使用账号默认预设
+	QueueID        int    `json:"queueId"`        // 内容队列，设置后从队列取视频（账号/预设为空时使用队列的配置）
+
+	NextRunAt *time.Time `json:"nextRunAt"`
+	LastRunAt *time.Time `json:"lastRunAt"`
+	CreatedAt string     `json:"createdAt"`
+	UpdatedAt string     `json:"updatedAt"`
+}
+
+func (r *RecurringSchedule) BeforeCreate(tx *gorm.DB) (err error) {
+	now := time.Now().Format(time.RFC3339)
+	r.CreatedAt = now
+	r.UpdatedAt = now
+	return nil
+}
+
+func (r *RecurringSchedule) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.UpdatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
+
+func (r *RecurringSchedule) BeforeSave(tx *gorm.DB) (err error) {
+	fields := []struct {
+		value  interface{}
+		target *string
+	}{
+		{r.Rules, &r.RulesJSON},
+		{r.ExcludeDates, &r.ExcludeDatesJSON},
+		{r.AccountIDs, &r.AccountIDsJSON},
+		{r.VideoIDs, &r.VideoIDsJSON},
+	}
+	for _, f := range fields {
+		data, err := json.Marshal(f.value)
+		if err != nil {
+			return err
+		}
+		*f.target = string(data)
+	}
+	return nil
+}
+
+func (r *RecurringSchedule) AfterFind(tx *gorm.DB) (err error) {
+	if r.RulesJSON != "" {
+		json.Unmarshal([]byte(r.RulesJSON), &r.Rules)
+	}
+	if r.ExcludeDatesJSON != "" {
+		json.Unmarshal([]byte(r.ExcludeDatesJSON), &r.ExcludeDates)
+	}
+	if r.AccountIDsJSON != "" {
+		json.Unmarshal([]byte(r.AccountIDsJSON), &r.AccountIDs)
+	}
+	if r.VideoIDsJSON != "" {
+		json.Unmarshal([]byte(r.VideoIDsJSON), &r.VideoIDs)
+	}
+	return nil
+}
+
+// ScheduleSlot 发布时段消费记录
+type ScheduleSlot struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	ScheduleID  int       `json:"scheduleId" gorm:"index"`
+	SlotTime    time.Time `json:"slotTime" gorm:"index"`
+	VideoID     int       `json:"videoId"`
+	Status      string    `json:"status"`
+	TaskIDs     []int     `json:"taskIds" gorm:"-"`
+	TaskIDsJSON string    `json:"-" gorm:"column:task_ids"`
+	Message     string    `json:"message"`
+	CreatedAt   string    `json:"createdAt"`
+}
+
+func (s *ScheduleSlot) BeforeCreate(tx *gorm.DB) (err error) {
+	s.CreatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
+
+func (s *ScheduleSlot) BeforeSave(tx *gorm.DB) (err error) {
+	data, err := json.Marshal(s.TaskIDs)
+	if err != nil {
+		return err
+	}
+	s.TaskIDsJSON = string(data)
+	return nil
+}
+
+func (s *ScheduleSlot) AfterFind(tx *gorm.DB) (err error) {
+	if s.TaskIDsJSON != "" {
+		json.Unmarshal([]byte(s.TaskIDsJSON), &s.TaskIDs)
+	}
+	return nil
+}
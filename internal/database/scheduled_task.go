@@ -45,6 +45,9 @@ type ScheduledTask struct {
 	CompletedAt  *time.Time   `json:"completed_at"`
 	CreatedAt    time.Time    `json:"created_at"`
 	UpdatedAt    time.Time    `json:"updated_at"`
+
+	// UploadTaskID 关联的上传任务（本地定时），到时间后交给上传任务执行器执行
+	UploadTaskID int `json:"upload_task_id" gorm:"index"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TagEntry 标签条目
+// Aliases 为各平台的别名（如抖音话题、B站标签、TikTok 英文标签），
+// 别名为 "-" 表示该平台不使用此标签，未配置时使用 Name
+type TagEntry struct {
+	Name    string            `json:"name"`
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// TagSet 标签组
+type TagSet struct {
+	ID          int        `json:"id" gorm:"primaryKey"`
+	Name        string     `json:"name" gorm:"uniqueIndex;not null"`
+	Description string     `json:"description"`
+	Entries     []TagEntry `json:"entries" gorm:"-"`
+	EntriesJSON string     `json:"-" gorm:"column:entries;type:text"`
+	UsageCount  int        `json:"usageCount" gorm:"default:0"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	CreatedAt   string     `json:"createdAt"`
+	UpdatedAt   string     `json:"updatedAt"`
+}
+
+func (t *TagSet) BeforeCreate(tx *gorm.DB) (err error) {
+	now := time.Now().Format(time.RFC3339)
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	return nil
+}
+
+func (t *TagSet) BeforeUpdate(tx *gorm.DB) (err error) {
+	t.UpdatedAt = time.Now().Format(time.RFC3339)
+	return nil
+}
+
+func (t *TagSet) BeforeSave(tx *gorm.DB) (err error) {
+	data, err := json.Marshal(t.Entries)
+	if err != nil {
+		return err
+	}
+	t.EntriesJSON = string(data)
+	return nil
+}
+
+func (t *TagSet) AfterFind(tx *gorm.DB) (err error) {
+	if t.EntriesJSON != "" {
+		json.Unmarshal([]byte(t.EntriesJSON), &t.Entries)
+	}
+	return nil
+}
@@ -25,6 +25,10 @@ type UploadLog struct {
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 失败诊断记录（开启 trace / HAR 采集且上传失败时保存）
+	TracePath string `gorm:"size:500" json:"trace_path"` // Playwright trace，可用 playwright show-trace 打开
+	HarPath   string `gorm:"size:500" json:"har_path"`   // 网络请求 HAR
 }
 
 // UploadLogQuery 上传日志查询条件
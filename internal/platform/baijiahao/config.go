@@ -8,8 +8,6 @@ type Config struct {
 	ElementWaitTimeout   time.Duration
 	SubmitCheckTimeout   time.Duration
 	MaxPublishRetries    int
-	TitleMinLength       int
-	TitleMaxLength       int
 	UploadCheckInterval  time.Duration
 }
 
@@ -19,8 +17,6 @@ var defaultConfig = Config{
 	ElementWaitTimeout:   5 * time.Second,
 	SubmitCheckTimeout:   60 * time.Second,
 	MaxPublishRetries:    3,
-	TitleMinLength:       8,
-	TitleMaxLength:       30,
 	UploadCheckInterval:  2 * time.Second,
 }
 
@@ -24,7 +24,7 @@ func (u *Uploader) setCustomCover(page playwright.Page, coverPath string) error
 		return fmt.Errorf("失败: 设置封面 - 未找到封面区域: %w", err)
 	}
 
-	if err := coverArea.Click(); err != nil {
+	if err := human.Click(coverArea); err != nil {
 		return fmt.Errorf("失败: 设置封面 - 点击封面区域失败: %w", err)
 	}
 	time.Sleep(2 * time.Second)
@@ -45,7 +45,7 @@ func (u *Uploader) setCustomCover(page playwright.Page, coverPath string) error
 
 	confirmBtn := page.Locator("button:has-text('确认'), button:has-text('完成'), button:has-text('确定')").First()
 	if count, _ := confirmBtn.Count(); count > 0 {
-		if err := confirmBtn.Click(); err != nil {
+		if err := human.Click(confirmBtn); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 设置封面 - 点击确认按钮失败: %v", err))
 		}
 		time.Sleep(1 * time.Second)
@@ -32,7 +32,7 @@ func (u *Uploader) publish(page playwright.Page, browserCtx *browser.PooledConte
 
 		utils.InfoWithPlatform(u.platform, fmt.Sprintf("第%d次尝试发布...", attempt+1))
 
-		if err := publishBtn.Click(playwright.LocatorClickOptions{
+		if err := human.Click(publishBtn, playwright.LocatorClickOptions{
 			Force: playwright.Bool(true),
 		}); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 发布 - 点击按钮失败: %v", err))
@@ -47,7 +47,7 @@ func (u *Uploader) publish(page playwright.Page, browserCtx *browser.PooledConte
 		if count, _ := confirmDialog.Count(); count > 0 {
 			if visible, _ := confirmDialog.IsVisible(); visible {
 				utils.InfoWithPlatform(u.platform, "处理确认弹窗...")
-				confirmDialog.Click()
+				human.Click(confirmDialog)
 				time.Sleep(2 * time.Second)
 			}
 		}
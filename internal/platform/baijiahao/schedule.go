@@ -17,27 +17,27 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		return fmt.Errorf("失败: 设置定时发布 - 解析时间失败: %w", err)
 	}
 
-	page.Locator(`span:has-text("定时发布")`).First().Click()
+	human.Click(page.Locator(`span:has-text("定时发布")`).First())
 	time.Sleep(1 * time.Second)
 
 	monthDay := fmt.Sprintf("%d月%d日", targetTime.Month(), targetTime.Day())
 	hour := fmt.Sprintf("%d", targetTime.Hour())
 	minute := fmt.Sprintf("%d", targetTime.Minute())
 
-	page.Locator(fmt.Sprintf(`div:has-text("选择日期") + div span.cheetah-select-selection-item[title="%s"]`, monthDay)).First().Click()
+	human.Click(page.Locator(fmt.Sprintf(`div:has-text("选择日期") + div span.cheetah-select-selection-item[title="%s"]`, monthDay)).First())
 	time.Sleep(500 * time.Millisecond)
 
-	page.Locator(fmt.Sprintf(`div:has-text("小时") ~ div span:has-text("%s")`, hour)).First().Click()
+	human.Click(page.Locator(fmt.Sprintf(`div:has-text("小时") ~ div span:has-text("%s")`, hour)).First())
 	time.Sleep(300 * time.Millisecond)
-	page.Locator(`div:has-text("小时") ~ div span:has-text("点")`).First().Click()
+	human.Click(page.Locator(`div:has-text("小时") ~ div span:has-text("点")`).First())
 	time.Sleep(300 * time.Millisecond)
 
-	page.Locator(fmt.Sprintf(`div:has-text("分钟") ~ div span:has-text("%s")`, minute)).First().Click()
+	human.Click(page.Locator(fmt.Sprintf(`div:has-text("分钟") ~ div span:has-text("%s")`, minute)).First())
 	time.Sleep(300 * time.Millisecond)
-	page.Locator(`div:has-text("分钟") ~ div span:has-text("分")`).First().Click()
+	human.Click(page.Locator(`div:has-text("分钟") ~ div span:has-text("分")`).First())
 	time.Sleep(300 * time.Millisecond)
 
-	page.Locator(`button:has-text("定时发布")`).First().Click()
+	human.Click(page.Locator(`button:has-text("定时发布")`).First())
 
 	utils.InfoWithPlatform(u.platform, "定时发布设置完成")
 	time.Sleep(1 * time.Second)
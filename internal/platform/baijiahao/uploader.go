@@ -9,24 +9,22 @@ import (
 
 	"Fuploader/internal/config"
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/types"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
 )
 
+// human 上传页面的点击和输入，按浏览器池配置的 baijiahao 人类交互预设执行
+var human = browser.HumanFor("baijiahao")
+
 func debugLog(format string, args ...interface{}) {
 	if config.Config != nil && config.Config.DebugMode {
 		utils.InfoWithPlatform("baijiahao", fmt.Sprintf("[调试] "+format, args...))
 	}
 }
 
-var defaultPool *browser.Pool
-
-func init() {
-	defaultPool = browser.NewPoolFromConfig()
-}
-
 type Uploader struct {
 	accountID   uint
 	cookiePath  string
@@ -63,8 +61,10 @@ func NewUploaderWithAccount(accountID uint) *Uploader {
 	return u
 }
 
-func NewUploaderWithPool(accountID uint, pool *browser.Pool) *Uploader {
-	cookiePath := config.GetCookiePath("baijiahao", int(accountID))
+func NewUploaderWithPool(accountID uint, cookiePath string, pool *browser.Pool) *Uploader {
+	if cookiePath == "" {
+		cookiePath = config.GetCookiePath("baijiahao", int(accountID))
+	}
 	u := &Uploader{
 		accountID:   accountID,
 		cookiePath:  cookiePath,
@@ -96,7 +96,7 @@ func (u *Uploader) getBrowserPool() *browser.Pool {
 	if u.browserPool != nil {
 		return u.browserPool
 	}
-	return defaultPool
+	return browser.GetDefaultPool()
 }
 
 func (u *Uploader) Upload(ctx context.Context, task *types.VideoTask) error {
@@ -187,12 +187,7 @@ func (u *Uploader) fillTitle(page playwright.Page, title string) error {
 
 	utils.InfoWithPlatform(u.platform, "填写标题...")
 
-	if StringRuneLen(title) < u.config.TitleMinLength {
-		title = title + " 你不知道的"
-		utils.InfoWithPlatform(u.platform, fmt.Sprintf("标题少于%d字符，自动补全为: %s", u.config.TitleMinLength, title))
-	}
-
-	title = TruncateString(title, u.config.TitleMaxLength)
+	title = platformutils.ApplyTextRule(u.platform, platformutils.FieldTitle, title)
 
 	titleInput := page.Locator(`div[contenteditable="true"][aria-placeholder="添加标题获得更多推荐"]`).First()
 	if err := titleInput.WaitFor(playwright.LocatorWaitForOptions{
@@ -201,7 +196,7 @@ func (u *Uploader) fillTitle(page playwright.Page, title string) error {
 		return fmt.Errorf("失败: 填写标题 - 未找到输入框: %w", err)
 	}
 
-	if err := titleInput.Fill(title); err != nil {
+	if err := human.Fill(titleInput, title); err != nil {
 		return fmt.Errorf("失败: 填写标题 - %w", err)
 	}
 
@@ -224,7 +219,7 @@ func (u *Uploader) fillDescription(page playwright.Page, description string) err
 		return fmt.Errorf("失败: 填写描述 - 未找到输入框: %w", err)
 	}
 
-	if err := descInput.Fill(description); err != nil {
+	if err := human.Fill(descInput, description); err != nil {
 		return fmt.Errorf("失败: 填写描述 - %w", err)
 	}
 
@@ -256,7 +251,7 @@ func (u *Uploader) addTags(page playwright.Page, tags []string) error {
 			continue
 		}
 
-		if err := tagInput.Fill(cleanTag); err != nil {
+		if err := human.Fill(tagInput, cleanTag); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 添加标签 - 输入标签[%d]失败: %v", i, err))
 			continue
 		}
@@ -287,7 +282,7 @@ func (u *Uploader) selectCategory(page playwright.Page, category string) error {
 		return fmt.Errorf("失败: 选择内容分类 - 未找到选择器: %w", err)
 	}
 
-	if err := categoryInput.Click(); err != nil {
+	if err := human.Click(categoryInput); err != nil {
 		return fmt.Errorf("失败: 选择内容分类 - 点击选择器失败: %w", err)
 	}
 	time.Sleep(1 * time.Second)
@@ -299,7 +294,7 @@ func (u *Uploader) selectCategory(page playwright.Page, category string) error {
 		return fmt.Errorf("失败: 选择内容分类 - 未找到选项: %w", err)
 	}
 
-	if err := categoryOption.Click(); err != nil {
+	if err := human.Click(categoryOption); err != nil {
 		return fmt.Errorf("失败: 选择内容分类 - 点击选项失败: %w", err)
 	}
 
@@ -318,7 +313,7 @@ func (u *Uploader) setAICreation(page playwright.Page) error {
 		return fmt.Errorf("失败: 勾选AI创作声明 - 未找到选项: %w", err)
 	}
 
-	if err := aiCheckbox.Click(); err != nil {
+	if err := human.Click(aiCheckbox); err != nil {
 		return fmt.Errorf("失败: 勾选AI创作声明 - %w", err)
 	}
 
@@ -337,7 +332,7 @@ func (u *Uploader) setAutoAudio(page playwright.Page) error {
 		return fmt.Errorf("失败: 勾选自动生成音频 - 未找到选项: %w", err)
 	}
 
-	if err := audioCheckbox.Click(); err != nil {
+	if err := human.Click(audioCheckbox); err != nil {
 		return fmt.Errorf("失败: 勾选自动生成音频 - %w", err)
 	}
 
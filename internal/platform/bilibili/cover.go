@@ -23,7 +23,7 @@ func (u *Uploader) setCover(page playwright.Page, thumbnail string) (bool, error
 		utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 设置封面 - 滚动到封面区域失败: %v", err))
 	}
 
-	if err := coverMain.Click(playwright.LocatorClickOptions{Force: playwright.Bool(true)}); err != nil {
+	if err := human.Click(coverMain, playwright.LocatorClickOptions{Force: playwright.Bool(true)}); err != nil {
 		utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 设置封面 - 点击封面区域失败: %v", err))
 		return false, nil
 	}
@@ -49,7 +49,7 @@ func (u *Uploader) setCover(page playwright.Page, thumbnail string) (bool, error
 	}
 
 	if count, _ := confirmBtn.Count(); count > 0 {
-		if err := confirmBtn.Click(); err != nil {
+		if err := human.Click(confirmBtn); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 设置封面 - 点击完成按钮失败: %v", err))
 			page.Keyboard().Press("Escape")
 			return false, nil
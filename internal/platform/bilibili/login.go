@@ -4,12 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"Fuploader/internal/config"
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/vault"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
@@ -90,7 +90,7 @@ func (u *Uploader) saveCookiesFromPage(page playwright.Page) error {
 		return fmt.Errorf("失败: 保存Cookie - 序列化失败: %w", err)
 	}
 
-	if err := os.WriteFile(u.cookiePath, data, 0644); err != nil {
+	if err := vault.WriteFile(u.cookiePath, data); err != nil {
 		return fmt.Errorf("失败: 保存Cookie - 写入失败: %w", err)
 	}
 
@@ -3,9 +3,9 @@ package bilibili
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
+	"Fuploader/internal/platform/vault"
 	"Fuploader/internal/utils"
 
 	"github.com/imroc/req/v3"
@@ -45,7 +45,7 @@ func convertPlaywrightCookies(cookies []PlaywrightCookie) string {
 func ValidateCookieAPI(cookiePath string) (bool, string, error) {
 	utils.InfoWithPlatform("bilibili", fmt.Sprintf("验证Cookie(API) - 开始验证，cookie路径: %s", cookiePath))
 
-	loginInfo, err := os.ReadFile(cookiePath)
+	loginInfo, err := vault.ReadFile(cookiePath)
 	if err != nil || len(loginInfo) == 0 {
 		utils.WarnWithPlatform("bilibili", fmt.Sprintf("验证Cookie(API) - 读取cookie文件失败: %v", err))
 		return false, "", fmt.Errorf("cookie文件不存在")
@@ -40,7 +40,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		return fmt.Errorf("失败: 设置定时发布 - 未找到定时开关: %w", err)
 	}
 
-	if err := switchContainer.Click(); err != nil {
+	if err := human.Click(switchContainer); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 点击定时开关失败: %w", err)
 	}
 	utils.InfoWithPlatform(u.platform, "已开启定时发布")
@@ -52,7 +52,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	}
 
 	datePicker := page.Locator(`div.date-picker-date-wrp`).First()
-	if err := datePicker.Click(); err != nil {
+	if err := human.Click(datePicker); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 点击日期选择器失败: %w", err)
 	}
 
@@ -62,7 +62,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		return fmt.Errorf("失败: 设置定时发布 - 未找到目标日期: %w", err)
 	}
 
-	if err := dateCell.Click(); err != nil {
+	if err := human.Click(dateCell); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 选择日期失败: %w", err)
 	}
 
@@ -71,7 +71,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		return fmt.Errorf("失败: 设置定时发布 - 未找到时间选择器: %w", err)
 	}
 
-	if err := timePicker.Click(); err != nil {
+	if err := human.Click(timePicker); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 点击时间选择器失败: %w", err)
 	}
 
@@ -81,7 +81,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		return fmt.Errorf("失败: 设置定时发布 - 未找到目标时间: %w", err)
 	}
 
-	if err := timeCell.Click(); err != nil {
+	if err := human.Click(timeCell); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 选择时间失败: %w", err)
 	}
 
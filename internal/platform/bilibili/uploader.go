@@ -9,12 +9,16 @@ import (
 
 	"Fuploader/internal/config"
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/types"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
 )
 
+// human 上传页面的点击和输入，按浏览器池配置的 bilibili 人类交互预设执行
+var human = browser.HumanFor("bilibili")
+
 type Uploader struct {
 	accountID   uint
 	cookiePath  string
@@ -51,8 +55,10 @@ func NewUploaderWithAccount(accountID uint) *Uploader {
 	return u
 }
 
-func NewUploaderWithPool(accountID uint, pool *browser.Pool) *Uploader {
-	cookiePath := config.GetCookiePath("bilibili", int(accountID))
+func NewUploaderWithPool(accountID uint, cookiePath string, pool *browser.Pool) *Uploader {
+	if cookiePath == "" {
+		cookiePath = config.GetCookiePath("bilibili", int(accountID))
+	}
 	u := &Uploader{
 		accountID:   accountID,
 		cookiePath:  cookiePath,
@@ -257,7 +263,7 @@ func (u *Uploader) setCopyright(page playwright.Page, copyright string) {
 	}
 
 	if count, _ := copyrightLocator.Count(); count > 0 {
-		if err := copyrightLocator.Click(); err != nil {
+		if err := human.Click(copyrightLocator); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("点击%s选项失败: %v", copyrightText, err))
 		} else {
 			utils.InfoWithPlatform(u.platform, fmt.Sprintf("已选择%s", copyrightText))
@@ -267,12 +273,13 @@ func (u *Uploader) setCopyright(page playwright.Page, copyright string) {
 
 func (u *Uploader) setTitle(page playwright.Page, title string) {
 	utils.InfoWithPlatform(u.platform, "填写标题...")
+	title = platformutils.ApplyTextRule(u.platform, platformutils.FieldTitle, title)
 	titleInput := page.Locator(`input[type="text"][placeholder="请输入稿件标题"]`).First()
 	if err := titleInput.WaitFor(playwright.LocatorWaitForOptions{Timeout: playwright.Float(float64(u.config.ElementWaitTimeout.Milliseconds()))}); err != nil {
 		titleInput = page.Locator(`div.video-title-container input[type="text"]`).First()
 	}
 	if count, _ := titleInput.Count(); count > 0 {
-		if err := titleInput.Fill(title); err != nil {
+		if err := human.Fill(titleInput, title); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("填写标题失败: %v", err))
 		} else {
 			utils.InfoWithPlatform(u.platform, fmt.Sprintf("标题已填写: %s", title))
@@ -291,7 +298,7 @@ func (u *Uploader) setTags(page playwright.Page, tags []string) {
 		if count, _ := tagCloseBtn.Count(); count == 0 {
 			break
 		}
-		if err := tagCloseBtn.Click(); err != nil {
+		if err := human.Click(tagCloseBtn); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("删除默认标签失败: %v", err))
 			break
 		}
@@ -312,7 +319,7 @@ func (u *Uploader) setTags(page playwright.Page, tags []string) {
 	}
 
 	for i, tag := range tags {
-		if err := tagInput.Fill(tag); err != nil {
+		if err := human.Fill(tagInput, tag); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("输入标签[%d]失败: %v", i, err))
 			continue
 		}
@@ -325,6 +332,7 @@ func (u *Uploader) setTags(page playwright.Page, tags []string) {
 
 func (u *Uploader) setDescription(page playwright.Page, description string) {
 	utils.InfoWithPlatform(u.platform, "填写描述...")
+	description = platformutils.ApplyTextRule(u.platform, platformutils.FieldDescription, description)
 	descEditor := page.Locator(`div.ql-editor[data-placeholder*="相关信息"]`).First()
 	if err := descEditor.WaitFor(playwright.LocatorWaitForOptions{Timeout: playwright.Float(float64(u.config.ElementWaitTimeout.Milliseconds()))}); err != nil {
 		descEditor = page.Locator(`div.desc-text-wrp div.ql-editor`).First()
@@ -333,7 +341,7 @@ func (u *Uploader) setDescription(page playwright.Page, description string) {
 		descEditor = page.Locator(`div.archive-info-editor div.ql-editor`).First()
 	}
 	if count, _ := descEditor.Count(); count > 0 {
-		if err := descEditor.Fill(description); err != nil {
+		if err := human.Fill(descEditor, description); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("填写描述失败: %v", err))
 		} else {
 			utils.InfoWithPlatform(u.platform, "描述已填写")
@@ -375,7 +383,7 @@ func (u *Uploader) submitVideo(ctx context.Context, page playwright.Page, browse
 
 		utils.InfoWithPlatform(u.platform, fmt.Sprintf("第%d次尝试发布...", clickAttempt))
 
-		if err := submitBtn.Click(playwright.LocatorClickOptions{Force: playwright.Bool(true)}); err != nil {
+		if err := human.Click(submitBtn, playwright.LocatorClickOptions{Force: playwright.Bool(true)}); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("点击发布按钮失败: %v", err))
 			time.Sleep(2 * time.Second)
 			continue
@@ -385,7 +393,7 @@ func (u *Uploader) submitVideo(ctx context.Context, page playwright.Page, browse
 
 		confirmDialogBtn := page.Locator(`button:has-text("确定"), button:has-text("确认")`).First()
 		if count, _ := confirmDialogBtn.Count(); count > 0 {
-			confirmDialogBtn.Click()
+			human.Click(confirmDialogBtn)
 			time.Sleep(2 * time.Second)
 		}
 
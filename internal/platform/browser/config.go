@@ -17,6 +17,14 @@ const (
 	ReuseModeAggressive ContextReuseMode = "aggressive" // 激进复用，立即复用（适合批量上传）
 )
 
+// StorageMode 登录态存储模式
+type StorageMode string
+
+const (
+	StorageModeState      StorageMode = "storage_state" // Cookie/localStorage 存为 JSON，上下文按需创建（默认）
+	StorageModePersistent StorageMode = "persistent"    // 每个账号独立的 Chrome 用户数据目录，保留 IndexedDB、Service Worker 等
+)
+
 // PoolConfig 浏览器池配置
 type PoolConfig struct {
 	MaxBrowsers           int              `json:"max_browsers"`             // 最大浏览器实例数
@@ -25,6 +33,44 @@ type PoolConfig struct {
 	EnableHealthCheck     bool             `json:"enable_health_check"`      // 是否启用健康检查
 	HealthCheckInterval   int              `json:"health_check_interval"`    // 健康检查间隔（秒）
 	ContextReuseMode      ContextReuseMode `json:"context_reuse_mode"`       // 上下文复用模式
+
+	// 按平台选择登录态存储模式，未配置的平台使用 storage_state
+	PlatformStorageModes map[string]StorageMode `json:"platform_storage_modes,omitempty"`
+	// 持久化模式下同时打开的账号浏览器上限（每个账号独占一个浏览器进程）
+	MaxPersistentContexts int `json:"max_persistent_contexts"`
+
+	// 上传失败诊断：记录整个上传会话的 Playwright trace / HAR，仅在失败时保留
+	FailureTrace bool `json:"failure_trace"`
+	FailureHar   bool `json:"failure_har"`
+	// 诊断记录保留天数和总大小（MB），超出时从最旧的开始删除，0 表示不限制
+	DiagnosticsMaxAgeDays int `json:"diagnostics_max_age_days"`
+	DiagnosticsMaxSizeMB  int `json:"diagnostics_max_size_mb"`
+
+	// 远程浏览器：连接已有的浏览器（CDP 或 Playwright 浏览器服务）而不在本机启动，为空时使用本地 Chrome
+	Remote *RemoteBrowser `json:"remote,omitempty"`
+	// 按平台配置远程浏览器，优先于 Remote
+	PlatformRemotes map[string]*RemoteBrowser `json:"platform_remotes,omitempty"`
+
+	// 人类交互预设（off/light/standard/careful），决定上传器点击和输入的方式，默认 off
+	HumanPreset HumanPreset `json:"human_preset,omitempty"`
+	// 按平台配置人类交互预设，优先于 HumanPreset
+	PlatformHumanPresets map[string]HumanPreset `json:"platform_human_presets,omitempty"`
+}
+
+// RemoteProtocol 远程浏览器连接协议
+type RemoteProtocol string
+
+const (
+	RemoteProtocolCDP        RemoteProtocol = "cdp"        // Chrome DevTools Protocol，如 http://host:9222
+	RemoteProtocolPlaywright RemoteProtocol = "playwright" // Playwright 浏览器服务（launchServer / run-server），如 ws://host:3000/
+)
+
+// RemoteBrowser 远程浏览器端点
+type RemoteBrowser struct {
+	Protocol RemoteProtocol    `json:"protocol"`
+	Endpoint string            `json:"endpoint"`
+	Headers  map[string]string `json:"headers,omitempty"` // 连接时附带的请求头（如鉴权）
+	Timeout  int               `json:"timeout,omitempty"` // 连接超时（秒），0 使用默认 30 秒
 }
 
 // DefaultPoolConfig 默认配置
@@ -35,6 +81,9 @@ var DefaultPoolConfig = PoolConfig{
 	EnableHealthCheck:     true,
 	HealthCheckInterval:   60,
 	ContextReuseMode:      ReuseModeConservative, // 默认保守复用
+	MaxPersistentContexts: 3,
+	DiagnosticsMaxAgeDays: 7,
+	DiagnosticsMaxSizeMB:  500,
 }
 
 var (
@@ -140,10 +189,86 @@ func UpdateConfig(updates map[string]interface{}) error {
 	if v, ok := updates["context_reuse_mode"].(string); ok {
 		cfg.ContextReuseMode = ContextReuseMode(v)
 	}
+	if v, ok := updates["platform_storage_modes"].(map[string]interface{}); ok {
+		cfg.PlatformStorageModes = make(map[string]StorageMode, len(v))
+		for platform, mode := range v {
+			if m, ok := mode.(string); ok {
+				cfg.PlatformStorageModes[platform] = StorageMode(m)
+			}
+		}
+	}
+	if v, ok := updates["max_persistent_contexts"].(float64); ok {
+		cfg.MaxPersistentContexts = int(v)
+	}
+	if v, ok := updates["failure_trace"].(bool); ok {
+		cfg.FailureTrace = v
+	}
+	if v, ok := updates["failure_har"].(bool); ok {
+		cfg.FailureHar = v
+	}
+	if v, ok := updates["diagnostics_max_age_days"].(float64); ok {
+		cfg.DiagnosticsMaxAgeDays = int(v)
+	}
+	if v, ok := updates["diagnostics_max_size_mb"].(float64); ok {
+		cfg.DiagnosticsMaxSizeMB = int(v)
+	}
+	if v, ok := updates["human_preset"].(string); ok {
+		cfg.HumanPreset = HumanPreset(v)
+	}
+	if v, ok := updates["platform_human_presets"].(map[string]interface{}); ok {
+		cfg.PlatformHumanPresets = make(map[string]HumanPreset, len(v))
+		for platform, preset := range v {
+			if p, ok := preset.(string); ok {
+				cfg.PlatformHumanPresets[platform] = HumanPreset(p)
+			}
+		}
+	}
+	if v, ok := updates["remote"]; ok {
+		cfg.Remote = nil
+		if data, err := json.Marshal(v); err == nil {
+			json.Unmarshal(data, &cfg.Remote)
+		}
+	}
+	if v, ok := updates["platform_remotes"]; ok {
+		cfg.PlatformRemotes = nil
+		if data, err := json.Marshal(v); err == nil {
+			json.Unmarshal(data, &cfg.PlatformRemotes)
+		}
+	}
 
 	return SavePoolConfig(cfg)
 }
 
+// StorageModeFor 平台使用的登录态存储模式
+func (c *PoolConfig) StorageModeFor(platform string) StorageMode {
+	if mode, ok := c.PlatformStorageModes[platform]; ok && mode == StorageModePersistent {
+		return StorageModePersistent
+	}
+	return StorageModeState
+}
+
+// RemoteFor 平台使用的远程浏览器，未配置（使用本地 Chrome）时返回 nil
+func (c *PoolConfig) RemoteFor(platform string) *RemoteBrowser {
+	if remote, ok := c.PlatformRemotes[platform]; ok && remote != nil && remote.Endpoint != "" {
+		return remote
+	}
+	if c.Remote != nil && c.Remote.Endpoint != "" {
+		return c.Remote
+	}
+	return nil
+}
+
+// HumanPresetFor 平台使用的人类交互预设，未配置或无效时为 off
+func (c *PoolConfig) HumanPresetFor(platform string) HumanPreset {
+	if preset, ok := c.PlatformHumanPresets[platform]; ok && preset.Valid() {
+		return preset
+	}
+	if c.HumanPreset.Valid() {
+		return c.HumanPreset
+	}
+	return HumanPresetOff
+}
+
 // ResetToDefault 重置为默认配置
 func ResetToDefault() error {
 	cfg := DefaultPoolConfig
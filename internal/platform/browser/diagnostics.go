@@ -0,0 +1,241 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"Fuploader/internal/utils"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Diagnostics 一次上传会话的故障诊断记录（Playwright trace / HAR）
+// 通过 WithDiagnostics 放入 context，会话中获取的浏览器上下文在 Release 时写出记录，Finish 时仅在失败时保留
+// 记录中包含 Cookie 等登录凭据，目录和文件仅当前用户可读写
+type Diagnostics struct {
+	Trace bool // 记录 trace（DOM 快照、网络、控制台），可用 playwright show-trace 查看
+	HAR   bool // 记录 HAR（使用独立上下文，会话结束后关闭）
+
+	dir    string // 保留记录的目录
+	name   string // 文件名前缀
+	tmpDir string
+
+	mu     sync.Mutex
+	traces []string
+	hars   []string
+	seq    int
+}
+
+// DiagnosticsFiles 保留下来的诊断文件（多个上下文时为最后一个）
+type DiagnosticsFiles struct {
+	TracePath string
+	HarPath   string
+}
+
+type diagnosticsKey struct{}
+
+// NewDiagnostics 创建诊断会话，dir 为失败时保存记录的目录，name 为文件名前缀
+func NewDiagnostics(dir, name string, trace, har bool) *Diagnostics {
+	return &Diagnostics{
+		Trace:  trace,
+		HAR:    har,
+		dir:    dir,
+		name:   name,
+		tmpDir: filepath.Join(dir, ".tmp", name),
+	}
+}
+
+// WithDiagnostics 将诊断会话放入 context，浏览器池据此为上下文开启记录
+func WithDiagnostics(ctx context.Context, d *Diagnostics) context.Context {
+	return context.WithValue(ctx, diagnosticsKey{}, d)
+}
+
+func diagnosticsFrom(ctx context.Context) *Diagnostics {
+	if ctx == nil {
+		return nil
+	}
+	d, _ := ctx.Value(diagnosticsKey{}).(*Diagnostics)
+	return d
+}
+
+// privateDir 创建仅当前用户可访问的目录（已存在时同样收紧权限）
+func privateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return os.Chmod(dir, 0700)
+}
+
+// nextPath 会话内临时文件路径
+func (d *Diagnostics) nextPath(ext string) (string, error) {
+	if err := privateDir(d.dir); err != nil {
+		return "", fmt.Errorf("create diagnostics directory failed: %w", err)
+	}
+	if err := privateDir(d.tmpDir); err != nil {
+		return "", fmt.Errorf("create diagnostics directory failed: %w", err)
+	}
+	d.mu.Lock()
+	d.seq++
+	seq := d.seq
+	d.mu.Unlock()
+	return filepath.Join(d.tmpDir, fmt.Sprintf("%d%s", seq, ext)), nil
+}
+
+func (d *Diagnostics) addTrace(path string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.traces = append(d.traces, path)
+}
+
+func (d *Diagnostics) addHar(path string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.hars = append(d.hars, path)
+}
+
+// Finish 结束会话：keep 为 true（上传失败）时将记录移动到诊断目录，否则删除
+func (d *Diagnostics) Finish(keep bool) (DiagnosticsFiles, error) {
+	d.mu.Lock()
+	traces, hars := d.traces, d.hars
+	d.traces, d.hars = nil, nil
+	d.mu.Unlock()
+	defer os.RemoveAll(d.tmpDir)
+
+	var files DiagnosticsFiles
+	if !keep {
+		return files, nil
+	}
+
+	var firstErr error
+	keepFiles := func(paths []string, ext string) string {
+		kept := ""
+		for i, src := range paths {
+			if _, err := os.Stat(src); err != nil {
+				continue
+			}
+			name := d.name + ext
+			if len(paths) > 1 {
+				name = fmt.Sprintf("%s_%d%s", d.name, i+1, ext)
+			}
+			dst := filepath.Join(d.dir, name)
+			err := os.Chmod(src, 0600)
+			if err == nil {
+				err = os.Rename(src, dst)
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("save diagnostics file failed: %w", err)
+				}
+				continue
+			}
+			kept = dst
+		}
+		return kept
+	}
+	files.TracePath = keepFiles(traces, "_trace.zip")
+	files.HarPath = keepFiles(hars, ".har")
+	return files, firstErr
+}
+
+// CleanDiagnostics 按保留天数和总大小清理诊断目录中保留的记录（超出总大小时从最旧的开始删除），返回已删除的文件
+// maxAgeDays、maxSizeMB 为 0 表示不限制
+func CleanDiagnostics(dir string, maxAgeDays, maxSizeMB int, now time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read diagnostics directory failed: %w", err)
+	}
+
+	type record struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var records []record
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		records = append(records, record{filepath.Join(dir, entry.Name()), info.Size(), info.ModTime()})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].modTime.After(records[j].modTime) })
+
+	var deleted []string
+	cutoff := now.AddDate(0, 0, -maxAgeDays)
+	limit := int64(maxSizeMB) * 1024 * 1024
+	var total int64
+	for _, rec := range records {
+		expired := maxAgeDays > 0 && rec.modTime.Before(cutoff)
+		if !expired {
+			total += rec.size
+			expired = maxSizeMB > 0 && total > limit
+		}
+		if !expired {
+			continue
+		}
+		if err := os.Remove(rec.path); err != nil {
+			utils.Warn(fmt.Sprintf("[-] 清理诊断记录失败 %s: %v", rec.path, err))
+			continue
+		}
+		deleted = append(deleted, rec.path)
+	}
+	return deleted, nil
+}
+
+// startDiagnostics 为上下文开启 trace 记录（上下文已在记录时跳过）
+func (c *PooledContext) startDiagnostics(d *Diagnostics) {
+	if d == nil || !d.Trace {
+		return
+	}
+	if err := c.context.Tracing().Start(playwright.TracingStartOptions{
+		Name:        playwright.String(d.name),
+		Screenshots: playwright.Bool(true),
+		Snapshots:   playwright.Bool(true),
+		Sources:     playwright.Bool(false),
+	}); err != nil {
+		utils.Warn(fmt.Sprintf("[-] [%s] 开启 trace 记录失败: %v", c.platform, err))
+		return
+	}
+	c.diagnostics = d
+}
+
+// stopDiagnostics 结束 trace 记录并写入诊断会话的临时目录
+func (c *PooledContext) stopDiagnostics() {
+	d := c.diagnostics
+	if d == nil {
+		return
+	}
+	c.diagnostics = nil
+
+	path, err := d.nextPath("_trace.zip")
+	if err == nil {
+		err = c.context.Tracing().Stop(path)
+	} else {
+		c.context.Tracing().Stop()
+	}
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] [%s] 保存 trace 记录失败: %v", c.platform, err))
+		return
+	}
+	d.addTrace(path)
+}
+
+// finishHar 上下文关闭后将 HAR 文件登记到诊断会话
+func (c *PooledContext) finishHar() {
+	if c.har == nil || c.harPath == "" {
+		return
+	}
+	c.har.addHar(c.harPath)
+	c.har, c.harPath = nil, ""
+}
@@ -0,0 +1,113 @@
+package browser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func writeTemp(t *testing.T, d *Diagnostics, ext string) string {
+	t.Helper()
+	path, err := d.nextPath(ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiagnosticsFinish(t *testing.T) {
+	dir := t.TempDir()
+
+	// 上传失败：记录移动到诊断目录
+	d := NewDiagnostics(dir, "task_1", true, true)
+	d.addTrace(writeTemp(t, d, "_trace.zip"))
+	d.addHar(writeTemp(t, d, ".har"))
+	files, err := d.Finish(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files.TracePath != filepath.Join(dir, "task_1_trace.zip") || files.HarPath != filepath.Join(dir, "task_1.har") {
+		t.Fatalf("files = %+v", files)
+	}
+	for _, p := range []string{files.TracePath, files.HarPath} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Errorf("kept file missing: %v", err)
+			continue
+		}
+		// 记录包含 Cookie，仅当前用户可读写
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("%s mode = %v, want 0600", p, info.Mode().Perm())
+		}
+	}
+	if info, err := os.Stat(dir); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0700 {
+		t.Errorf("diagnostics directory mode = %v, want 0700", info.Mode().Perm())
+	}
+
+	// 上传成功：临时记录被删除
+	d = NewDiagnostics(dir, "task_2", true, false)
+	tmp := writeTemp(t, d, "_trace.zip")
+	d.addTrace(tmp)
+	if files, err := d.Finish(false); err != nil || files.TracePath != "" {
+		t.Fatalf("files = %+v, err = %v", files, err)
+	}
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Error("temporary trace not removed")
+	}
+}
+
+func TestDiagnosticsContext(t *testing.T) {
+	if diagnosticsFrom(context.Background()) != nil {
+		t.Error("unexpected diagnostics")
+	}
+	d := NewDiagnostics(t.TempDir(), "task", true, false)
+	if diagnosticsFrom(WithDiagnostics(context.Background(), d)) != d {
+		t.Error("diagnostics not propagated")
+	}
+}
+
+func TestCleanDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	write := func(name string, size int, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	newest := write("task_3.har", 600*1024, time.Hour)
+	middle := write("task_2_trace.zip", 600*1024, 24*time.Hour)
+	old := write("task_1.har", 10, 10*24*time.Hour)
+	if err := os.MkdirAll(filepath.Join(dir, ".tmp", "task_4"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	// 超过 7 天的记录被删除，剩余记录超过 1MB 时从最旧的开始删除
+	deleted, err := CleanDiagnostics(dir, 7, 1, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || deleted[0] != middle || deleted[1] != old {
+		t.Fatalf("deleted = %v, want %s and %s", deleted, middle, old)
+	}
+	if _, err := os.Stat(newest); err != nil {
+		t.Errorf("newest record removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".tmp")); err != nil {
+		t.Errorf("session directory removed: %v", err)
+	}
+
+	if deleted, err := CleanDiagnostics(filepath.Join(dir, "missing"), 7, 1, now); err != nil || len(deleted) != 0 {
+		t.Errorf("missing directory: deleted = %v, err = %v", deleted, err)
+	}
+}
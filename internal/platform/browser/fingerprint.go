@@ -0,0 +1,278 @@
+package browser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"Fuploader/internal/config"
+	"Fuploader/internal/utils"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Fingerprint 浏览器指纹档案
+// 账号首次使用时生成并持久化，之后每次为该账号创建上下文都使用同一份档案，避免同一账号每次登录都像换了一台设备
+type Fingerprint struct {
+	ChromeVersion  string  `json:"chromeVersion"`
+	UserAgent      string  `json:"userAgent"`
+	Platform       string  `json:"platform"` // Sec-Ch-Ua-Platform
+	ViewportWidth  int     `json:"viewportWidth"`
+	ViewportHeight int     `json:"viewportHeight"`
+	Locale         string  `json:"locale"`
+	TimezoneID     string  `json:"timezoneId"`
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	WebGLVendor    string  `json:"webglVendor"`
+	WebGLRenderer  string  `json:"webglRenderer"`
+	NoiseSeed      uint32  `json:"noiseSeed"` // Canvas/WebGL 读数噪声种子
+}
+
+// FingerprintStore 账号指纹持久化接口
+// accountID 为 0 时通过 cookiePath 定位账号；无法定位账号时 AccountRegion 和 LoadFingerprint 返回 ErrNoAccount
+type FingerprintStore interface {
+	// AccountRegion 账号浏览器上下文的地区（按账号平台和时区，见 config.AccountBrowserRegion）
+	AccountRegion(accountID uint, cookiePath string) (config.BrowserRegion, error)
+	LoadFingerprint(accountID uint, cookiePath string) (*Fingerprint, error)
+	SaveFingerprint(accountID uint, cookiePath string, fp *Fingerprint) error
+}
+
+// ErrNoAccount 上下文无法关联到账号（如新账号扫码登录前）
+var ErrNoAccount = errors.New("no account for browser context")
+
+var (
+	fingerprintStore FingerprintStore
+	fingerprintMu    sync.Mutex
+)
+
+var (
+	fingerprintChromeVersions = []string{"120", "121", "122", "123", "124", "125"}
+
+	// 常见桌面分辨率（扣除任务栏和浏览器工具栏后的可视区域）
+	fingerprintViewports = []playwright.Size{
+		{Width: 1920, Height: 969},
+		{Width: 1536, Height: 730},
+		{Width: 1440, Height: 789},
+		{Width: 1366, Height: 657},
+		{Width: 1600, Height: 789},
+		{Width: 1680, Height: 939},
+	}
+
+	fingerprintWebGL = [][2]string{
+		{"Google Inc. (NVIDIA)", "ANGLE (NVIDIA, NVIDIA GeForce GTX 1650 Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+		{"Google Inc. (NVIDIA)", "ANGLE (NVIDIA, NVIDIA GeForce RTX 3060 Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+		{"Google Inc. (Intel)", "ANGLE (Intel, Intel(R) UHD Graphics 630 Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+		{"Google Inc. (Intel)", "ANGLE (Intel, Intel(R) Iris(R) Xe Graphics Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+		{"Google Inc. (AMD)", "ANGLE (AMD, AMD Radeon(TM) Graphics Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+	}
+)
+
+// SetFingerprintStore 设置账号指纹存储，未设置时每个上下文使用随机指纹
+func SetFingerprintStore(store FingerprintStore) {
+	fingerprintMu.Lock()
+	defer fingerprintMu.Unlock()
+	fingerprintStore = store
+}
+
+// GenerateFingerprint 生成一份新的随机指纹档案，语言、时区和地理位置取自 region
+func GenerateFingerprint(region config.BrowserRegion) *Fingerprint {
+	version := fingerprintChromeVersions[rand.Intn(len(fingerprintChromeVersions))]
+	viewport := fingerprintViewports[rand.Intn(len(fingerprintViewports))]
+	webgl := fingerprintWebGL[rand.Intn(len(fingerprintWebGL))]
+
+	fp := &Fingerprint{
+		ChromeVersion: version,
+		UserAgent: fmt.Sprintf(
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s.0.0.0 Safari/537.36",
+			version,
+		),
+		Platform:       "Windows",
+		ViewportWidth:  viewport.Width,
+		ViewportHeight: viewport.Height,
+		WebGLVendor:    webgl[0],
+		WebGLRenderer:  webgl[1],
+		NoiseSeed:      rand.Uint32(),
+	}
+	fp.setRegion(region)
+	return fp
+}
+
+// setRegion 按地区设置语言、时区和地理位置（地区城市附近的随机位置），地区没有经纬度时不设置地理位置
+func (f *Fingerprint) setRegion(region config.BrowserRegion) {
+	f.Locale = region.Locale
+	f.TimezoneID = region.TimezoneID
+	f.Latitude, f.Longitude = 0, 0
+	if region.Latitude != 0 || region.Longitude != 0 {
+		f.Latitude = region.Latitude + (rand.Float64()-0.5)*0.1
+		f.Longitude = region.Longitude + (rand.Float64()-0.5)*0.1
+	}
+}
+
+// matchesRegion 指纹的语言和时区是否与地区一致
+func (f *Fingerprint) matchesRegion(region config.BrowserRegion) bool {
+	return f.Locale == region.Locale && f.TimezoneID == region.TimezoneID
+}
+
+// acceptLanguage 与语言一致的 Accept-Language 请求头
+func acceptLanguage(locale string) string {
+	lang, _, _ := strings.Cut(locale, "-")
+	if lang == "en" {
+		return locale + ",en;q=0.9"
+	}
+	return fmt.Sprintf("%s,%s;q=0.9,en;q=0.8", locale, lang)
+}
+
+// ClientHints 与 UA 一致的客户端提示请求头
+func (f *Fingerprint) ClientHints() map[string]string {
+	return map[string]string{
+		"Sec-Ch-Ua":          fmt.Sprintf(`"Not_A Brand";v="8", "Chromium";v="%s", "Google Chrome";v="%s"`, f.ChromeVersion, f.ChromeVersion),
+		"Sec-Ch-Ua-Mobile":   "?0",
+		"Sec-Ch-Ua-Platform": fmt.Sprintf(`"%s"`, f.Platform),
+	}
+}
+
+// Apply 按指纹档案生成上下文选项，保留 base 中的反爬开关
+func (f *Fingerprint) Apply(base *ContextOptions) *ContextOptions {
+	headers := map[string]string{
+		"Accept-Language":           acceptLanguage(f.Locale),
+		"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
+		"Accept-Encoding":           "gzip, deflate, br",
+		"Upgrade-Insecure-Requests": "1",
+	}
+	for k, v := range f.ClientHints() {
+		headers[k] = v
+	}
+
+	var geolocation *playwright.Geolocation
+	if f.Latitude != 0 || f.Longitude != 0 {
+		geolocation = &playwright.Geolocation{Latitude: f.Latitude, Longitude: f.Longitude}
+	}
+
+	return &ContextOptions{
+		UserAgent:   f.UserAgent,
+		Viewport:    &playwright.Size{Width: f.ViewportWidth, Height: f.ViewportHeight},
+		Locale:      f.Locale,
+		TimezoneId:  f.TimezoneID,
+		Geolocation: geolocation,
+
+		ExtraHeaders:      headers,
+		EnableAntiDetect:  base.EnableAntiDetect,
+		EnableRandomDelay: base.EnableRandomDelay,
+		HumanLikeBehavior: base.HumanLikeBehavior,
+		Fingerprint:       f,
+		Proxy:             base.Proxy,
+		RecordHarPath:     base.RecordHarPath,
+		RecordVideoDir:    base.RecordVideoDir,
+		RecordVideoSize:   base.RecordVideoSize,
+	}
+}
+
+// NoiseScript 注入页面的 Canvas/WebGL 指纹脚本
+// 噪声由固定种子生成，同一账号每次读取的 Canvas 哈希一致，不同账号之间互不相同
+func (f *Fingerprint) NoiseScript() string {
+	vendor, _ := json.Marshal(f.WebGLVendor)
+	renderer, _ := json.Marshal(f.WebGLRenderer)
+	return fmt.Sprintf(`(() => {
+  const seed = %d >>> 0;
+  const noise = (i) => {
+    let t = (seed + Math.imul(i + 1, 0x6D2B79F5)) >>> 0;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) & 1;
+  };
+  const perturb = (data) => {
+    for (let i = 0; i < data.length; i += 4 * 97) data[i] ^= noise(i);
+    return data;
+  };
+
+  const getImageData = CanvasRenderingContext2D.prototype.getImageData;
+  CanvasRenderingContext2D.prototype.getImageData = function (...args) {
+    const image = getImageData.apply(this, args);
+    perturb(image.data);
+    return image;
+  };
+
+  const withNoise = (canvas, fn) => {
+    const ctx = canvas.width && canvas.height ? canvas.getContext('2d') : null;
+    if (!ctx) return fn();
+    const saved = getImageData.call(ctx, 0, 0, canvas.width, canvas.height);
+    const noisy = new ImageData(perturb(new Uint8ClampedArray(saved.data)), canvas.width, canvas.height);
+    ctx.putImageData(noisy, 0, 0);
+    try { return fn(); } finally { ctx.putImageData(saved, 0, 0); }
+  };
+  const toDataURL = HTMLCanvasElement.prototype.toDataURL;
+  HTMLCanvasElement.prototype.toDataURL = function (...args) {
+    return withNoise(this, () => toDataURL.apply(this, args));
+  };
+  const toBlob = HTMLCanvasElement.prototype.toBlob;
+  HTMLCanvasElement.prototype.toBlob = function (...args) {
+    return withNoise(this, () => toBlob.apply(this, args));
+  };
+
+  const patchWebGL = (proto) => {
+    if (!proto) return;
+    const getParameter = proto.getParameter;
+    proto.getParameter = function (p) {
+      if (p === 0x9245) return %s;
+      if (p === 0x9246) return %s;
+      return getParameter.call(this, p);
+    };
+  };
+  patchWebGL(window.WebGLRenderingContext && WebGLRenderingContext.prototype);
+  patchWebGL(window.WebGL2RenderingContext && WebGL2RenderingContext.prototype);
+})();`, f.NoiseSeed, vendor, renderer)
+}
+
+// accountFingerprint 获取账号的固定指纹，首次使用时生成并保存；无法关联账号时返回 nil
+// 账号时区修改后，已保存指纹的语言、时区和地理位置按新地区更新，其余部分保持不变
+func accountFingerprint(accountID uint, cookiePath string) *Fingerprint {
+	if accountID == 0 && cookiePath == "" {
+		return nil
+	}
+
+	fingerprintMu.Lock()
+	defer fingerprintMu.Unlock()
+	if fingerprintStore == nil {
+		return nil
+	}
+
+	region, err := fingerprintStore.AccountRegion(accountID, cookiePath)
+	if errors.Is(err, ErrNoAccount) {
+		return nil
+	}
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 读取账号地区失败，本次使用随机指纹: %v", err))
+		return nil
+	}
+	fp, err := fingerprintStore.LoadFingerprint(accountID, cookiePath)
+	if errors.Is(err, ErrNoAccount) {
+		return nil
+	}
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 读取账号指纹失败，本次使用随机指纹: %v", err))
+		return nil
+	}
+	if fp != nil {
+		if fp.matchesRegion(region) {
+			return fp
+		}
+		fp.setRegion(region)
+		if err := fingerprintStore.SaveFingerprint(accountID, cookiePath, fp); err != nil {
+			utils.Warn(fmt.Sprintf("[-] 保存账号指纹失败: %v", err))
+		} else {
+			utils.Info(fmt.Sprintf("[-] 账号地区已变更，指纹时区更新为 %s", fp.TimezoneID))
+		}
+		return fp
+	}
+
+	fp = GenerateFingerprint(region)
+	if err := fingerprintStore.SaveFingerprint(accountID, cookiePath, fp); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 保存账号指纹失败: %v", err))
+	} else {
+		utils.Info(fmt.Sprintf("[-] 已为账号生成固定指纹 - Chrome %s, %dx%d", fp.ChromeVersion, fp.ViewportWidth, fp.ViewportHeight))
+	}
+	return fp
+}
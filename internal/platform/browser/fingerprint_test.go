@@ -0,0 +1,133 @@
+package browser
+
+import (
+	"Fuploader/internal/config"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFingerprintApply(t *testing.T) {
+	fp := GenerateFingerprint(config.PlatformBrowserRegion(config.PlatformDouyin))
+	opts := fp.Apply(&ContextOptions{EnableAntiDetect: true, HumanLikeBehavior: true})
+
+	if opts.Fingerprint != fp || !opts.EnableAntiDetect || !opts.HumanLikeBehavior || opts.EnableRandomDelay {
+		t.Fatalf("反爬开关或指纹未保留: %+v", opts)
+	}
+	if !strings.Contains(opts.UserAgent, "Chrome/"+fp.ChromeVersion+".") {
+		t.Errorf("UA %q 与版本 %s 不一致", opts.UserAgent, fp.ChromeVersion)
+	}
+	if hint := opts.ExtraHeaders["Sec-Ch-Ua"]; !strings.Contains(hint, `"Google Chrome";v="`+fp.ChromeVersion+`"`) {
+		t.Errorf("Sec-Ch-Ua %q 与 UA 不一致", hint)
+	}
+	if opts.Viewport.Width != fp.ViewportWidth || opts.Viewport.Height != fp.ViewportHeight {
+		t.Errorf("viewport = %v", opts.Viewport)
+	}
+	if opts.Geolocation.Latitude != fp.Latitude || opts.TimezoneId != fp.TimezoneID {
+		t.Errorf("geolocation/timezone 未应用")
+	}
+}
+
+func TestFingerprintStable(t *testing.T) {
+	fp := GenerateFingerprint(config.PlatformBrowserRegion(config.PlatformDouyin))
+	data, err := json.Marshal(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored Fingerprint
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	// 持久化后恢复的档案生成完全相同的上下文选项和注入脚本
+	a, b := fp.Apply(DefaultContextOptions()), restored.Apply(DefaultContextOptions())
+	if a.UserAgent != b.UserAgent || *a.Viewport != *b.Viewport || *a.Geolocation != *b.Geolocation {
+		t.Errorf("restored options differ: %+v vs %+v", a, b)
+	}
+	for k, v := range a.ExtraHeaders {
+		if b.ExtraHeaders[k] != v {
+			t.Errorf("header %s: %q vs %q", k, v, b.ExtraHeaders[k])
+		}
+	}
+	if fp.NoiseScript() != restored.NoiseScript() {
+		t.Error("noise script differs after restore")
+	}
+}
+
+// memoryFingerprintStore 内存指纹存储，只有一个账号
+type memoryFingerprintStore struct {
+	region config.BrowserRegion
+	fp     *Fingerprint
+	saves  int
+}
+
+func (s *memoryFingerprintStore) AccountRegion(accountID uint, cookiePath string) (config.BrowserRegion, error) {
+	if accountID != 1 {
+		return config.BrowserRegion{}, ErrNoAccount
+	}
+	return s.region, nil
+}
+
+func (s *memoryFingerprintStore) LoadFingerprint(accountID uint, cookiePath string) (*Fingerprint, error) {
+	return s.fp, nil
+}
+
+func (s *memoryFingerprintStore) SaveFingerprint(accountID uint, cookiePath string, fp *Fingerprint) error {
+	saved := *fp
+	s.fp = &saved
+	s.saves++
+	return nil
+}
+
+var testConfigOnce sync.Once
+
+func TestAccountFingerprintRegion(t *testing.T) {
+	// 生成和更新指纹会写日志，日志文件需要配置目录
+	testConfigOnce.Do(func() {
+		if config.Config == nil {
+			dir, err := os.MkdirTemp("", "fuploader-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			config.Config = &config.AppConfig{LogPath: dir}
+		}
+	})
+
+	store := &memoryFingerprintStore{region: config.AccountBrowserRegion(config.PlatformTiktok, "")}
+	SetFingerprintStore(store)
+	defer SetFingerprintStore(nil)
+
+	fp := accountFingerprint(1, "")
+	if fp == nil || fp.TimezoneID != "Europe/London" || fp.Locale != "en-GB" || store.saves != 1 {
+		t.Fatalf("首次生成的指纹 = %+v, saves = %d", fp, store.saves)
+	}
+	if again := accountFingerprint(1, ""); again.UserAgent != fp.UserAgent || store.saves != 1 {
+		t.Errorf("地区未变时应复用已保存的指纹")
+	}
+	if accountFingerprint(2, "") != nil {
+		t.Error("无法关联账号时应返回 nil")
+	}
+
+	// 账号时区修改后只更新地区相关字段
+	store.region = config.AccountBrowserRegion(config.PlatformTiktok, "America/New_York")
+	updated := accountFingerprint(1, "")
+	if updated.TimezoneID != "America/New_York" || updated.UserAgent != fp.UserAgent || updated.NoiseSeed != fp.NoiseSeed || store.saves != 2 {
+		t.Errorf("更新后的指纹 = %+v", updated)
+	}
+	if updated.Latitude < 40 || updated.Latitude > 41.5 {
+		t.Errorf("地理位置未按账号时区更新: %f", updated.Latitude)
+	}
+
+	opts := updated.Apply(&ContextOptions{})
+	if opts.TimezoneId != "America/New_York" || opts.ExtraHeaders["Accept-Language"] != "en-GB,en;q=0.9" {
+		t.Errorf("options = %s, %q", opts.TimezoneId, opts.ExtraHeaders["Accept-Language"])
+	}
+
+	// 时区不在坐标表中时不设置地理位置
+	store.region = config.AccountBrowserRegion(config.PlatformDouyin, "Asia/Kolkata")
+	if opts := accountFingerprint(1, "").Apply(&ContextOptions{}); opts.Geolocation != nil || opts.TimezoneId != "Asia/Kolkata" || opts.Locale != "zh-CN" {
+		t.Errorf("options = %+v", opts)
+	}
+}
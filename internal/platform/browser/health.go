@@ -126,10 +126,7 @@ func (h *HealthChecker) Check() *HealthCheckResult {
 		CheckedAt: time.Now(),
 	}
 
-	h.pool.mutex.RLock()
-	browsers := make([]*PooledBrowser, len(h.pool.browsers))
-	copy(browsers, h.pool.browsers)
-	h.pool.mutex.RUnlock()
+	browsers := h.pool.allBrowsers()
 
 	result.BrowserCount = len(browsers)
 
@@ -167,6 +164,11 @@ func (h *HealthChecker) Check() *HealthCheckResult {
 
 // isBrowserHealthy 检查浏览器是否健康
 func (h *HealthChecker) isBrowserHealthy(browser *PooledBrowser) bool {
+	// 持久化浏览器只有一个账号上下文，进程退出即视为不健康（下次获取时重新启动）
+	if browser.persistent {
+		return !browser.closed.Load()
+	}
+
 	browser.mutex.Lock()
 	defer browser.mutex.Unlock()
 
@@ -234,10 +236,7 @@ func (h *HealthChecker) checkContextHealth(ctx *PooledContext) (bool, string) {
 
 // cleanupUnhealthyContexts 清理不健康的上下文
 func (h *HealthChecker) cleanupUnhealthyContexts() {
-	h.pool.mutex.RLock()
-	browsers := make([]*PooledBrowser, len(h.pool.browsers))
-	copy(browsers, h.pool.browsers)
-	h.pool.mutex.RUnlock()
+	browsers := h.pool.allBrowsers()
 
 	for _, browser := range browsers {
 		browser.mutex.Lock()
@@ -258,39 +257,7 @@ func (h *HealthChecker) cleanupUnhealthyContexts() {
 
 // restartBrowser 重启浏览器实例
 func (h *HealthChecker) restartBrowser(oldBrowser *PooledBrowser) error {
-	h.pool.mutex.Lock()
-	defer h.pool.mutex.Unlock()
-
-	// 关闭旧的浏览器实例
-	for _, ctx := range oldBrowser.contexts {
-		ctx.Close()
-	}
-	if err := oldBrowser.browser.Close(); err != nil {
-		utils.Warn(fmt.Sprintf("[-] 关闭旧浏览器失败: %v", err))
-	}
-
-	// 从池中移除
-	for i, b := range h.pool.browsers {
-		if b == oldBrowser {
-			h.pool.browsers = append(h.pool.browsers[:i], h.pool.browsers[i+1:]...)
-			break
-		}
-	}
-
-	// 创建新的浏览器实例
-	newBrowser, err := h.pool.launchBrowser()
-	if err != nil {
-		return fmt.Errorf("启动新浏览器失败: %w", err)
-	}
-
-	pooled := &PooledBrowser{
-		browser:  newBrowser,
-		contexts: make([]*PooledContext, 0),
-	}
-	h.pool.browsers = append(h.pool.browsers, pooled)
-
-	utils.Info("[-] 浏览器实例已重启")
-	return nil
+	return h.pool.restartBrowser(oldBrowser)
 }
 
 // GetLastCheckTime 获取上次检查时间
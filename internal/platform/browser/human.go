@@ -0,0 +1,361 @@
+package browser
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// HumanPreset 人类交互预设，越接近真人操作越慢
+type HumanPreset string
+
+const (
+	HumanPresetOff      HumanPreset = "off"      // 直接 Fill/Click
+	HumanPresetLight    HumanPreset = "light"    // 鼠标移动到元素后点击，短暂停顿，文本直接填充
+	HumanPresetStandard HumanPreset = "standard" // 操作前思考停顿，滚轮滚动到元素，短文本逐字输入
+	HumanPresetCareful  HumanPreset = "careful"  // 较长的思考停顿，较长文本也逐字输入
+)
+
+// HumanPresetInfo 预设说明（成本/收益），供设置页展示
+type HumanPresetInfo struct {
+	Preset      HumanPreset `json:"preset"`
+	Description string      `json:"description"`
+	ExtraTime   string      `json:"extra_time"` // 每次上传大约增加的耗时
+}
+
+// HumanPresets 所有预设及其成本/收益说明
+func HumanPresets() []HumanPresetInfo {
+	return []HumanPresetInfo{
+		{HumanPresetOff, "直接填充和点击，最快，最容易被识别为自动化", "0"},
+		{HumanPresetLight, "点击前沿曲线移动鼠标、按下有时长，操作间短暂停顿，文本直接填充", "5-15 秒"},
+		{HumanPresetStandard, "操作前思考停顿，滚轮滚动到元素，标题、标签等短文本逐字输入", "20-60 秒"},
+		{HumanPresetCareful, "较长的思考停顿，300 字以内的文本逐字输入并在词间停顿", "1-3 分钟"},
+	}
+}
+
+// Valid 是否为已知预设
+func (p HumanPreset) Valid() bool {
+	_, ok := humanProfiles[p]
+	return ok
+}
+
+// HumanDelay 停顿时长分布：对数正态分布（中位数 Median，离散度 Sigma），截断到 [Min, Max]
+type HumanDelay struct {
+	Median time.Duration
+	Sigma  float64
+	Min    time.Duration
+	Max    time.Duration
+}
+
+// at 标准正态分位 z 对应的时长
+func (d HumanDelay) at(z float64) time.Duration {
+	if d.Median <= 0 {
+		return 0
+	}
+	delay := time.Duration(float64(d.Median) * math.Exp(d.Sigma*z))
+	if delay < d.Min {
+		delay = d.Min
+	}
+	if d.Max > 0 && delay > d.Max {
+		delay = d.Max
+	}
+	return delay
+}
+
+// Sample 随机取一个停顿时长
+func (d HumanDelay) Sample() time.Duration {
+	return d.at(rand.NormFloat64())
+}
+
+func (d HumanDelay) sleep() {
+	if delay := d.Sample(); delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// HumanProfile 预设对应的交互参数，零值即 off
+type HumanProfile struct {
+	ThinkTime  HumanDelay // 每次点击/输入前的停顿
+	MoveMouse  bool       // 点击前沿曲线移动鼠标到元素内的随机位置
+	ClickHold  HumanDelay // 鼠标按下到松开的时间
+	Scroll     bool       // 元素不在视野内时用滚轮分步滚动过去
+	TypeMaxLen int        // 不超过该字数的文本逐字输入，0 表示总是直接填充
+	KeyDelay   HumanDelay // 按键间隔
+	WordPause  HumanDelay // 空格和标点后的额外停顿
+}
+
+var humanProfiles = map[HumanPreset]HumanProfile{
+	HumanPresetOff: {},
+	HumanPresetLight: {
+		ThinkTime: HumanDelay{Median: 300 * time.Millisecond, Sigma: 0.4, Min: 100 * time.Millisecond, Max: time.Second},
+		MoveMouse: true,
+		ClickHold: HumanDelay{Median: 80 * time.Millisecond, Sigma: 0.3, Min: 40 * time.Millisecond, Max: 200 * time.Millisecond},
+	},
+	HumanPresetStandard: {
+		ThinkTime:  HumanDelay{Median: 800 * time.Millisecond, Sigma: 0.5, Min: 300 * time.Millisecond, Max: 3 * time.Second},
+		MoveMouse:  true,
+		ClickHold:  HumanDelay{Median: 90 * time.Millisecond, Sigma: 0.3, Min: 40 * time.Millisecond, Max: 250 * time.Millisecond},
+		Scroll:     true,
+		TypeMaxLen: 50,
+		KeyDelay:   HumanDelay{Median: 120 * time.Millisecond, Sigma: 0.35, Min: 40 * time.Millisecond, Max: 400 * time.Millisecond},
+		WordPause:  HumanDelay{Median: 150 * time.Millisecond, Sigma: 0.5, Min: 50 * time.Millisecond, Max: 800 * time.Millisecond},
+	},
+	HumanPresetCareful: {
+		ThinkTime:  HumanDelay{Median: 1500 * time.Millisecond, Sigma: 0.5, Min: 500 * time.Millisecond, Max: 6 * time.Second},
+		MoveMouse:  true,
+		ClickHold:  HumanDelay{Median: 100 * time.Millisecond, Sigma: 0.3, Min: 50 * time.Millisecond, Max: 300 * time.Millisecond},
+		Scroll:     true,
+		TypeMaxLen: 300,
+		KeyDelay:   HumanDelay{Median: 150 * time.Millisecond, Sigma: 0.4, Min: 50 * time.Millisecond, Max: 600 * time.Millisecond},
+		WordPause:  HumanDelay{Median: 300 * time.Millisecond, Sigma: 0.6, Min: 80 * time.Millisecond, Max: 2 * time.Second},
+	},
+}
+
+// Profile 预设对应的交互参数
+func (p HumanPreset) Profile() HumanProfile {
+	return humanProfiles[p]
+}
+
+// off 是否直接操作（不做任何模拟）
+func (p HumanProfile) off() bool {
+	return p == HumanProfile{}
+}
+
+// shouldType 文本是否逐字输入
+// 含 #、@ 的文本会触发话题/提及联想，含换行的文本在单行输入框中会触发提交，这些文本总是直接填充
+func (p HumanProfile) shouldType(text string) bool {
+	if p.TypeMaxLen <= 0 || text == "" || strings.ContainsAny(text, "#@\n") {
+		return false
+	}
+	return utf8.RuneCountInString(text) <= p.TypeMaxLen
+}
+
+// humanLocateTimeout 模拟前定位元素、输入前点击聚焦的超时（毫秒），超时后退回直接操作，由 Playwright 按原有超时等待元素
+const humanLocateTimeout = 3000
+
+// Human 平台交互层：按平台配置的预设执行点击和输入（鼠标移动、滚动、输入节奏和思考停顿）
+// 每次操作时读取浏览器池配置，修改预设后立即生效
+type Human struct {
+	platform string
+}
+
+// HumanFor 获取平台的交互层
+func HumanFor(platform string) *Human {
+	return &Human{platform: platform}
+}
+
+// Profile 平台当前使用的交互参数
+func (h *Human) Profile() HumanProfile {
+	return LoadPoolConfig().HumanPresetFor(h.platform).Profile()
+}
+
+// Think 操作间的思考停顿（off 预设不停顿）
+func (h *Human) Think() {
+	h.Profile().ThinkTime.sleep()
+}
+
+// Click 点击元素，替代 Locator.Click
+func (h *Human) Click(target playwright.Locator, options ...playwright.LocatorClickOptions) error {
+	profile := h.Profile()
+	if profile.off() {
+		return target.Click(options...)
+	}
+	return h.click(target, profile, options...)
+}
+
+func (h *Human) click(target playwright.Locator, profile HumanProfile, options ...playwright.LocatorClickOptions) error {
+	var opts playwright.LocatorClickOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	profile.ThinkTime.sleep()
+	if position := approach(target, profile); position != nil && opts.Position == nil {
+		opts.Position = position
+	}
+	if opts.Delay == nil && profile.ClickHold.Median > 0 {
+		opts.Delay = playwright.Float(float64(profile.ClickHold.Sample().Milliseconds()))
+	}
+	return target.Click(opts)
+}
+
+// Fill 填写自由文本输入框（标题、描述、标签），替代 Locator.Fill：先点击输入框，短文本按预设逐字输入，其余直接填充
+// 日期/时间等格式化输入使用 FillValue
+func (h *Human) Fill(target playwright.Locator, text string, options ...playwright.LocatorFillOptions) error {
+	profile := h.Profile()
+	if profile.off() {
+		return target.Fill(text, options...)
+	}
+
+	// 点击失败（如输入框被遮挡）时仍由 Fill 直接聚焦填写
+	focus := playwright.LocatorClickOptions{Timeout: playwright.Float(humanLocateTimeout)}
+	if err := h.click(target, profile, focus); err != nil || !profile.shouldType(text) {
+		return target.Fill(text, options...)
+	}
+
+	page, err := target.Page()
+	if err != nil {
+		return target.Fill(text, options...)
+	}
+	if err := target.Fill("", options...); err != nil {
+		return err
+	}
+	return typeRunes(page, text, profile)
+}
+
+// Type 向已聚焦的元素（富文本编辑器、话题输入区等无法 Fill 的元素）输入文本，替代 Keyboard.Type / Locator.Type
+// 不超过预设字数的文本按预设节奏逐字输入，其余一次性输入；与 Fill 不同，# 和换行照常按键输入（用于触发话题联想和换行）
+func (h *Human) Type(page playwright.Page, text string, options ...playwright.KeyboardTypeOptions) error {
+	profile := h.Profile()
+	if profile.off() {
+		return page.Keyboard().Type(text, options...)
+	}
+
+	profile.ThinkTime.sleep()
+	if profile.TypeMaxLen <= 0 || utf8.RuneCountInString(text) > profile.TypeMaxLen {
+		return page.Keyboard().Type(text, options...)
+	}
+	return typeRunes(page, text, profile)
+}
+
+// typeRunes 按预设的按键间隔逐字输入，空格和标点后额外停顿
+func typeRunes(page playwright.Page, text string, profile HumanProfile) error {
+	for _, r := range text {
+		if err := page.Keyboard().Type(string(r)); err != nil {
+			return err
+		}
+		profile.KeyDelay.sleep()
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			profile.WordPause.sleep()
+		}
+	}
+	return nil
+}
+
+// FillValue 填写格式化输入框（日期/时间选择器、链接等），替代 Locator.Fill
+// 只做操作前的思考停顿，不点击聚焦也不逐字输入：逐字输入会触发选择器按键解析，填出错误的值
+func (h *Human) FillValue(target playwright.Locator, text string, options ...playwright.LocatorFillOptions) error {
+	h.Think()
+	return target.Fill(text, options...)
+}
+
+// humanPoint 视口坐标
+type humanPoint struct {
+	X, Y float64
+}
+
+// mousePositions 各页面鼠标最后的位置，页面关闭时删除
+var mousePositions sync.Map // playwright.Page -> humanPoint
+
+// approach 滚动并移动鼠标到元素内的随机位置，返回相对元素的点击位置；定位失败时返回 nil
+func approach(target playwright.Locator, profile HumanProfile) *playwright.Position {
+	page, err := target.Page()
+	if err != nil {
+		return nil
+	}
+	box, err := target.BoundingBox(playwright.LocatorBoundingBoxOptions{Timeout: playwright.Float(humanLocateTimeout)})
+	if err != nil || box == nil || box.Width < 1 || box.Height < 1 {
+		return nil
+	}
+
+	if profile.Scroll {
+		if scrolled := wheelTo(page, box); scrolled {
+			if box, err = target.BoundingBox(playwright.LocatorBoundingBoxOptions{Timeout: playwright.Float(humanLocateTimeout)}); err != nil || box == nil {
+				return nil
+			}
+		}
+	}
+
+	position := clickPosition(box.Width, box.Height, rand.NormFloat64(), rand.NormFloat64())
+	if profile.MoveMouse {
+		moveMouse(page, humanPoint{box.X + position.X, box.Y + position.Y})
+	}
+	return &position
+}
+
+// clickPosition 元素内的点击位置：以中心为均值的正态分布，限制在元素中间 80% 的范围内
+func clickPosition(width, height, zx, zy float64) playwright.Position {
+	clamp := func(z float64) float64 {
+		return math.Max(-2, math.Min(2, z))
+	}
+	return playwright.Position{
+		X: width/2 + clamp(zx)*width*0.1,
+		Y: height/2 + clamp(zy)*height*0.1,
+	}
+}
+
+// wheelTo 元素不在视野内时用滚轮分步滚动，使元素位于视口上部三分之一处，返回是否滚动过
+func wheelTo(page playwright.Page, box *playwright.Rect) bool {
+	viewport := page.ViewportSize()
+	if viewport == nil {
+		return false
+	}
+	top, bottom := box.Y, box.Y+box.Height
+	if top >= 0 && bottom <= float64(viewport.Height) {
+		return false
+	}
+
+	remaining := top - float64(viewport.Height)/3
+	for i := 0; i < 30 && math.Abs(remaining) > 1; i++ {
+		step := math.Min(math.Abs(remaining), 100+rand.Float64()*150)
+		if remaining < 0 {
+			step = -step
+		}
+		if err := page.Mouse().Wheel(0, step); err != nil {
+			return true
+		}
+		remaining -= step
+		time.Sleep(time.Duration(30+rand.Intn(90)) * time.Millisecond)
+	}
+	return true
+}
+
+// moveMouse 沿贝塞尔曲线将鼠标从上次位置移动到目标位置
+func moveMouse(page playwright.Page, to humanPoint) {
+	from, seen := mousePositions.Load(page)
+	if !seen {
+		page.OnClose(func(p playwright.Page) { mousePositions.Delete(p) })
+		// 未知位置时从目标附近的随机位置出发
+		angle := rand.Float64() * 2 * math.Pi
+		distance := 150 + rand.Float64()*250
+		from = humanPoint{math.Max(0, to.X+math.Cos(angle)*distance), math.Max(0, to.Y+math.Sin(angle)*distance)}
+	}
+	start := from.(humanPoint)
+
+	dx, dy := to.X-start.X, to.Y-start.Y
+	steps := 10 + int(math.Min(30, math.Hypot(dx, dy)/40))
+	c1 := humanPoint{start.X + dx*0.3 + rand.NormFloat64()*math.Abs(dy)*0.2, start.Y + dy*0.3 + rand.NormFloat64()*math.Abs(dx)*0.2}
+	c2 := humanPoint{start.X + dx*0.7 + rand.NormFloat64()*math.Abs(dy)*0.1, start.Y + dy*0.7 + rand.NormFloat64()*math.Abs(dx)*0.1}
+
+	for _, p := range bezierPath(start, c1, c2, to, steps) {
+		if err := page.Mouse().Move(p.X, p.Y); err != nil {
+			break
+		}
+		time.Sleep(time.Duration(4+rand.Intn(12)) * time.Millisecond)
+	}
+	mousePositions.Store(page, to)
+}
+
+// bezierPath 三次贝塞尔曲线上的 steps 个点（不含起点，含终点），起止段较慢（缓入缓出）
+func bezierPath(p0, p1, p2, p3 humanPoint, steps int) []humanPoint {
+	if steps < 1 {
+		steps = 1
+	}
+	points := make([]humanPoint, 0, steps)
+	for i := 1; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		t = t * t * (3 - 2*t) // smoothstep 缓入缓出
+		u := 1 - t
+		points = append(points, humanPoint{
+			X: u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+			Y: u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+		})
+	}
+	return points
+}
@@ -0,0 +1,122 @@
+package browser
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHumanDelayAt(t *testing.T) {
+	d := HumanDelay{Median: 100 * time.Millisecond, Sigma: 0.5, Min: 50 * time.Millisecond, Max: 200 * time.Millisecond}
+	if got := d.at(0); got != 100*time.Millisecond {
+		t.Errorf("median = %v", got)
+	}
+	if got := d.at(-10); got != 50*time.Millisecond {
+		t.Errorf("low tail = %v, want clamped to min", got)
+	}
+	if got := d.at(10); got != 200*time.Millisecond {
+		t.Errorf("high tail = %v, want clamped to max", got)
+	}
+	if got := (HumanDelay{}).at(1); got != 0 {
+		t.Errorf("zero delay = %v", got)
+	}
+}
+
+func TestHumanPresetFor(t *testing.T) {
+	cfg := PoolConfig{
+		HumanPreset: HumanPresetLight,
+		PlatformHumanPresets: map[string]HumanPreset{
+			"douyin":   HumanPresetCareful,
+			"bilibili": "unknown",
+		},
+	}
+	if got := cfg.HumanPresetFor("douyin"); got != HumanPresetCareful {
+		t.Errorf("douyin = %s", got)
+	}
+	for _, platform := range []string{"bilibili", "tencent", ""} {
+		if got := cfg.HumanPresetFor(platform); got != HumanPresetLight {
+			t.Errorf("%q = %s, want light", platform, got)
+		}
+	}
+	if got := (&PoolConfig{}).HumanPresetFor("douyin"); got != HumanPresetOff {
+		t.Errorf("default = %s, want off", got)
+	}
+}
+
+func TestHumanPresetsCostOrder(t *testing.T) {
+	infos := HumanPresets()
+	if len(infos) != len(humanProfiles) {
+		t.Fatalf("presets listed = %d, profiles = %d", len(infos), len(humanProfiles))
+	}
+	if !infos[0].Preset.Profile().off() {
+		t.Error("first preset should be off")
+	}
+	for i := 1; i < len(infos); i++ {
+		prev, cur := infos[i-1].Preset.Profile(), infos[i].Preset.Profile()
+		if !infos[i].Preset.Valid() || cur.off() {
+			t.Errorf("%s should be a valid non-off preset", infos[i].Preset)
+		}
+		if cur.ThinkTime.Median < prev.ThinkTime.Median || cur.TypeMaxLen < prev.TypeMaxLen {
+			t.Errorf("%s should cost at least as much as %s", infos[i].Preset, infos[i-1].Preset)
+		}
+	}
+}
+
+func TestShouldType(t *testing.T) {
+	p := HumanPresetStandard.Profile()
+	cases := map[string]bool{
+		"周末去爬山":                             true,
+		"":                                  false,
+		"带话题 #旅行":                           false,
+		"提到 @朋友":                            false,
+		"第一行\n第二行":                          false,
+		strings.Repeat("长", p.TypeMaxLen+1): false,
+	}
+	for text, want := range cases {
+		if got := p.shouldType(text); got != want {
+			t.Errorf("shouldType(%q) = %v, want %v", text, got, want)
+		}
+	}
+	if HumanPresetLight.Profile().shouldType("短标题") {
+		t.Error("light preset should always fill")
+	}
+}
+
+func TestClickPosition(t *testing.T) {
+	for _, z := range []float64{-100, -1, 0, 1, 100} {
+		pos := clickPosition(200, 40, z, -z)
+		if pos.X < 200*0.3 || pos.X > 200*0.7 || pos.Y < 40*0.3 || pos.Y > 40*0.7 {
+			t.Errorf("z=%v: position %+v outside the middle of the element", z, pos)
+		}
+	}
+	if pos := clickPosition(200, 40, 0, 0); pos.X != 100 || pos.Y != 20 {
+		t.Errorf("center = %+v", pos)
+	}
+}
+
+func TestBezierPath(t *testing.T) {
+	from, to := humanPoint{0, 0}, humanPoint{300, 120}
+	path := bezierPath(from, humanPoint{50, 200}, humanPoint{250, -50}, to, 20)
+	if len(path) != 20 {
+		t.Fatalf("len = %d", len(path))
+	}
+	last := path[len(path)-1]
+	if math.Abs(last.X-to.X) > 1e-9 || math.Abs(last.Y-to.Y) > 1e-9 {
+		t.Errorf("path ends at %+v, want %+v", last, to)
+	}
+	// 缓入缓出：首尾两步比中间一步短
+	step := func(i int) float64 {
+		prev := from
+		if i > 0 {
+			prev = path[i-1]
+		}
+		return math.Hypot(path[i].X-prev.X, path[i].Y-prev.Y)
+	}
+	if step(0) >= step(10) || step(19) >= step(10) {
+		t.Errorf("steps not eased: first %.1f, middle %.1f, last %.1f", step(0), step(10), step(19))
+	}
+	if got := bezierPath(from, from, to, to, 0); len(got) != 1 {
+		t.Errorf("steps=0 len = %d, want 1", len(got))
+	}
+}
@@ -0,0 +1,407 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"Fuploader/internal/utils"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// probeTimeout 查看池状态时单个页面的探测超时（卡住的页面不阻塞状态查询）
+const probeTimeout = 2 * time.Second
+
+// jsHeapScript 读取页面 JS 堆内存（Chromium 的 performance.memory）
+const jsHeapScript = `() => (performance.memory ? performance.memory.usedJSHeapSize : 0)`
+
+var poolObjectID atomic.Uint64
+
+// PoolSnapshot 浏览器池实时状态
+type PoolSnapshot struct {
+	Stats      PoolStats     `json:"stats"`
+	Browsers   []BrowserInfo `json:"browsers"`
+	CapturedAt time.Time     `json:"captured_at"`
+}
+
+// BrowserInfo 浏览器实例状态
+type BrowserInfo struct {
+	ID           string        `json:"id"`
+	Persistent   bool          `json:"persistent"`    // 持久化用户数据目录模式（单账号浏览器）
+	Remote       string        `json:"remote"`        // 连接的远程浏览器端点（不含路径和参数），本机启动时为空
+	Version      string        `json:"version"`       // 浏览器版本
+	Healthy      bool          `json:"healthy"`       // 浏览器进程仍连接
+	ContextCount int           `json:"context_count"` // 上下文数
+	InUse        int           `json:"in_use"`        // 使用中的上下文数
+	LastUsed     time.Time     `json:"last_used"`
+	JSHeapBytes  int64         `json:"js_heap_bytes"` // 所有页面 JS 堆内存之和
+	Contexts     []ContextInfo `json:"contexts"`
+}
+
+// ContextInfo 浏览器上下文状态
+type ContextInfo struct {
+	ID          string    `json:"id"`
+	AccountID   uint      `json:"account_id"`
+	Platform    string    `json:"platform"`
+	CookiePath  string    `json:"cookie_path"`
+	InUse       bool      `json:"in_use"`
+	TaskID      int       `json:"task_id"`     // 占用上下文的上传任务，0 为非上传任务或空闲
+	AcquiredAt  time.Time `json:"acquired_at"` // 最近一次获取时间
+	WaitMs      int64     `json:"wait_ms"`     // 最近一次获取耗时（毫秒）
+	CreatedAt   time.Time `json:"created_at"`
+	LastUsed    time.Time `json:"last_used"`
+	AgeSeconds  int64     `json:"age_seconds"`
+	IdleSeconds int64     `json:"idle_seconds"`
+	Proxy       string    `json:"proxy"`     // 使用的代理，直连为空
+	Dedicated   bool      `json:"dedicated"` // 记录 HAR/录屏的独立上下文（释放后关闭）
+	Pages       int       `json:"pages"`
+	PageURL     string    `json:"page_url"`
+	Healthy     bool      `json:"healthy"`
+	HealthError string    `json:"health_error"`
+	JSHeapBytes int64     `json:"js_heap_bytes"`
+}
+
+type taskIDKey struct{}
+
+// WithTaskID 将上传任务 ID 放入 context，浏览器池据此记录上下文的占用任务
+func WithTaskID(ctx context.Context, taskID int) context.Context {
+	return context.WithValue(ctx, taskIDKey{}, taskID)
+}
+
+func taskIDFrom(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	id, _ := ctx.Value(taskIDKey{}).(int)
+	return id
+}
+
+// beginAcquire 开始获取上下文，计入等待数
+func (p *Pool) beginAcquire() time.Time {
+	p.waiting.Add(1)
+	return time.Now()
+}
+
+// endAcquire 获取结束，记录耗时和上下文的占用信息
+func (p *Pool) endAcquire(ctx context.Context, c *PooledContext, start time.Time) {
+	p.waiting.Add(-1)
+	wait := time.Since(start)
+	p.lastWait.Store(int64(wait))
+	for {
+		max := p.maxWait.Load()
+		if int64(wait) <= max || p.maxWait.CompareAndSwap(max, int64(wait)) {
+			break
+		}
+	}
+	if c != nil {
+		c.parent.mutex.Lock()
+		c.inUse = true
+		c.taskID = taskIDFrom(ctx)
+		c.acquiredAt = time.Now()
+		c.waitTime = wait
+		c.parent.mutex.Unlock()
+	}
+	p.updateStats()
+}
+
+// Snapshot 列出所有浏览器和上下文的实时状态（探测页面响应和内存，卡住的页面在超时后标记为不健康）
+func (p *Pool) Snapshot() *PoolSnapshot {
+	p.updateStats()
+	snapshot := &PoolSnapshot{
+		Stats:      p.GetStats(),
+		Browsers:   make([]BrowserInfo, 0),
+		CapturedAt: time.Now(),
+	}
+
+	type probe struct {
+		info  *ContextInfo
+		pages []playwright.Page
+	}
+	var probes []probe
+
+	browsers := p.allBrowsers()
+	snapshot.Browsers = make([]BrowserInfo, len(browsers))
+	for i, b := range browsers {
+		b.mutex.Lock()
+		if b.id == 0 {
+			b.id = poolObjectID.Add(1)
+		}
+		info := BrowserInfo{
+			ID:           "b" + strconv.FormatUint(b.id, 10),
+			Persistent:   b.persistent,
+			ContextCount: len(b.contexts),
+			InUse:        b.inUse,
+			LastUsed:     b.lastUsed,
+			Contexts:     make([]ContextInfo, len(b.contexts)),
+		}
+		if b.remote != nil {
+			info.Remote = endpointHost(b.remote.Endpoint)
+		}
+		if b.persistent {
+			info.Healthy = !b.closed.Load()
+		} else if b.browser != nil {
+			info.Healthy = b.browser.IsConnected()
+			info.Version = b.browser.Version()
+		}
+		for j, c := range b.contexts {
+			if c.id == 0 {
+				c.id = poolObjectID.Add(1)
+			}
+			if c.lastUsed.After(info.LastUsed) {
+				info.LastUsed = c.lastUsed
+			}
+			info.Contexts[j] = ContextInfo{
+				ID:          "c" + strconv.FormatUint(c.id, 10),
+				AccountID:   c.accountID,
+				Platform:    c.platform,
+				CookiePath:  c.cookiePath,
+				InUse:       c.inUse,
+				TaskID:      c.taskID,
+				AcquiredAt:  c.acquiredAt,
+				WaitMs:      c.waitTime.Milliseconds(),
+				CreatedAt:   c.createdAt,
+				LastUsed:    c.lastUsed,
+				AgeSeconds:  int64(time.Since(c.createdAt).Seconds()),
+				IdleSeconds: int64(time.Since(c.lastUsed).Seconds()),
+				Proxy:       c.proxyServer,
+				Dedicated:   c.closeOnRelease,
+			}
+			// Pages 只读取本地状态，页面探测在释放锁后并发进行
+			probes = append(probes, probe{info: &info.Contexts[j], pages: c.context.Pages()})
+		}
+		b.mutex.Unlock()
+		snapshot.Browsers[i] = info
+	}
+
+	var wg sync.WaitGroup
+	for _, pr := range probes {
+		wg.Add(1)
+		go func(pr probe) {
+			defer wg.Done()
+			probeContext(pr.info, pr.pages)
+		}(pr)
+	}
+	wg.Wait()
+
+	for i := range snapshot.Browsers {
+		for _, c := range snapshot.Browsers[i].Contexts {
+			snapshot.Browsers[i].JSHeapBytes += c.JSHeapBytes
+		}
+	}
+	return snapshot
+}
+
+// probeContext 探测上下文中各页面的响应和 JS 堆内存
+func probeContext(info *ContextInfo, pages []playwright.Page) {
+	info.Pages = len(pages)
+	info.Healthy = true
+	var errs []string
+	for _, page := range pages {
+		if page.IsClosed() {
+			continue
+		}
+		if info.PageURL == "" {
+			info.PageURL = page.URL()
+		}
+		heap, err := evaluateWithTimeout(page, jsHeapScript, probeTimeout)
+		if err != nil {
+			info.Healthy = false
+			errs = append(errs, err.Error())
+			continue
+		}
+		info.JSHeapBytes += heap
+	}
+	info.HealthError = strings.Join(errs, "; ")
+}
+
+// evaluateWithTimeout 在页面执行脚本，超时视为页面无响应
+func evaluateWithTimeout(page playwright.Page, script string, timeout time.Duration) (int64, error) {
+	type result struct {
+		value interface{}
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := page.Evaluate(script)
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return 0, fmt.Errorf("页面无响应: %w", r.err)
+		}
+		switch v := r.value.(type) {
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		case float64:
+			return int64(v), nil
+		}
+		return 0, nil
+	case <-time.After(timeout):
+		return 0, fmt.Errorf("页面 %s 内无响应", timeout)
+	}
+}
+
+// findContext 按编号查找上下文（调用方持有池锁）
+func (p *Pool) findContext(id string) (*PooledBrowser, *PooledContext) {
+	for _, b := range append(append([]*PooledBrowser{}, p.browsers...), p.persistent...) {
+		b.mutex.Lock()
+		for _, c := range b.contexts {
+			if c.id != 0 && "c"+strconv.FormatUint(c.id, 10) == id {
+				b.mutex.Unlock()
+				return b, c
+			}
+		}
+		b.mutex.Unlock()
+	}
+	return nil, nil
+}
+
+// contains 浏览器是否仍在池中（调用方持有池锁）
+func (p *Pool) contains(browser *PooledBrowser) bool {
+	for _, b := range p.browsers {
+		if b == browser {
+			return true
+		}
+	}
+	for _, b := range p.persistent {
+		if b == browser {
+			return true
+		}
+	}
+	return false
+}
+
+// findBrowser 按编号查找浏览器（调用方持有池锁）
+func (p *Pool) findBrowser(id string) *PooledBrowser {
+	for _, b := range append(append([]*PooledBrowser{}, p.browsers...), p.persistent...) {
+		b.mutex.Lock()
+		matched := b.id != 0 && "b"+strconv.FormatUint(b.id, 10) == id
+		b.mutex.Unlock()
+		if matched {
+			return b
+		}
+	}
+	return nil
+}
+
+// ForceCloseContext 强制关闭卡住的上下文，持有方之后的 Release 直接返回错误
+// 持久化上下文会连同其浏览器进程一起关闭
+func (p *Pool) ForceCloseContext(id string) error {
+	p.mutex.Lock()
+	b, c := p.findContext(id)
+	if c == nil {
+		p.mutex.Unlock()
+		return fmt.Errorf("browser context %s not found", id)
+	}
+
+	b.mutex.Lock()
+	c.forceClosed = true
+	if c.inUse {
+		c.inUse = false
+		b.inUse--
+	}
+	if !b.persistent {
+		c.removeFromParent()
+	}
+	b.mutex.Unlock()
+
+	if b.persistent {
+		finish := p.detachPersistent(b)
+		p.updateStats()
+		p.mutex.Unlock()
+		// 保存 Cookie 和关闭浏览器进程在池锁外进行
+		finish()
+		utils.Info(fmt.Sprintf("[-] [%s] 已强制关闭持久化浏览器 %s", c.platform, id))
+		return nil
+	}
+	p.updateStats()
+	p.mutex.Unlock()
+
+	// 关闭可能较慢（页面卡住时），在池锁外进行
+	c.Close()
+	utils.Info(fmt.Sprintf("[-] [%s] 已强制关闭浏览器上下文 %s（任务 %d）", c.platform, id, c.taskID))
+	return nil
+}
+
+// RestartBrowser 重启浏览器实例，其中的上下文全部关闭（持久化浏览器关闭后在下次使用时重新启动）
+func (p *Pool) RestartBrowser(id string) error {
+	p.mutex.RLock()
+	b := p.findBrowser(id)
+	p.mutex.RUnlock()
+	if b == nil {
+		return fmt.Errorf("browser %s not found", id)
+	}
+	return p.restartBrowser(b)
+}
+
+// restartBrowser 关闭浏览器实例及其上下文并启动新实例替换，实例已不在池中时不做处理
+func (p *Pool) restartBrowser(oldBrowser *PooledBrowser) error {
+	p.mutex.Lock()
+	if !p.contains(oldBrowser) {
+		p.mutex.Unlock()
+		return nil
+	}
+
+	// 持有方之后的 Release 不再重复处理占用计数
+	oldBrowser.mutex.Lock()
+	contexts := append([]*PooledContext(nil), oldBrowser.contexts...)
+	for _, ctx := range contexts {
+		ctx.forceClosed = true
+		ctx.inUse = false
+	}
+	oldBrowser.inUse = 0
+	oldBrowser.mutex.Unlock()
+
+	// 持久化浏览器不在此重启，移出池后由下次获取上下文时按需启动；保存 Cookie 和关闭在池锁外进行
+	if oldBrowser.persistent {
+		finish := p.detachPersistent(oldBrowser)
+		p.updateStats()
+		p.mutex.Unlock()
+		finish()
+		utils.Info("[-] 已移除失效的持久化浏览器")
+		return nil
+	}
+	defer p.mutex.Unlock()
+	defer p.updateStats()
+
+	// 关闭旧的浏览器实例
+	for _, ctx := range contexts {
+		ctx.Close()
+	}
+	if err := oldBrowser.browser.Close(); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 关闭旧浏览器失败: %v", err))
+	}
+
+	// 从池中移除
+	for i, b := range p.browsers {
+		if b == oldBrowser {
+			p.browsers = append(p.browsers[:i], p.browsers[i+1:]...)
+			break
+		}
+	}
+
+	// 创建新的浏览器实例
+	newBrowser, err := p.launchBrowser(oldBrowser.remote)
+	if err != nil {
+		return fmt.Errorf("启动新浏览器失败: %w", err)
+	}
+
+	pooled := &PooledBrowser{
+		browser:  newBrowser,
+		contexts: make([]*PooledContext, 0),
+		remote:   oldBrowser.remote,
+	}
+	p.browsers = append(p.browsers, pooled)
+
+	utils.Info("[-] 浏览器实例已重启")
+	return nil
+}
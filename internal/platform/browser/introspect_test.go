@@ -0,0 +1,59 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEndAcquireRecordsHolder(t *testing.T) {
+	p := NewPool(1, 1)
+	parent := &PooledBrowser{}
+	c := &PooledContext{parent: parent, platform: "douyin"}
+	parent.contexts = []*PooledContext{c}
+	p.browsers = []*PooledBrowser{parent}
+
+	start := p.beginAcquire()
+	if got := p.waiting.Load(); got != 1 {
+		t.Fatalf("waiting = %d", got)
+	}
+	p.endAcquire(WithTaskID(context.Background(), 42), c, start.Add(-1500*time.Millisecond))
+
+	if !c.inUse || c.taskID != 42 || c.waitTime < 1500*time.Millisecond || c.acquiredAt.IsZero() {
+		t.Errorf("holder not recorded: inUse=%v task=%d wait=%v", c.inUse, c.taskID, c.waitTime)
+	}
+	stats := p.GetStats()
+	if stats.WaitQueueLength != 0 || stats.MaxWaitMs < 1500 || stats.LastWaitMs < 1500 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// 失败的获取只计入耗时
+	p.endAcquire(context.Background(), nil, time.Now())
+	if p.GetStats().MaxWaitMs < 1500 {
+		t.Error("max wait overwritten by shorter acquisition")
+	}
+}
+
+func TestFindContextAndRemoveFromParent(t *testing.T) {
+	p := NewPool(1, 1)
+	parent := &PooledBrowser{id: 3}
+	c := &PooledContext{parent: parent, platform: "tencent", id: 7}
+	parent.contexts = []*PooledContext{c}
+	parent.incrementPlatformCount("tencent")
+	p.browsers = []*PooledBrowser{parent}
+
+	if b, found := p.findContext("c7"); found != c || b != parent {
+		t.Fatalf("findContext = %v, %v", b, found)
+	}
+	if p.findBrowser("b3") != parent || p.findBrowser("b4") != nil {
+		t.Error("findBrowser mismatch")
+	}
+
+	// 持有父浏览器锁时移除不能死锁
+	parent.mutex.Lock()
+	c.removeFromParent()
+	parent.mutex.Unlock()
+	if len(parent.contexts) != 0 || parent.hasPlatformContext("tencent") {
+		t.Errorf("context not removed: %d, %v", len(parent.contexts), parent.platformContext)
+	}
+}
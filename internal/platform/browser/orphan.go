@@ -0,0 +1,181 @@
+package browser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ownerFlag 浏览器池启动的 Chromium 带有该参数，值为应用进程 PID，用于识别应用崩溃后遗留的浏览器进程
+// Chromium 忽略不认识的命令行参数，子进程（渲染进程等）不继承该参数
+const ownerFlag = "--fuploader-owner="
+
+// launchArgs 浏览器启动参数，附带应用进程标记
+func launchArgs() []string {
+	args := make([]string, 0, len(browserLaunchArgs)+1)
+	args = append(args, browserLaunchArgs...)
+	return append(args, ownerFlag+strconv.Itoa(os.Getpid()))
+}
+
+// processInfo 进程 PID 和命令行
+type processInfo struct {
+	PID     int
+	Command string
+}
+
+// KillOrphanBrowsers 结束上次运行遗留的浏览器进程（启动它们的应用进程已不存在），返回结束的进程数
+// 应用启动时、浏览器池启动浏览器之前调用
+func KillOrphanBrowsers() (int, error) {
+	processes, err := listProcesses()
+	if err != nil {
+		return 0, err
+	}
+
+	killed := 0
+	var lastErr error
+	for _, proc := range orphanBrowsers(processes, os.Getpid(), processAlive) {
+		process, err := os.FindProcess(proc.PID)
+		if err != nil {
+			continue
+		}
+		if err := process.Kill(); err != nil {
+			lastErr = fmt.Errorf("kill orphan browser %d failed: %w", proc.PID, err)
+			continue
+		}
+		killed++
+	}
+	return killed, lastErr
+}
+
+// orphanBrowsers 筛选遗留的浏览器进程：带有应用标记，且标记的应用进程不是当前进程、已不存在
+func orphanBrowsers(processes []processInfo, selfPID int, alive func(int) bool) []processInfo {
+	var orphans []processInfo
+	for _, proc := range processes {
+		owner := parseOwner(proc.Command)
+		if owner == 0 || owner == selfPID || proc.PID == selfPID || alive(owner) {
+			continue
+		}
+		orphans = append(orphans, proc)
+	}
+	return orphans
+}
+
+// parseOwner 从命令行解析应用进程标记，没有标记时返回 0
+func parseOwner(command string) int {
+	i := strings.Index(command, ownerFlag)
+	if i < 0 {
+		return 0
+	}
+	value := command[i+len(ownerFlag):]
+	if end := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+		value = value[:end]
+	}
+	pid, _ := strconv.Atoi(value)
+	return pid
+}
+
+// processAlive 进程是否仍在运行（Windows 上 FindProcess 只对存在的进程成功）
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		process.Release()
+		return true
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// listProcesses 列出系统进程及命令行
+func listProcesses() ([]processInfo, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return listProcProcesses()
+	case "windows":
+		cmd := exec.Command("powershell", "-NoProfile", "-Command",
+			"Get-CimInstance Win32_Process | Select-Object ProcessId,CommandLine | ConvertTo-Csv -NoTypeInformation")
+		hideWindow(cmd)
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("list processes failed: %w", err)
+		}
+		return parseWindowsProcesses(out)
+	default:
+		out, err := exec.Command("ps", "-axo", "pid=,command=").Output()
+		if err != nil {
+			return nil, fmt.Errorf("list processes failed: %w", err)
+		}
+		return parsePSProcesses(out), nil
+	}
+}
+
+// listProcProcesses 读取 /proc 下各进程的命令行
+func listProcProcesses() ([]processInfo, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("list processes failed: %w", err)
+	}
+	var processes []processInfo
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		cmdline, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue
+		}
+		processes = append(processes, processInfo{PID: pid, Command: string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '}))})
+	}
+	return processes, nil
+}
+
+// parsePSProcesses 解析 ps -o pid=,command= 输出
+func parsePSProcesses(out []byte) []processInfo {
+	var processes []processInfo
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.SplitN(strings.TrimSpace(scanner.Text()), " ", 2)
+		if len(fields) < 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		processes = append(processes, processInfo{PID: pid, Command: strings.TrimSpace(fields[1])})
+	}
+	return processes
+}
+
+// parseWindowsProcesses 解析 PowerShell ConvertTo-Csv 输出（"ProcessId","CommandLine"）
+func parseWindowsProcesses(out []byte) ([]processInfo, error) {
+	reader := csv.NewReader(bytes.NewReader(out))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse process list failed: %w", err)
+	}
+	var processes []processInfo
+	for i, record := range records {
+		if i == 0 || len(record) < 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			continue
+		}
+		processes = append(processes, processInfo{PID: pid, Command: record[1]})
+	}
+	return processes, nil
+}
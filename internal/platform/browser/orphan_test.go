@@ -0,0 +1,71 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOwner(t *testing.T) {
+	cases := map[string]int{
+		"/opt/chromium/chrome --no-sandbox --fuploader-owner=1234 --remote-debugging-pipe": 1234,
+		"/opt/chromium/chrome --fuploader-owner=42":                                        42,
+		"/opt/chromium/chrome --no-sandbox":                                                0,
+		"/opt/chromium/chrome --fuploader-owner=":                                          0,
+	}
+	for command, want := range cases {
+		if got := parseOwner(command); got != want {
+			t.Errorf("parseOwner(%q) = %d, want %d", command, got, want)
+		}
+	}
+}
+
+func TestLaunchArgsIncludesOwner(t *testing.T) {
+	args := launchArgs()
+	if owner := parseOwner(strings.Join(args, " ")); owner == 0 {
+		t.Fatalf("launch args missing owner flag: %v", args)
+	}
+	if len(args) != len(browserLaunchArgs)+1 {
+		t.Errorf("len(args) = %d, want %d", len(args), len(browserLaunchArgs)+1)
+	}
+}
+
+func TestOrphanBrowsers(t *testing.T) {
+	processes := []processInfo{
+		{PID: 10, Command: "chrome --fuploader-owner=100"}, // 当前进程启动的浏览器
+		{PID: 11, Command: "chrome --fuploader-owner=200"}, // 其他仍在运行的实例
+		{PID: 12, Command: "chrome --fuploader-owner=300"}, // 应用已退出
+		{PID: 13, Command: "chrome --type=renderer"},       // 不带标记
+		{PID: 100, Command: "fuploader"},
+	}
+	alive := func(pid int) bool { return pid == 100 || pid == 200 }
+
+	orphans := orphanBrowsers(processes, 100, alive)
+	if len(orphans) != 1 || orphans[0].PID != 12 {
+		t.Errorf("orphans = %+v, want only pid 12", orphans)
+	}
+}
+
+func TestParsePSProcesses(t *testing.T) {
+	out := []byte("    1 /sbin/init\n  523 /opt/chrome --fuploader-owner=7 --headless\n\nbad line\n")
+	processes := parsePSProcesses(out)
+	if len(processes) != 2 {
+		t.Fatalf("len = %d, want 2: %+v", len(processes), processes)
+	}
+	if processes[1].PID != 523 || processes[1].Command != "/opt/chrome --fuploader-owner=7 --headless" {
+		t.Errorf("process = %+v", processes[1])
+	}
+}
+
+func TestParseWindowsProcesses(t *testing.T) {
+	out := []byte("\"ProcessId\",\"CommandLine\"\r\n\"4\",\"\"\r\n\"812\",\"\"\"C:\\chrome.exe\"\" --fuploader-owner=99\"\r\n")
+	processes, err := parseWindowsProcesses(out)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(processes) != 2 {
+		t.Fatalf("len = %d, want 2: %+v", len(processes), processes)
+	}
+	if processes[1].PID != 812 || parseOwner(processes[1].Command) != 99 {
+		t.Errorf("process = %+v", processes[1])
+	}
+}
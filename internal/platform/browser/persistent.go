@@ -0,0 +1,353 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"Fuploader/internal/config"
+	"Fuploader/internal/platform/vault"
+	"Fuploader/internal/utils"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// UserDataDir 账号的 Chrome 用户数据目录（按 Cookie 文件名区分账号，如 storage/userdata/tencent_3）
+func UserDataDir(cookiePath string) string {
+	name := strings.TrimSuffix(filepath.Base(cookiePath), filepath.Ext(cookiePath))
+	return filepath.Join(config.Config.UserDataPath, name)
+}
+
+// RemoveUserDataDir 删除账号的用户数据目录（重新登录或删除账号时调用）
+func RemoveUserDataDir(cookiePath string) error {
+	if cookiePath == "" {
+		return nil
+	}
+	return os.RemoveAll(UserDataDir(cookiePath))
+}
+
+// platformFromCookiePath 从 Cookie 文件名（平台_账号ID.json）解析平台
+func platformFromCookiePath(cookiePath string) string {
+	name := strings.TrimSuffix(filepath.Base(cookiePath), filepath.Ext(cookiePath))
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// usePersistent 是否为该账号使用持久化用户数据目录模式（需要能定位到账号的 Cookie 路径）
+func usePersistent(platform, cookiePath string) bool {
+	if cookiePath == "" {
+		return false
+	}
+	if platform == "" {
+		platform = platformFromCookiePath(cookiePath)
+	}
+	// 用户数据目录只能由本机启动的浏览器打开，配置了远程浏览器的平台使用 storage_state
+	cfg := LoadPoolConfig()
+	return cfg.StorageModeFor(platform) == StorageModePersistent && cfg.RemoteFor(platform) == nil
+}
+
+// getOrLaunchPersistent 获取账号的持久化上下文
+// 同一用户数据目录只能被一个浏览器进程打开，因此已打开的上下文总是直接复用（不受复用模式和空闲时间限制）
+// 启动、保存 Cookie 和关闭浏览器进程较慢，在池锁外进行；期间用户数据目录被预留，其他调用等待完成
+func (p *Pool) getOrLaunchPersistent(ctx context.Context, accountID uint, platform string, cookiePath string, options *ContextOptions) (*PooledContext, error) {
+	if options == nil {
+		options = DefaultContextOptions()
+	}
+	if platform == "" {
+		platform = platformFromCookiePath(cookiePath)
+	}
+	options = p.fingerprintOptions(accountID, platform, cookiePath, options)
+	options, err := withAccountProxy(accountID, cookiePath, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.mutex.Lock()
+		p.prunePersistent()
+
+		// 其他调用正在启动或关闭该用户数据目录的浏览器，等待完成后重新检查
+		if busy, ok := p.persistentBusy[cookiePath]; ok {
+			p.mutex.Unlock()
+			select {
+			case <-busy:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		c, closing, err := p.acquirePersistent(accountID, platform, cookiePath, options)
+		if err != nil || c != nil {
+			p.updateStats()
+			p.mutex.Unlock()
+			for _, finish := range closing {
+				finish()
+			}
+			return c, err
+		}
+
+		// 预留用户数据目录后释放池锁，再关闭需要让出的浏览器并启动新的持久化上下文
+		release := p.reservePersistent(cookiePath)
+		p.mutex.Unlock()
+
+		for _, finish := range closing {
+			finish()
+		}
+		wrapper, pooledCtx, err := p.launchPersistent(accountID, platform, cookiePath, options)
+		if err == nil {
+			p.mutex.Lock()
+			p.persistent = append(p.persistent, wrapper)
+			p.updateStats()
+			p.mutex.Unlock()
+		}
+		release()
+		return pooledCtx, err
+	}
+}
+
+// acquirePersistent 复用已打开的持久化上下文（调用方持有池锁）
+// 需要启动新浏览器时返回 nil；closing 为需要在池锁外执行的关闭操作（代理变更或达到上限时让出的浏览器）
+func (p *Pool) acquirePersistent(accountID uint, platform string, cookiePath string, options *ContextOptions) (*PooledContext, []func(), error) {
+	var closing []func()
+
+	// 1. 复用已打开的持久化上下文
+	for _, b := range p.persistent {
+		c := b.persistentContext()
+		if c == nil || c.cookiePath != cookiePath {
+			continue
+		}
+		b.mutex.Lock()
+		inUse := b.inUse
+		b.mutex.Unlock()
+		if c.proxyServer != proxyServer(options.Proxy) {
+			if inUse > 0 {
+				utils.Warn(fmt.Sprintf("[-] [%s] 账号代理已变更，但持久化浏览器正在使用中，暂时沿用原代理", platform))
+			} else {
+				utils.Info(fmt.Sprintf("[-] [%s] 账号代理已变更，重启持久化浏览器", platform))
+				closing = append(closing, p.detachPersistent(b))
+				break
+			}
+		}
+		b.mutex.Lock()
+		c.lastUsed = time.Now()
+		b.inUse++
+		b.mutex.Unlock()
+		if accountID > 0 {
+			c.accountID = accountID
+		}
+		utils.Info(fmt.Sprintf("[-] 复用持久化上下文 - AccountID: %d, Platform: %s", c.accountID, platform))
+		return c, nil, nil
+	}
+
+	// 2. 达到上限时关闭最久未使用的空闲持久化上下文（正在启动的也计入上限）
+	limit := LoadPoolConfig().MaxPersistentContexts
+	if limit <= 0 {
+		limit = DefaultPoolConfig.MaxPersistentContexts
+	}
+	if len(p.persistent)+len(p.persistentBusy) >= limit {
+		idle := p.lruIdlePersistent()
+		if idle == nil {
+			return nil, closing, fmt.Errorf("持久化浏览器已达上限（%d），且均在使用中", limit)
+		}
+		closing = append(closing, p.detachPersistent(idle))
+	}
+	return nil, closing, nil
+}
+
+// reservePersistent 标记用户数据目录正在启动或关闭（调用方持有池锁），返回在池锁外调用的解除函数
+func (p *Pool) reservePersistent(cookiePath string) func() {
+	if p.persistentBusy == nil {
+		p.persistentBusy = make(map[string]chan struct{})
+	}
+	busy := make(chan struct{})
+	p.persistentBusy[cookiePath] = busy
+	return func() {
+		p.mutex.Lock()
+		if p.persistentBusy[cookiePath] == busy {
+			delete(p.persistentBusy, cookiePath)
+		}
+		p.mutex.Unlock()
+		close(busy)
+	}
+}
+
+// launchPersistent 使用账号的用户数据目录启动持久化上下文，由调用方加入池中（调用方已预留用户数据目录，不持有池锁）
+func (p *Pool) launchPersistent(accountID uint, platform string, cookiePath string, options *ContextOptions) (*PooledBrowser, *PooledContext, error) {
+	dir := UserDataDir(cookiePath)
+	_, statErr := os.Stat(dir)
+	fresh := os.IsNotExist(statErr)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, nil, fmt.Errorf("create user data dir failed: %w", err)
+	}
+
+	pw, err := p.driver()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	launchOptions := playwright.BrowserTypeLaunchPersistentContextOptions{
+		Headless:         playwright.Bool(config.Config.Headless),
+		Args:             launchArgs(),
+		Locale:           playwright.String(options.Locale),
+		TimezoneId:       playwright.String(options.TimezoneId),
+		Permissions:      []string{"geolocation"},
+		ColorScheme:      playwright.ColorSchemeLight,
+		ExtraHttpHeaders: options.ExtraHeaders,
+		Geolocation:      options.Geolocation,
+		Viewport:         options.Viewport,
+		Proxy:            options.Proxy,
+	}
+	if options.UserAgent != "" {
+		launchOptions.UserAgent = playwright.String(options.UserAgent)
+	}
+	if chromePath := findLocalChrome(); chromePath != "" {
+		launchOptions.ExecutablePath = playwright.String(chromePath)
+	}
+
+	browserCtx, err := pw.Chromium.LaunchPersistentContext(dir, launchOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("launch persistent context failed: %w", err)
+	}
+
+	if options.Fingerprint != nil {
+		if err := browserCtx.AddInitScript(playwright.Script{Content: playwright.String(options.Fingerprint.NoiseScript())}); err != nil {
+			browserCtx.Close()
+			return nil, nil, fmt.Errorf("inject fingerprint script failed: %w", err)
+		}
+	}
+
+	// 新建的用户数据目录从 Cookie 文件导入登录态，之后以用户数据目录为准
+	if fresh {
+		if err := seedPersistentCookies(browserCtx, cookiePath); err != nil {
+			utils.Warn(fmt.Sprintf("[-] [%s] 导入 Cookie 到用户数据目录失败: %v", platform, err))
+		}
+	}
+
+	wrapper := &PooledBrowser{
+		contexts:        make([]*PooledContext, 0, 1),
+		platformContext: make(map[string]int),
+		lastUsed:        time.Now(),
+		persistent:      true,
+	}
+	browserCtx.On("close", func() {
+		wrapper.closed.Store(true)
+	})
+
+	pooledCtx := &PooledContext{
+		context:    browserCtx,
+		cookiePath: cookiePath,
+		accountID:  accountID,
+		platform:   platform,
+		createdAt:  time.Now(),
+		lastUsed:   time.Now(),
+		parent:     wrapper,
+
+		proxyServer: proxyServer(options.Proxy),
+	}
+	wrapper.contexts = append(wrapper.contexts, pooledCtx)
+	wrapper.inUse++
+	if platform != "" {
+		wrapper.incrementPlatformCount(platform)
+	}
+
+	utils.Info(fmt.Sprintf("[-] 启动持久化上下文 - AccountID: %d, Platform: %s, UserDataDir: %s", accountID, platform, dir))
+	return wrapper, pooledCtx, nil
+}
+
+// seedPersistentCookies 将 Cookie 文件中的 Cookie 导入持久化上下文
+func seedPersistentCookies(browserCtx playwright.BrowserContext, cookiePath string) error {
+	data, err := vault.ReadFile(cookiePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var state playwright.OptionalStorageState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("parse storage state failed: %w", err)
+	}
+	if len(state.Cookies) == 0 {
+		return nil
+	}
+	return browserCtx.AddCookies(state.Cookies)
+}
+
+// persistentContext 持久化浏览器中唯一的上下文
+func (b *PooledBrowser) persistentContext() *PooledContext {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if len(b.contexts) == 0 {
+		return nil
+	}
+	return b.contexts[0]
+}
+
+// prunePersistent 移除已关闭的持久化浏览器（调用方持有池锁）
+func (p *Pool) prunePersistent() {
+	alive := p.persistent[:0]
+	for _, b := range p.persistent {
+		if b.closed.Load() || b.persistentContext() == nil {
+			continue
+		}
+		alive = append(alive, b)
+	}
+	p.persistent = alive
+}
+
+// lruIdlePersistent 最久未使用的空闲持久化浏览器（调用方持有池锁）
+func (p *Pool) lruIdlePersistent() *PooledBrowser {
+	var oldest *PooledBrowser
+	var oldestUsed time.Time
+	for _, b := range p.persistent {
+		c := b.persistentContext()
+		b.mutex.Lock()
+		idle := b.inUse == 0
+		b.mutex.Unlock()
+		if c == nil || !idle {
+			continue
+		}
+		if oldest == nil || c.lastUsed.Before(oldestUsed) {
+			oldest, oldestUsed = b, c.lastUsed
+		}
+	}
+	return oldest
+}
+
+// detachPersistent 将持久化浏览器移出池并预留其用户数据目录（调用方持有池锁）
+// 返回在池锁外调用的函数：保存 Cookie 后关闭浏览器进程，完成后解除预留
+func (p *Pool) detachPersistent(b *PooledBrowser) func() {
+	c := b.persistentContext()
+	b.closed.Store(true)
+	p.prunePersistent()
+	if c == nil {
+		return func() {}
+	}
+
+	release := p.reservePersistent(c.cookiePath)
+	return func() {
+		defer release()
+		if c.cookiePath != "" {
+			if err := c.SaveCookiesTo(c.cookiePath); err != nil {
+				utils.Warn(fmt.Sprintf("[-] [%s] 保存Cookie失败: %v", c.platform, err))
+			}
+		}
+		c.Close()
+	}
+}
+
+// allBrowsers 普通浏览器和持久化浏览器的快照
+func (p *Pool) allBrowsers() []*PooledBrowser {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	browsers := make([]*PooledBrowser, 0, len(p.browsers)+len(p.persistent))
+	browsers = append(browsers, p.browsers...)
+	return append(browsers, p.persistent...)
+}
@@ -0,0 +1,72 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPlatformFromCookiePath(t *testing.T) {
+	cases := map[string]string{
+		filepath.Join("storage", "cookies", "tencent_3.json"):      "tencent",
+		filepath.Join("storage", "cookies", "xiaohongshu_12.json"): "xiaohongshu",
+		"douyin.json": "",
+		"":            "",
+	}
+	for path, want := range cases {
+		if got := platformFromCookiePath(path); got != want {
+			t.Errorf("platformFromCookiePath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestStorageModeFor(t *testing.T) {
+	cfg := PoolConfig{PlatformStorageModes: map[string]StorageMode{
+		"tencent":     StorageModePersistent,
+		"xiaohongshu": "unknown",
+	}}
+	if got := cfg.StorageModeFor("tencent"); got != StorageModePersistent {
+		t.Errorf("tencent = %s", got)
+	}
+	for _, platform := range []string{"xiaohongshu", "douyin", ""} {
+		if got := cfg.StorageModeFor(platform); got != StorageModeState {
+			t.Errorf("%q = %s, want storage_state", platform, got)
+		}
+	}
+}
+
+func TestGetOrLaunchPersistentWaitsOutsideLock(t *testing.T) {
+	p := NewPool(1, 1)
+	cookiePath := filepath.Join("storage", "cookies", "tencent_3.json")
+
+	// 模拟其他调用正在启动该账号的浏览器
+	p.mutex.Lock()
+	release := p.reservePersistent(cookiePath)
+	p.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.getOrLaunchPersistent(ctx, 3, "tencent", cookiePath, &ContextOptions{})
+		done <- err
+	}()
+
+	// 等待期间不持有池锁，其他操作（如状态页快照）不被阻塞
+	time.Sleep(50 * time.Millisecond)
+	if !p.mutex.TryLock() {
+		t.Fatal("pool lock held while waiting for a reserved user data dir")
+	}
+	p.mutex.Unlock()
+
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+
+	release()
+	if _, busy := p.persistentBusy[cookiePath]; busy {
+		t.Error("reservation not released")
+	}
+}
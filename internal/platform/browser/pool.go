@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
-	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"Fuploader/internal/config"
+	"Fuploader/internal/platform/vault"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
@@ -26,6 +27,11 @@ type PoolStats struct {
 	MaxBrowsers       int       `json:"max_browsers"`         // 最大浏览器数
 	MaxContexts       int       `json:"max_contexts"`         // 每个浏览器的最大上下文数
 	Timestamp         time.Time `json:"timestamp"`            // 统计时间戳
+
+	PersistentCount int `json:"persistent_count"` // 持久化用户数据目录模式下打开的账号浏览器数
+
+	LastWaitMs int64 `json:"last_wait_ms"` // 最近一次获取上下文耗时（毫秒）
+	MaxWaitMs  int64 `json:"max_wait_ms"`  // 获取上下文最长耗时（毫秒）
 }
 
 // Pool 浏览器池
@@ -37,6 +43,16 @@ type Pool struct {
 	waitQueue   chan struct{} // 等待队列，用于限制并发获取上下文
 	stats       PoolStats
 	statsMutex  sync.RWMutex
+
+	persistent     []*PooledBrowser         // 持久化模式的账号浏览器，每个只包含一个上下文
+	persistentBusy map[string]chan struct{} // 正在启动或关闭的持久化浏览器（按 Cookie 路径），完成时关闭通道
+
+	pw          *playwright.Playwright // 共用的 Playwright 驱动
+	driverMutex sync.Mutex
+
+	waiting  atomic.Int32 // 正在获取上下文的调用数
+	lastWait atomic.Int64 // 最近一次获取上下文耗时（纳秒）
+	maxWait  atomic.Int64 // 获取上下文最长耗时（纳秒）
 }
 
 // PlatformCache 平台级资源缓存
@@ -56,6 +72,13 @@ type PooledBrowser struct {
 	lastUsed        time.Time
 	inUse           int
 	mutex           sync.Mutex
+
+	persistent bool        // 持久化模式（LaunchPersistentContext），browser 为空
+	closed     atomic.Bool // 持久化浏览器已关闭
+
+	id uint64 // 池状态查看使用的编号
+
+	remote *RemoteBrowser // 连接的远程浏览器端点，本机启动时为空
 }
 
 // PooledContext 封装的浏览器上下文
@@ -68,6 +91,21 @@ type PooledContext struct {
 	createdAt  time.Time
 	lastUsed   time.Time
 	parent     *PooledBrowser
+
+	proxyServer string // 创建上下文时使用的代理，直连为空
+
+	diagnostics    *Diagnostics // 正在记录 trace 的诊断会话
+	har            *Diagnostics // 记录 HAR 的诊断会话（独立上下文）
+	harPath        string
+	closeOnRelease bool // 释放时关闭上下文（记录 HAR/录屏的独立上下文）
+
+	// 占用信息（池状态查看）
+	id          uint64
+	inUse       bool
+	taskID      int           // 占用上下文的上传任务
+	acquiredAt  time.Time     // 本次获取时间
+	waitTime    time.Duration // 本次获取耗时（含等待锁和启动浏览器）
+	forceClosed bool          // 已被强制关闭，持有方的 Release 不再处理
 }
 
 // ContextOptions 上下文选项
@@ -80,8 +118,18 @@ type ContextOptions struct {
 	ExtraHeaders map[string]string
 	// 反爬相关选项
 	EnableAntiDetect  bool // 启用反检测
-	EnableRandomDelay bool // 启用随机延迟
+	EnableRandomDelay bool // 启用随机延迟（点击和输入的模拟由平台的人类交互预设决定，见 HumanFor）
 	HumanLikeBehavior bool // 模拟人类行为
+
+	// 账号固定指纹（由反检测选项生成，用于注入 Canvas/WebGL 指纹脚本）
+	Fingerprint *Fingerprint
+	// 上下文代理（HTTP/SOCKS5），为空时使用账号绑定的代理，账号未配置时直连
+	Proxy *playwright.Proxy
+	// HAR 记录路径（上下文关闭时写出），为空时不记录
+	RecordHarPath string
+	// 录屏目录（上下文关闭时写出 webm），为空时不录屏
+	RecordVideoDir  string
+	RecordVideoSize *playwright.Size
 }
 
 // DefaultContextOptions 返回默认上下文选项（带反爬配置）
@@ -115,7 +163,71 @@ func (p *Pool) GetContext(ctx context.Context, cookiePath string, options *Conte
 }
 
 // GetContextByAccount 通过accountID获取浏览器上下文（新接口，根据配置选择复用策略）
+// ctx 中带有诊断会话（WithDiagnostics）时为上下文开启 trace/HAR 记录，带有录屏（WithRecording）时录制会话
 func (p *Pool) GetContextByAccount(ctx context.Context, accountID uint, cookiePath string, options *ContextOptions) (*PooledContext, error) {
+	diagnostics := diagnosticsFrom(ctx)
+	recording := recordingFrom(ctx)
+
+	start := p.beginAcquire()
+	var pooledCtx *PooledContext
+	var err error
+	switch {
+	case usePersistent("", cookiePath):
+		// 平台配置为持久化用户数据目录模式时，使用账号独立的浏览器进程（HAR/录屏只能在启动时配置，此模式仅记录 trace）
+		pooledCtx, err = p.getOrLaunchPersistent(ctx, accountID, "", cookiePath, options)
+	case (diagnostics != nil && diagnostics.HAR) || recording != nil:
+		pooledCtx, err = p.createSessionContext(ctx, accountID, cookiePath, options, diagnostics, recording)
+	default:
+		pooledCtx, err = p.getContextByReuseMode(ctx, accountID, cookiePath, options)
+	}
+	p.endAcquire(ctx, pooledCtx, start)
+	if err != nil {
+		return nil, err
+	}
+
+	pooledCtx.startDiagnostics(diagnostics)
+	return pooledCtx, nil
+}
+
+// createSessionContext 创建记录 HAR/录屏的独立上下文（这些选项只能在创建时指定），释放时关闭以写出文件
+func (p *Pool) createSessionContext(ctx context.Context, accountID uint, cookiePath string, options *ContextOptions, diagnostics *Diagnostics, recording *Recording) (*PooledContext, error) {
+	sessionOptions := DefaultContextOptions()
+	if options != nil {
+		copied := *options
+		sessionOptions = &copied
+	}
+
+	harPath := ""
+	if diagnostics != nil && diagnostics.HAR {
+		var err error
+		if harPath, err = diagnostics.nextPath(".har"); err != nil {
+			return nil, err
+		}
+		sessionOptions.RecordHarPath = harPath
+	}
+	if recording != nil {
+		dir, err := recording.videoDir()
+		if err != nil {
+			return nil, err
+		}
+		sessionOptions.RecordVideoDir = dir
+		sessionOptions.RecordVideoSize = recording.size()
+	}
+
+	pooledCtx, err := p.createNewContext(ctx, accountID, "", cookiePath, sessionOptions)
+	if err != nil {
+		return nil, err
+	}
+	if harPath != "" {
+		pooledCtx.har = diagnostics
+		pooledCtx.harPath = harPath
+	}
+	pooledCtx.closeOnRelease = true
+	return pooledCtx, nil
+}
+
+// getContextByReuseMode 按配置的复用策略获取上下文
+func (p *Pool) getContextByReuseMode(ctx context.Context, accountID uint, cookiePath string, options *ContextOptions) (*PooledContext, error) {
 	// 根据配置选择复用策略
 	cfg := LoadPoolConfig()
 	
@@ -146,14 +258,18 @@ func (p *Pool) getOrCreateContextWithIdleTimeout(ctx context.Context, accountID
 		options = DefaultContextOptions()
 	}
 
-	// 如果启用反检测，生成随机指纹
-	if options.EnableAntiDetect {
-		options = p.generateRandomFingerprint(options)
+	// 账号固定指纹
+	options = p.fingerprintOptions(accountID, platform, cookiePath, options)
+
+	// 账号绑定的代理
+	options, err := withAccountProxy(accountID, cookiePath, options)
+	if err != nil {
+		return nil, err
 	}
 
 	// 1. 尝试复用现有上下文（通过accountID + cookiePath双重匹配，带30秒空闲检查）
 	for _, browser := range p.browsers {
-		if pooledCtx := browser.getIdleContextByKey(accountID, cookiePath); pooledCtx != nil {
+		if pooledCtx := browser.getIdleContextByKey(accountID, cookiePath, proxyServer(options.Proxy)); pooledCtx != nil {
 			p.updateStats()
 			return pooledCtx, nil
 		}
@@ -173,14 +289,18 @@ func (p *Pool) getOrCreateContextImmediate(ctx context.Context, accountID uint,
 		options = DefaultContextOptions()
 	}
 
-	// 如果启用反检测，生成随机指纹
-	if options.EnableAntiDetect {
-		options = p.generateRandomFingerprint(options)
+	// 账号固定指纹
+	options = p.fingerprintOptions(accountID, platform, cookiePath, options)
+
+	// 账号绑定的代理
+	options, err := withAccountProxy(accountID, cookiePath, options)
+	if err != nil {
+		return nil, err
 	}
 
 	// 1. 尝试立即复用现有上下文（跳过30秒空闲检查）
 	for _, browser := range p.browsers {
-		if pooledCtx := browser.getContextImmediate(accountID, cookiePath); pooledCtx != nil {
+		if pooledCtx := browser.getContextImmediate(accountID, cookiePath, proxyServer(options.Proxy)); pooledCtx != nil {
 			utils.Info(fmt.Sprintf("[-] 立即复用上下文 - AccountID: %d", accountID))
 			p.updateStats()
 			return pooledCtx, nil
@@ -205,13 +325,17 @@ func (p *Pool) createNewContextLocked(ctx context.Context, accountID uint, platf
 		options = DefaultContextOptions()
 	}
 
-	// 如果启用反检测，生成随机指纹
-	if options.EnableAntiDetect {
-		options = p.generateRandomFingerprint(options)
+	// 账号固定指纹
+	options = p.fingerprintOptions(accountID, platform, cookiePath, options)
+
+	// 账号绑定的代理
+	options, err := withAccountProxy(accountID, cookiePath, options)
+	if err != nil {
+		return nil, err
 	}
 
-	// 创建新上下文
-	browser, err := p.getOrCreateBrowser()
+	// 创建新上下文（平台配置了远程浏览器时使用连接该端点的浏览器）
+	browser, err := p.getOrCreateBrowser(remoteFor(platform, cookiePath))
 	if err != nil {
 		return nil, err
 	}
@@ -228,6 +352,17 @@ func (p *Pool) createNewContextLocked(ctx context.Context, accountID uint, platf
 // GetContextWithAffinity 获取浏览器上下文（带平台亲和性）
 // 优先将同平台账号分配到同一浏览器进程，共享DNS缓存和静态资源
 func (p *Pool) GetContextWithAffinity(ctx context.Context, accountID uint, platform string, options *ContextOptions) (*PooledContext, error) {
+	start := p.beginAcquire()
+	pooledCtx, err := p.getContextWithAffinity(ctx, accountID, platform, options)
+	p.endAcquire(ctx, pooledCtx, start)
+	return pooledCtx, err
+}
+
+func (p *Pool) getContextWithAffinity(ctx context.Context, accountID uint, platform string, options *ContextOptions) (*PooledContext, error) {
+	if cookiePath := config.GetCookiePath(platform, int(accountID)); accountID > 0 && usePersistent(platform, cookiePath) {
+		return p.getOrLaunchPersistent(ctx, accountID, platform, cookiePath, options)
+	}
+
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
@@ -236,16 +371,20 @@ func (p *Pool) GetContextWithAffinity(ctx context.Context, accountID uint, platf
 		options = DefaultContextOptions()
 	}
 
-	// 如果启用反检测，生成随机指纹
-	if options.EnableAntiDetect {
-		options = p.generateRandomFingerprint(options)
-	}
-
 	cookiePath := config.GetCookiePath(platform, int(accountID))
 
+	// 账号固定指纹
+	options = p.fingerprintOptions(accountID, platform, cookiePath, options)
+
+	// 账号绑定的代理
+	options, err := withAccountProxy(accountID, cookiePath, options)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1. 尝试复用现有上下文
 	for _, browser := range p.browsers {
-		if pooledCtx := browser.getIdleContextByKey(accountID, cookiePath); pooledCtx != nil {
+		if pooledCtx := browser.getIdleContextByKey(accountID, cookiePath, proxyServer(options.Proxy)); pooledCtx != nil {
 			p.updateStats()
 			return pooledCtx, nil
 		}
@@ -268,6 +407,17 @@ func (p *Pool) GetContextWithAffinity(ctx context.Context, accountID uint, platf
 
 // GetContextImmediate 立即获取上下文（跳过30秒等待，用于同任务连续操作）
 func (p *Pool) GetContextImmediate(ctx context.Context, accountID uint, platform string, options *ContextOptions) (*PooledContext, error) {
+	start := p.beginAcquire()
+	pooledCtx, err := p.getContextImmediate(ctx, accountID, platform, options)
+	p.endAcquire(ctx, pooledCtx, start)
+	return pooledCtx, err
+}
+
+func (p *Pool) getContextImmediate(ctx context.Context, accountID uint, platform string, options *ContextOptions) (*PooledContext, error) {
+	if cookiePath := config.GetCookiePath(platform, int(accountID)); accountID > 0 && usePersistent(platform, cookiePath) {
+		return p.getOrLaunchPersistent(ctx, accountID, platform, cookiePath, options)
+	}
+
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
@@ -276,16 +426,20 @@ func (p *Pool) GetContextImmediate(ctx context.Context, accountID uint, platform
 		options = DefaultContextOptions()
 	}
 
-	// 如果启用反检测，生成随机指纹
-	if options.EnableAntiDetect {
-		options = p.generateRandomFingerprint(options)
-	}
-
 	cookiePath := config.GetCookiePath(platform, int(accountID))
 
+	// 账号固定指纹
+	options = p.fingerprintOptions(accountID, platform, cookiePath, options)
+
+	// 账号绑定的代理
+	options, err := withAccountProxy(accountID, cookiePath, options)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1. 尝试立即复用现有上下文（跳过30秒等待）
 	for _, browser := range p.browsers {
-		if pooledCtx := browser.getContextImmediate(accountID, cookiePath); pooledCtx != nil {
+		if pooledCtx := browser.getContextImmediate(accountID, cookiePath, proxyServer(options.Proxy)); pooledCtx != nil {
 			utils.Info(fmt.Sprintf("[-] 立即复用上下文 - AccountID: %d, Platform: %s", accountID, platform))
 			p.updateStats()
 			return pooledCtx, nil
@@ -351,10 +505,7 @@ func (p *Pool) RecoverContext(ctx context.Context, accountID uint, platform stri
 func (p *Pool) CleanupAndRecover() {
 	utils.Info("[-] 执行清理和恢复任务...")
 
-	p.mutex.Lock()
-	browsers := make([]*PooledBrowser, len(p.browsers))
-	copy(browsers, p.browsers)
-	p.mutex.Unlock()
+	browsers := p.allBrowsers()
 
 	recoveredCount := 0
 	cleanedCount := 0
@@ -384,49 +535,24 @@ func (p *Pool) CleanupAndRecover() {
 	utils.Info(fmt.Sprintf("[-] 清理和恢复完成 - 恢复: %d, 清理: %d", recoveredCount, cleanedCount))
 }
 
+// fingerprintOptions 生成上下文指纹选项：能关联到账号时总是使用账号固定指纹（不论是否启用反检测），
+// 否则启用反检测时使用平台默认地区的随机指纹，未启用时保持原选项
+func (p *Pool) fingerprintOptions(accountID uint, platform string, cookiePath string, baseOptions *ContextOptions) *ContextOptions {
+	if baseOptions.Fingerprint != nil {
+		return baseOptions
+	}
+	if fp := accountFingerprint(accountID, cookiePath); fp != nil {
+		return fp.Apply(baseOptions)
+	}
+	if !baseOptions.EnableAntiDetect {
+		return baseOptions
+	}
+	return p.generateRandomFingerprint(platform, baseOptions)
+}
+
 // generateRandomFingerprint 生成随机浏览器指纹
-func (p *Pool) generateRandomFingerprint(baseOptions *ContextOptions) *ContextOptions {
-	chromeVersions := []string{"120", "121", "122", "123", "124", "125"}
-	version := chromeVersions[rand.Intn(len(chromeVersions))]
-
-	// 随机视口（在合理范围内变化）
-	width := 1920 + rand.Intn(100) - 50
-	height := 1080 + rand.Intn(100) - 50
-
-	// 随机地理位置（北京附近）
-	lat := 39.9042 + (rand.Float64()-0.5)*0.1
-	lng := 116.4074 + (rand.Float64()-0.5)*0.1
-
-	options := &ContextOptions{
-		UserAgent: fmt.Sprintf(
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s.0.0.0 Safari/537.36",
-			version,
-		),
-		Viewport: &playwright.Size{
-			Width:  width,
-			Height: height,
-		},
-		Locale:     "zh-CN",
-		TimezoneId: "Asia/Shanghai",
-		Geolocation: &playwright.Geolocation{
-			Latitude:  lat,
-			Longitude: lng,
-		},
-		ExtraHeaders: map[string]string{
-			"Accept-Language":           "zh-CN,zh;q=0.9,en;q=0.8",
-			"Sec-Ch-Ua":                 fmt.Sprintf(`"Not_A Brand";v="8", "Chromium";v="%s", "Google Chrome";v="%s"`, version, version),
-			"Sec-Ch-Ua-Mobile":          "?0",
-			"Sec-Ch-Ua-Platform":        `"Windows"`,
-			"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
-			"Accept-Encoding":           "gzip, deflate, br",
-			"Upgrade-Insecure-Requests": "1",
-		},
-		EnableAntiDetect:  baseOptions.EnableAntiDetect,
-		EnableRandomDelay: baseOptions.EnableRandomDelay,
-		HumanLikeBehavior: baseOptions.HumanLikeBehavior,
-	}
-
-	return options
+func (p *Pool) generateRandomFingerprint(platform string, baseOptions *ContextOptions) *ContextOptions {
+	return GenerateFingerprint(config.PlatformBrowserRegion(platform)).Apply(baseOptions)
 }
 
 // GetStats 获取浏览器池统计信息
@@ -460,40 +586,116 @@ func (p *Pool) updateStats() {
 		browser.mutex.Unlock()
 	}
 
-	p.stats.WaitQueueLength = len(p.waitQueue)
+	for _, browser := range p.persistent {
+		browser.mutex.Lock()
+		p.stats.PersistentCount++
+		p.stats.ContextCount += len(browser.contexts)
+		p.stats.InUseContextCount += browser.inUse
+		for _, ctx := range browser.contexts {
+			if time.Since(ctx.lastUsed) > 30*time.Second {
+				p.stats.IdleContextCount++
+			}
+		}
+		browser.mutex.Unlock()
+	}
+
+	p.stats.WaitQueueLength = int(p.waiting.Load())
+	p.stats.LastWaitMs = time.Duration(p.lastWait.Load()).Milliseconds()
+	p.stats.MaxWaitMs = time.Duration(p.maxWait.Load()).Milliseconds()
 }
 
-// Close 关闭浏览器池
+// Close 关闭浏览器池：保存各上下文的 Cookie 后关闭上下文和浏览器，并停止 Playwright 驱动（应用退出时调用）
 func (p *Pool) Close() error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
 	for _, browser := range p.browsers {
-		for _, ctx := range browser.contexts {
-			ctx.Close()
-		}
+		closePooledContexts(browser)
 		if err := browser.browser.Close(); err != nil {
 			utils.Warn(fmt.Sprintf("[-] 关闭浏览器失败: %v", err))
 		}
 	}
 
 	p.browsers = make([]*PooledBrowser, 0)
+
+	// 关闭持久化浏览器（关闭上下文即退出对应的浏览器进程）
+	for _, browser := range p.persistent {
+		closePooledContexts(browser)
+		browser.closed.Store(true)
+	}
+	p.persistent = nil
+
+	p.stopDriver()
 	p.updateStats()
 	return nil
 }
 
-// getOrCreateBrowser 获取或创建浏览器实例
-func (p *Pool) getOrCreateBrowser() (*PooledBrowser, error) {
-	// 查找有可用容量的浏览器
+// closePooledContexts 保存 Cookie 后关闭浏览器的所有上下文，持有方之后的 Release 不再处理
+func closePooledContexts(browser *PooledBrowser) {
+	browser.mutex.Lock()
+	contexts := append([]*PooledContext(nil), browser.contexts...)
+	for _, ctx := range contexts {
+		ctx.forceClosed = true
+		ctx.inUse = false
+	}
+	browser.contexts = nil
+	browser.inUse = 0
+	browser.mutex.Unlock()
+
+	for _, ctx := range contexts {
+		ctx.stopDiagnostics()
+		if ctx.cookiePath != "" {
+			if err := ctx.SaveCookiesTo(ctx.cookiePath); err != nil {
+				utils.Warn(fmt.Sprintf("[-] [%s] 保存Cookie失败: %v", ctx.platform, err))
+			}
+		}
+		ctx.Close()
+		ctx.finishHar()
+	}
+}
+
+// driver 池共用的 Playwright 驱动，首次启动浏览器时启动，关闭池时停止
+func (p *Pool) driver() (*playwright.Playwright, error) {
+	p.driverMutex.Lock()
+	defer p.driverMutex.Unlock()
+	if p.pw != nil {
+		return p.pw, nil
+	}
+	pw, err := playwright.Run()
+	if err != nil {
+		return nil, fmt.Errorf("start playwright failed: %w", err)
+	}
+	p.pw = pw
+	return pw, nil
+}
+
+// stopDriver 停止 Playwright 驱动
+func (p *Pool) stopDriver() {
+	p.driverMutex.Lock()
+	defer p.driverMutex.Unlock()
+	if p.pw == nil {
+		return
+	}
+	if err := p.pw.Stop(); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 停止 Playwright 驱动失败: %v", err))
+	}
+	p.pw = nil
+}
+
+// getOrCreateBrowser 获取或创建浏览器实例，remote 为空时使用本地浏览器
+func (p *Pool) getOrCreateBrowser(remote *RemoteBrowser) (*PooledBrowser, error) {
+	key := remoteKey(remote)
+
+	// 查找同一端点有可用容量的浏览器
 	for _, b := range p.browsers {
-		if b.canCreateContext(p.maxContexts) {
+		if remoteKey(b.remote) == key && b.canCreateContext(p.maxContexts) {
 			return b, nil
 		}
 	}
 
 	// 创建新浏览器
 	if len(p.browsers) < p.maxBrowsers {
-		browser, err := p.launchBrowser()
+		browser, err := p.launchBrowser(remote)
 		if err != nil {
 			return nil, err
 		}
@@ -501,6 +703,7 @@ func (p *Pool) getOrCreateBrowser() (*PooledBrowser, error) {
 		pooled := &PooledBrowser{
 			browser:  browser,
 			contexts: make([]*PooledContext, 0),
+			remote:   remote,
 		}
 		p.browsers = append(p.browsers, pooled)
 		return pooled, nil
@@ -509,28 +712,28 @@ func (p *Pool) getOrCreateBrowser() (*PooledBrowser, error) {
 	return nil, fmt.Errorf("max browsers reached")
 }
 
-// getOrCreateBrowserWithAffinity 获取或创建浏览器实例（带平台亲和性）
+// getOrCreateBrowserWithAffinity 获取或创建浏览器实例（带平台亲和性，调用方持有池锁）
 func (p *Pool) getOrCreateBrowserWithAffinity(platform string) (*PooledBrowser, error) {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
+	remote := LoadPoolConfig().RemoteFor(platform)
+	key := remoteKey(remote)
 
 	// 1. 优先查找已有该平台上下文的浏览器（亲和性）
 	for _, b := range p.browsers {
-		if b.hasPlatformContext(platform) && b.canCreateContext(p.maxContexts) {
+		if remoteKey(b.remote) == key && b.hasPlatformContext(platform) && b.canCreateContext(p.maxContexts) {
 			return b, nil
 		}
 	}
 
 	// 2. 查找有空闲容量的浏览器
 	for _, b := range p.browsers {
-		if b.canCreateContext(p.maxContexts) {
+		if remoteKey(b.remote) == key && b.canCreateContext(p.maxContexts) {
 			return b, nil
 		}
 	}
 
 	// 3. 创建新浏览器
 	if len(p.browsers) < p.maxBrowsers {
-		browser, err := p.launchBrowser()
+		browser, err := p.launchBrowser(remote)
 		if err != nil {
 			return nil, err
 		}
@@ -539,6 +742,7 @@ func (p *Pool) getOrCreateBrowserWithAffinity(platform string) (*PooledBrowser,
 			browser:         browser,
 			contexts:        make([]*PooledContext, 0),
 			platformContext: make(map[string]int),
+			remote:          remote,
 		}
 		p.browsers = append(p.browsers, pooled)
 		return pooled, nil
@@ -547,11 +751,36 @@ func (p *Pool) getOrCreateBrowserWithAffinity(platform string) (*PooledBrowser,
 	return nil, fmt.Errorf("max browsers reached")
 }
 
-// launchBrowser 启动浏览器
-func (p *Pool) launchBrowser() (playwright.Browser, error) {
-	pw, err := playwright.Run()
+// browserLaunchArgs 浏览器启动参数（普通模式和持久化模式共用）
+var browserLaunchArgs = []string{
+	"--disable-blink-features=AutomationControlled",
+	"--disable-web-security",
+	"--no-sandbox",
+	"--disable-setuid-sandbox",
+	"--disable-dev-shm-usage",
+	"--window-size=1920,1080",
+	"--window-position=0,0",
+	"--start-maximized",
+	"--disable-infobars",
+	"--disable-extensions",
+	"--disable-default-apps",
+	"--disable-background-networking",
+	"--disable-sync",
+	"--disable-translate",
+	"--disable-popup-blocking",
+	"--disable-features=IsolateOrigins,site-per-process,SameSiteByDefaultCookies,CookiesWithoutSameSiteMustBeSecure",
+	"--disable-site-isolation-trials",
+}
+
+// launchBrowser 启动浏览器，配置了远程浏览器时改为连接远程端点
+func (p *Pool) launchBrowser(remote *RemoteBrowser) (playwright.Browser, error) {
+	if remote != nil {
+		return p.connectRemote(remote)
+	}
+
+	pw, err := p.driver()
 	if err != nil {
-		return nil, fmt.Errorf("start playwright failed: %w", err)
+		return nil, err
 	}
 
 	// 查找本地 Chrome
@@ -559,25 +788,7 @@ func (p *Pool) launchBrowser() (playwright.Browser, error) {
 
 	launchOptions := playwright.BrowserTypeLaunchOptions{
 		Headless: playwright.Bool(config.Config.Headless),
-		Args: []string{
-			"--disable-blink-features=AutomationControlled",
-			"--disable-web-security",
-			"--no-sandbox",
-			"--disable-setuid-sandbox",
-			"--disable-dev-shm-usage",
-			"--window-size=1920,1080",
-			"--window-position=0,0",
-			"--start-maximized",
-			"--disable-infobars",
-			"--disable-extensions",
-			"--disable-default-apps",
-			"--disable-background-networking",
-			"--disable-sync",
-			"--disable-translate",
-			"--disable-popup-blocking",
-			"--disable-features=IsolateOrigins,site-per-process,SameSiteByDefaultCookies,CookiesWithoutSameSiteMustBeSecure",
-			"--disable-site-isolation-trials",
-		},
+		Args:     launchArgs(),
 	}
 
 	if chromePath != "" {
@@ -741,13 +952,13 @@ func (b *PooledBrowser) getIdleContext(cookiePath string) *PooledContext {
 	return nil
 }
 
-// getIdleContextByKey 通过accountID和cookiePath双重匹配获取空闲上下文
-func (b *PooledBrowser) getIdleContextByKey(accountID uint, cookiePath string) *PooledContext {
+// getIdleContextByKey 通过accountID和cookiePath双重匹配获取空闲上下文（代理变更后不复用旧上下文）
+func (b *PooledBrowser) getIdleContextByKey(accountID uint, cookiePath string, proxy string) *PooledContext {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
 	for _, ctx := range b.contexts {
-		if ctx.accountID == accountID && ctx.cookiePath == cookiePath && time.Since(ctx.lastUsed) > 30*time.Second {
+		if ctx.accountID == accountID && ctx.cookiePath == cookiePath && ctx.proxyServer == proxy && time.Since(ctx.lastUsed) > 30*time.Second {
 			ctx.lastUsed = time.Now()
 			b.inUse++
 			return ctx
@@ -757,12 +968,12 @@ func (b *PooledBrowser) getIdleContextByKey(accountID uint, cookiePath string) *
 }
 
 // getContextImmediate 立即获取上下文（跳过30秒等待，用于同任务连续操作）
-func (b *PooledBrowser) getContextImmediate(accountID uint, cookiePath string) *PooledContext {
+func (b *PooledBrowser) getContextImmediate(accountID uint, cookiePath string, proxy string) *PooledContext {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
 	for _, ctx := range b.contexts {
-		if ctx.accountID == accountID && ctx.cookiePath == cookiePath {
+		if ctx.accountID == accountID && ctx.cookiePath == cookiePath && ctx.proxyServer == proxy {
 			// 不检查时间间隔，立即复用
 			ctx.lastUsed = time.Now()
 			b.inUse++
@@ -801,10 +1012,33 @@ func (b *PooledBrowser) createContextWithPlatform(accountID uint, platform strin
 	if options.Geolocation != nil {
 		contextOptions.Geolocation = options.Geolocation
 	}
+	if options.Viewport != nil {
+		contextOptions.Viewport = options.Viewport
+	}
+	if options.Proxy != nil {
+		contextOptions.Proxy = options.Proxy
+	}
+	if options.RecordHarPath != "" {
+		// 上传请求体很大，HAR 只记录请求和响应元数据
+		contextOptions.RecordHarPath = playwright.String(options.RecordHarPath)
+		contextOptions.RecordHarContent = playwright.HarContentPolicyOmit
+	}
+	if options.RecordVideoDir != "" {
+		contextOptions.RecordVideo = &playwright.RecordVideo{
+			Dir:  options.RecordVideoDir,
+			Size: options.RecordVideoSize,
+		}
+	}
 
-	// 加载 Cookie
-	if _, err := os.Stat(cookiePath); err == nil {
-		contextOptions.StorageStatePath = playwright.String(cookiePath)
+	// 加载 Cookie（凭据库加密存储，旧版本明文文件同样可读）
+	if data, err := vault.ReadFile(cookiePath); err == nil {
+		var state playwright.OptionalStorageState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("parse storage state failed: %w", err)
+		}
+		contextOptions.StorageState = &state
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("load storage state failed: %w", err)
 	}
 
 	context, err := b.browser.NewContext(contextOptions)
@@ -812,6 +1046,14 @@ func (b *PooledBrowser) createContextWithPlatform(accountID uint, platform strin
 		return nil, fmt.Errorf("create context failed: %w", err)
 	}
 
+	// 注入账号固定的 Canvas/WebGL 指纹
+	if options.Fingerprint != nil {
+		if err := context.AddInitScript(playwright.Script{Content: playwright.String(options.Fingerprint.NoiseScript())}); err != nil {
+			context.Close()
+			return nil, fmt.Errorf("inject fingerprint script failed: %w", err)
+		}
+	}
+
 	// [已禁用] 注入反检测脚本
 	// if err := platformutils.InjectStealthScript(context); err != nil {
 	// 	return nil, fmt.Errorf("inject stealth script failed: %w", err)
@@ -825,6 +1067,11 @@ func (b *PooledBrowser) createContextWithPlatform(accountID uint, platform strin
 		createdAt:  time.Now(),
 		lastUsed:   time.Now(),
 		parent:     b,
+
+		proxyServer: proxyServer(options.Proxy),
+	}
+	if ctx.proxyServer != "" {
+		utils.Info(fmt.Sprintf("[-] 上下文使用代理 - AccountID: %d, Proxy: %s", accountID, ctx.proxyServer))
 	}
 
 	b.contexts = append(b.contexts, ctx)
@@ -843,12 +1090,22 @@ func (c *PooledContext) Release() error {
 	c.parent.mutex.Lock()
 	defer c.parent.mutex.Unlock()
 
+	// 已在池状态页被强制关闭（或所在浏览器已重启），占用计数已处理
+	if c.forceClosed {
+		return fmt.Errorf("browser context was force closed")
+	}
+	c.inUse = false
+	c.taskID = 0
+
 	// 获取平台标识，如果为空则使用默认值
 	platform := c.platform
 	if platform == "" {
 		platform = "browser"
 	}
 
+	// 结束诊断会话的 trace 记录
+	c.stopDiagnostics()
+
 	// 检查页面是否已关闭（用户手动关闭浏览器）
 	if c.IsPageClosed() {
 		utils.Info(fmt.Sprintf("[-] [%s] 浏览器被用户关闭，执行清理...", platform))
@@ -871,6 +1128,7 @@ func (c *PooledContext) Release() error {
 		// 从父浏览器的上下文中移除
 		c.removeFromParent()
 		c.parent.inUse--
+		c.finishHar()
 
 		utils.Info(fmt.Sprintf("[-] [%s] 浏览器上下文已清理完成", platform))
 		return fmt.Errorf("browser was closed by user")
@@ -900,20 +1158,29 @@ func (c *PooledContext) Release() error {
 	c.parent.inUse--
 	c.lastUsed = time.Now()
 
+	// 记录 HAR/录屏的独立上下文不复用，关闭后写出文件
+	if c.closeOnRelease {
+		if err := c.context.Close(); err != nil {
+			utils.Warn(fmt.Sprintf("[-] [%s] 关闭上下文失败: %v", platform, err))
+		}
+		c.removeFromParent()
+		c.finishHar()
+	}
+
 	utils.Info(fmt.Sprintf("[-] [%s] 浏览器上下文已释放", platform))
 
 	return nil
 }
 
-// removeFromParent 从父浏览器中移除上下文
+// removeFromParent 从父浏览器中移除上下文（调用方持有父浏览器锁）
 func (c *PooledContext) removeFromParent() {
 	for i, ctx := range c.parent.contexts {
 		if ctx == c {
 			// 从切片中移除
 			c.parent.contexts = append(c.parent.contexts[:i], c.parent.contexts[i+1:]...)
-			// 减少平台计数
-			if c.platform != "" {
-				c.parent.decrementPlatformCount(c.platform)
+			// 减少平台计数（已持有锁，不能调用 decrementPlatformCount）
+			if c.platform != "" && c.parent.platformContext[c.platform] > 0 {
+				c.parent.platformContext[c.platform]--
 			}
 			break
 		}
@@ -945,15 +1212,8 @@ func (c *PooledContext) SaveCookiesTo(cookiePath string) error {
 		return err
 	}
 
-	// 确保目录存在
-	dir := filepath.Dir(cookiePath)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("create cookie directory failed: %w", err)
-		}
-	}
-
-	return os.WriteFile(cookiePath, data, 0644)
+	// 加密写入凭据库（权限 0600）
+	return vault.WriteFile(cookiePath, data)
 }
 
 // GetAccountID 获取上下文绑定的账号ID
@@ -1298,78 +1558,20 @@ func (c *PooledContext) GetCookieValues(domain string, names []string) (map[stri
 // 	return false, "", nil
 // }
 
-// ==================== 人类行为模拟方法（已禁用，降为可选项） ====================
-
-// HumanLikeDelay 模拟人类操作的随机延迟
-// [已禁用] 中等耗时（可配置），降为可选项
-// func (c *PooledContext) HumanLikeDelay(baseDelay time.Duration) {
-// 	variance := float64(baseDelay) * 0.3
-// 	delay := baseDelay + time.Duration(rand.Float64()*variance*2-variance)
-// 	time.Sleep(delay)
-// }
-
-// HumanLikeTyping 模拟人类输入（带随机延迟）
-// [已禁用] 高耗时（每字符50-150ms），降为可选项
-// func (c *PooledContext) HumanLikeTyping(text string) error {
-// 	if c.page == nil {
-// 		return fmt.Errorf("page not created")
-// 	}
-//
-// 	for _, char := range text {
-// 		if err := c.page.Keyboard().Type(string(char)); err != nil {
-// 			return err
-// 		}
-// 		time.Sleep(time.Duration(50+rand.Intn(100)) * time.Millisecond)
-// 	}
-// 	return nil
-// }
-
-// SimulateHumanBehavior 模拟人类浏览行为
-// [已禁用] 高耗时（1.5-3秒），降为可选项
-// func (c *PooledContext) SimulateHumanBehavior() error {
-// 	if c.page == nil {
-// 		return fmt.Errorf("page not created")
-// 	}
-//
-// 	// 随机滚动
-// 	scrollCount := 2 + rand.Intn(3)
-// 	for i := 0; i < scrollCount; i++ {
-// 		scrollY := rand.Intn(300) + 100
-// 		_, err := c.page.Evaluate(fmt.Sprintf("window.scrollBy(0, %d)", scrollY))
-// 		if err != nil {
-// 			return err
-// 		}
-// 		time.Sleep(time.Duration(500+rand.Intn(500)) * time.Millisecond)
-// 	}
-//
-// 	// 随机鼠标移动
-// 	err := c.page.Mouse().Move(float64(rand.Intn(500)+100), float64(rand.Intn(300)+100))
-// 	if err != nil {
-// 		return err
-// 	}
-//
-// 	return nil
-// }
-
 // SafeGoto 安全导航（已简化，移除高耗时检测）
 func (c *PooledContext) SafeGoto(url string, options ...playwright.PageGotoOptions) error {
 	if c.page == nil {
 		return fmt.Errorf("page not created")
 	}
 
-	// [已禁用] 模拟人类行为前等待
-	// c.HumanLikeDelay(500 * time.Millisecond)
+	// 导航前按平台的人类交互预设停顿
+	HumanFor(c.platform).Think()
 
 	_, err := c.page.Goto(url, options...)
 	if err != nil {
 		return err
 	}
 
-	// [已禁用] 页面加载后模拟人类行为
-	// if err := c.SimulateHumanBehavior(); err != nil {
-	// 	utils.Warn(fmt.Sprintf("[-] 模拟人类行为失败: %v", err))
-	// }
-
 	// [已禁用] 检测验证码
 	// if detected, captchaType, _ := c.DetectCaptcha(); detected {
 	// 	return fmt.Errorf("检测到%s，需要人工处理", captchaType)
@@ -1383,48 +1585,20 @@ func (c *PooledContext) SafeGoto(url string, options ...playwright.PageGotoOptio
 	return nil
 }
 
-// SafeClick 安全点击（已简化，移除延迟）
+// SafeClick 安全点击（按平台的人类交互预设模拟鼠标移动和停顿）
 func (c *PooledContext) SafeClick(selector string) error {
 	if c.page == nil {
 		return fmt.Errorf("page not created")
 	}
-
-	// [已禁用] 点击前延迟
-	// c.HumanLikeDelay(300 * time.Millisecond)
-
-	if err := c.page.Locator(selector).Click(); err != nil {
-		return err
-	}
-
-	// [已禁用] 点击后延迟
-	// c.HumanLikeDelay(200 * time.Millisecond)
-	return nil
+	return HumanFor(c.platform).Click(c.page.Locator(selector))
 }
 
-// SafeFill 安全填写（已简化，移除人类输入模拟）
+// SafeFill 安全填写（按平台的人类交互预设逐字输入或直接填充）
 func (c *PooledContext) SafeFill(selector, text string) error {
 	if c.page == nil {
 		return fmt.Errorf("page not created")
 	}
-
-	// 先点击输入框
-	if err := c.SafeClick(selector); err != nil {
-		return err
-	}
-
-	// 清空内容
-	if err := c.page.Keyboard().Press("Control+KeyA"); err != nil {
-		return err
-	}
-	if err := c.page.Keyboard().Press("Delete"); err != nil {
-		return err
-	}
-
-	// [已禁用] 模拟人类输入，改为直接填充
-	// return c.HumanLikeTyping(text)
-
-	// 直接填充文本
-	return c.page.Locator(selector).Fill(text)
+	return HumanFor(c.platform).Fill(c.page.Locator(selector), text)
 }
 
 // findLocalChrome 查找本地 Chrome
@@ -1452,13 +1626,23 @@ func init() {
 }
 
 var (
-	defaultPool     *Pool
-	defaultPoolOnce sync.Once
+	defaultPool      *Pool
+	defaultPoolMutex sync.Mutex
 )
 
+// GetDefaultPool 获取全局共享的浏览器池（应用启动时通过 SetDefaultPool 注入，未注入时按配置创建）
 func GetDefaultPool() *Pool {
-	defaultPoolOnce.Do(func() {
+	defaultPoolMutex.Lock()
+	defer defaultPoolMutex.Unlock()
+	if defaultPool == nil {
 		defaultPool = NewPoolFromConfig()
-	})
+	}
 	return defaultPool
 }
+
+// SetDefaultPool 注入应用持有的浏览器池，所有平台上传器共用该池，池的数量限制全局生效
+func SetDefaultPool(pool *Pool) {
+	defaultPoolMutex.Lock()
+	defer defaultPoolMutex.Unlock()
+	defaultPool = pool
+}
@@ -0,0 +1,74 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// ProxyProvider 账号代理配置来源
+// accountID 为 0 时通过 cookiePath 定位账号；账号未配置代理时返回 nil
+type ProxyProvider interface {
+	AccountProxy(accountID uint, cookiePath string) (*playwright.Proxy, error)
+}
+
+var (
+	proxyProvider ProxyProvider
+	proxyMu       sync.RWMutex
+)
+
+// SetProxyProvider 设置账号代理来源，未设置时所有上下文直连
+func SetProxyProvider(provider ProxyProvider) {
+	proxyMu.Lock()
+	defer proxyMu.Unlock()
+	proxyProvider = provider
+}
+
+// accountProxy 获取账号绑定的代理，无法关联账号或未配置时返回 nil
+func accountProxy(accountID uint, cookiePath string) (*playwright.Proxy, error) {
+	if accountID == 0 && cookiePath == "" {
+		return nil, nil
+	}
+
+	proxyMu.RLock()
+	provider := proxyProvider
+	proxyMu.RUnlock()
+	if provider == nil {
+		return nil, nil
+	}
+
+	proxy, err := provider.AccountProxy(accountID, cookiePath)
+	if errors.Is(err, ErrNoAccount) {
+		return nil, nil
+	}
+	return proxy, err
+}
+
+// proxyServer 代理地址，直连时为空
+func proxyServer(proxy *playwright.Proxy) string {
+	if proxy == nil {
+		return ""
+	}
+	return proxy.Server
+}
+
+// withAccountProxy 为上下文选项填充账号代理（调用方显式指定的代理优先）
+// 账号配置了代理但读取失败时返回错误，避免以本机 IP 登录该账号
+func withAccountProxy(accountID uint, cookiePath string, options *ContextOptions) (*ContextOptions, error) {
+	if options.Proxy != nil {
+		return options, nil
+	}
+	proxy, err := accountProxy(accountID, cookiePath)
+	if err != nil {
+		return nil, fmt.Errorf("load account proxy failed: %w", err)
+	}
+	if proxy == nil {
+		return options, nil
+	}
+
+	withProxy := *options
+	withProxy.Proxy = proxy
+	return &withProxy, nil
+}
@@ -0,0 +1,111 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Recording 一次上传会话的录屏（Playwright RecordVideo）
+// 通过 WithRecording 放入 context，会话使用独立上下文录制，上下文关闭后由 Finish 保存到录屏目录
+type Recording struct {
+	Width  int // 录屏尺寸，为 0 时按视口缩放到 800x800 以内
+	Height int
+
+	dir    string // 录屏保存目录
+	name   string // 文件名前缀
+	tmpDir string
+}
+
+type recordingKey struct{}
+
+// NewRecording 创建录屏会话，dir 为录屏保存目录，name 为文件名前缀
+func NewRecording(dir, name string, width, height int) *Recording {
+	return &Recording{
+		Width:  width,
+		Height: height,
+		dir:    dir,
+		name:   name,
+		tmpDir: filepath.Join(dir, ".tmp", name),
+	}
+}
+
+// WithRecording 将录屏会话放入 context，浏览器池据此为上下文开启录屏
+func WithRecording(ctx context.Context, r *Recording) context.Context {
+	return context.WithValue(ctx, recordingKey{}, r)
+}
+
+func recordingFrom(ctx context.Context) *Recording {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(recordingKey{}).(*Recording)
+	return r
+}
+
+// videoDir 上下文写出录像的临时目录
+func (r *Recording) videoDir() (string, error) {
+	if err := os.MkdirAll(r.tmpDir, 0755); err != nil {
+		return "", fmt.Errorf("create recording directory failed: %w", err)
+	}
+	return r.tmpDir, nil
+}
+
+func (r *Recording) size() *playwright.Size {
+	if r.Width <= 0 || r.Height <= 0 {
+		return nil
+	}
+	return &playwright.Size{Width: r.Width, Height: r.Height}
+}
+
+// Finish 会话结束（上下文已关闭）后将录像移动到录屏目录，返回主录像（体积最大的页面）路径，没有录像时返回空
+// 会话中打开了多个页面时，其余页面的录像以 _2、_3 后缀保存
+func (r *Recording) Finish() (string, error) {
+	defer os.RemoveAll(r.tmpDir)
+
+	entries, err := os.ReadDir(r.tmpDir)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read recording directory failed: %w", err)
+	}
+
+	type video struct {
+		path string
+		size int64
+	}
+	var videos []video
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".webm") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() == 0 {
+			continue
+		}
+		videos = append(videos, video{filepath.Join(r.tmpDir, entry.Name()), info.Size()})
+	}
+	sort.SliceStable(videos, func(i, j int) bool { return videos[i].size > videos[j].size })
+
+	main := ""
+	for i, v := range videos {
+		name := r.name + ".webm"
+		if i > 0 {
+			name = fmt.Sprintf("%s_%d.webm", r.name, i+1)
+		}
+		dst := filepath.Join(r.dir, name)
+		if err := os.Rename(v.path, dst); err != nil {
+			return main, fmt.Errorf("save recording failed: %w", err)
+		}
+		if i == 0 {
+			main = dst
+		}
+	}
+	return main, nil
+}
@@ -0,0 +1,45 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordingFinish(t *testing.T) {
+	dir := t.TempDir()
+	r := NewRecording(dir, "task_1_douyin", 1280, 720)
+	if s := r.size(); s == nil || s.Width != 1280 || s.Height != 720 {
+		t.Fatalf("size = %v", s)
+	}
+
+	tmp, err := r.videoDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 一个会话可能打开多个页面，体积最大的视为主录像
+	os.WriteFile(filepath.Join(tmp, "a.webm"), []byte("small"), 0644)
+	os.WriteFile(filepath.Join(tmp, "b.webm"), []byte("the main upload page"), 0644)
+
+	path, err := r.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "task_1_douyin.webm") {
+		t.Fatalf("path = %q", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "the main upload page" {
+		t.Errorf("main recording = %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "task_1_douyin_2.webm")); err != nil {
+		t.Errorf("secondary recording missing: %v", err)
+	}
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Error("temporary directory not removed")
+	}
+
+	// 未录到任何页面
+	if path, err := NewRecording(dir, "task_2", 0, 0).Finish(); path != "" || err != nil {
+		t.Errorf("empty recording: %q, %v", path, err)
+	}
+}
@@ -0,0 +1,101 @@
+package browser
+
+import (
+	"fmt"
+	"net/url"
+
+	"Fuploader/internal/utils"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// defaultRemoteTimeout 连接远程浏览器的默认超时（秒）
+const defaultRemoteTimeout = 30
+
+// Validate 检查远程浏览器配置
+func (r *RemoteBrowser) Validate() error {
+	u, err := url.Parse(r.Endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid remote browser endpoint: %q", r.Endpoint)
+	}
+	switch r.Protocol {
+	case RemoteProtocolCDP:
+		switch u.Scheme {
+		case "http", "https", "ws", "wss":
+		default:
+			return fmt.Errorf("cdp endpoint must be http(s) or ws(s): %q", r.Endpoint)
+		}
+	case RemoteProtocolPlaywright:
+		if u.Scheme != "ws" && u.Scheme != "wss" {
+			return fmt.Errorf("playwright server endpoint must be ws(s): %q", r.Endpoint)
+		}
+	default:
+		return fmt.Errorf("unsupported remote browser protocol: %q", r.Protocol)
+	}
+	if r.Timeout < 0 {
+		return fmt.Errorf("remote browser timeout must not be negative")
+	}
+	return nil
+}
+
+// remoteKey 浏览器所属端点的标识，本地浏览器为空
+func remoteKey(r *RemoteBrowser) string {
+	if r == nil {
+		return ""
+	}
+	return string(r.Protocol) + " " + r.Endpoint
+}
+
+// remoteFor 账号上下文使用的远程浏览器，platform 为空时根据 Cookie 路径推断平台
+func remoteFor(platform, cookiePath string) *RemoteBrowser {
+	if platform == "" {
+		platform = platformFromCookiePath(cookiePath)
+	}
+	return LoadPoolConfig().RemoteFor(platform)
+}
+
+// connectRemote 连接远程浏览器（CDP 或 Playwright 浏览器服务）
+// 关闭连接得到的浏览器只断开连接并清理本池创建的上下文，不会结束远程的浏览器进程
+func (p *Pool) connectRemote(remote *RemoteBrowser) (playwright.Browser, error) {
+	if err := remote.Validate(); err != nil {
+		return nil, err
+	}
+	pw, err := p.driver()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := remote.Timeout
+	if timeout == 0 {
+		timeout = defaultRemoteTimeout
+	}
+	timeoutMs := playwright.Float(float64(timeout * 1000))
+
+	var browser playwright.Browser
+	if remote.Protocol == RemoteProtocolPlaywright {
+		browser, err = pw.Chromium.Connect(remote.Endpoint, playwright.BrowserTypeConnectOptions{
+			Headers: remote.Headers,
+			Timeout: timeoutMs,
+		})
+	} else {
+		browser, err = pw.Chromium.ConnectOverCDP(remote.Endpoint, playwright.BrowserTypeConnectOverCDPOptions{
+			Headers: remote.Headers,
+			Timeout: timeoutMs,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connect remote browser %s failed: %w", endpointHost(remote.Endpoint), err)
+	}
+
+	utils.Info(fmt.Sprintf("[-] 浏览器池已连接远程浏览器 (%s): %s", remote.Protocol, endpointHost(remote.Endpoint)))
+	return browser, nil
+}
+
+// endpointHost 端点地址去掉路径和查询参数（其中可能带有访问令牌），用于日志和状态展示
+func endpointHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,72 @@
+package browser
+
+import "testing"
+
+func TestRemoteBrowserValidate(t *testing.T) {
+	valid := []RemoteBrowser{
+		{Protocol: RemoteProtocolCDP, Endpoint: "http://10.0.0.5:9222"},
+		{Protocol: RemoteProtocolCDP, Endpoint: "ws://10.0.0.5:9222/devtools/browser/abc"},
+		{Protocol: RemoteProtocolPlaywright, Endpoint: "wss://fleet.example.com/playwright?token=x", Timeout: 60},
+	}
+	for _, r := range valid {
+		if err := r.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error %v", r, err)
+		}
+	}
+
+	invalid := []RemoteBrowser{
+		{Protocol: RemoteProtocolCDP, Endpoint: ""},
+		{Protocol: RemoteProtocolCDP, Endpoint: "ftp://10.0.0.5:9222"},
+		{Protocol: RemoteProtocolPlaywright, Endpoint: "http://10.0.0.5:3000"},
+		{Protocol: "selenium", Endpoint: "ws://10.0.0.5:4444"},
+		{Protocol: RemoteProtocolCDP, Endpoint: "http://10.0.0.5:9222", Timeout: -1},
+	}
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("%+v: expected error", r)
+		}
+	}
+}
+
+func TestRemoteFor(t *testing.T) {
+	shared := &RemoteBrowser{Protocol: RemoteProtocolCDP, Endpoint: "http://fleet:9222"}
+	tencent := &RemoteBrowser{Protocol: RemoteProtocolPlaywright, Endpoint: "ws://fleet:3000/"}
+
+	cfg := PoolConfig{
+		Remote: shared,
+		PlatformRemotes: map[string]*RemoteBrowser{
+			"tencent": tencent,
+			"douyin":  {Protocol: RemoteProtocolCDP}, // 未填写地址，回退到池级配置
+		},
+	}
+	if got := cfg.RemoteFor("tencent"); got != tencent {
+		t.Errorf("tencent = %+v, want platform remote", got)
+	}
+	for _, platform := range []string{"douyin", "bilibili", ""} {
+		if got := cfg.RemoteFor(platform); got != shared {
+			t.Errorf("%q = %+v, want pool remote", platform, got)
+		}
+	}
+
+	local := PoolConfig{PlatformRemotes: map[string]*RemoteBrowser{"tencent": tencent}}
+	if got := local.RemoteFor("douyin"); got != nil {
+		t.Errorf("douyin = %+v, want local", got)
+	}
+	if got := (&PoolConfig{}).RemoteFor("tencent"); got != nil {
+		t.Errorf("default config = %+v, want local", got)
+	}
+}
+
+func TestRemoteKeyAndHost(t *testing.T) {
+	if remoteKey(nil) != "" {
+		t.Error("local browser key should be empty")
+	}
+	a := &RemoteBrowser{Protocol: RemoteProtocolCDP, Endpoint: "http://fleet:9222"}
+	b := &RemoteBrowser{Protocol: RemoteProtocolCDP, Endpoint: "http://fleet:9222"}
+	if remoteKey(a) != remoteKey(b) {
+		t.Error("same endpoint should share browsers")
+	}
+	if got := endpointHost("wss://fleet.example.com/playwright?token=secret"); got != "wss://fleet.example.com" {
+		t.Errorf("endpointHost = %q", got)
+	}
+}
@@ -8,8 +8,6 @@ type Config struct {
 	ElementWaitTimeout   time.Duration
 	SubmitCheckTimeout   time.Duration
 	MaxPublishRetries    int
-	TitleMaxLength       int
-	ShortTitleMaxLength  int
 	UploadCheckInterval  time.Duration
 }
 
@@ -19,8 +17,6 @@ var defaultConfig = Config{
 	ElementWaitTimeout:   5 * time.Second,
 	SubmitCheckTimeout:   100 * time.Second,
 	MaxPublishRetries:    20,
-	TitleMaxLength:       30,
-	ShortTitleMaxLength:  10,
 	UploadCheckInterval:  2 * time.Second,
 }
 
@@ -28,7 +28,7 @@ func (h *CoverHandler) SetCover(page playwright.Page, coverPath string) error {
 		return fmt.Errorf("未找到封面设置按钮: %w", err)
 	}
 
-	if err := coverBtn.Click(); err != nil {
+	if err := human.Click(coverBtn); err != nil {
 		return fmt.Errorf("点击封面设置按钮失败: %w", err)
 	}
 	time.Sleep(2 * time.Second)
@@ -59,7 +59,7 @@ func (h *CoverHandler) SetCover(page playwright.Page, coverPath string) error {
 	}); err != nil {
 		utils.WarnWithPlatform("douyin", "未找到设置竖封面按钮")
 	} else {
-		if err := verticalBtn.Click(); err != nil {
+		if err := human.Click(verticalBtn); err != nil {
 			utils.WarnWithPlatform("douyin", fmt.Sprintf("点击设置竖封面按钮失败: %v", err))
 		} else {
 			utils.InfoWithPlatform("douyin", "已切换到竖封面")
@@ -73,7 +73,7 @@ func (h *CoverHandler) SetCover(page playwright.Page, coverPath string) error {
 	}); err != nil {
 		utils.WarnWithPlatform("douyin", fmt.Sprintf("未找到完成按钮: %v", err))
 	} else {
-		if err := finishBtn.Click(); err != nil {
+		if err := human.Click(finishBtn); err != nil {
 			utils.WarnWithPlatform("douyin", fmt.Sprintf("点击完成按钮失败: %v", err))
 		} else {
 			utils.InfoWithPlatform("douyin", "已点击完成按钮")
@@ -34,11 +34,3 @@ func ConvertPlaywrightCookies(cookies []PlaywrightCookie) string {
 	}
 	return strings.Join(parts, "; ")
 }
-
-func TruncateString(s string, maxLen int) string {
-	runes := []rune(s)
-	if len(runes) <= maxLen {
-		return s
-	}
-	return string(runes[:maxLen])
-}
@@ -45,7 +45,7 @@ func (u *Uploader) setPermissions(page playwright.Page, allowDownload bool) erro
 		}); err != nil {
 			return fmt.Errorf("未找到不允许选项: %w", err)
 		}
-		if err := disallowBtn.Click(); err != nil {
+		if err := human.Click(disallowBtn); err != nil {
 			return fmt.Errorf("点击不允许失败: %w", err)
 		}
 		utils.InfoWithPlatform(u.platform, "已设置不允许下载")
@@ -62,7 +62,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	}); err != nil {
 		return fmt.Errorf("未找到定时发布选项: %w", err)
 	}
-	if err := scheduleBtn.Click(); err != nil {
+	if err := human.Click(scheduleBtn); err != nil {
 		return fmt.Errorf("点击定时发布失败: %w", err)
 	}
 	time.Sleep(1 * time.Second)
@@ -73,7 +73,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	}); err != nil {
 		return fmt.Errorf("未找到时间输入框: %w", err)
 	}
-	if err := timeInput.Fill(scheduleTime); err != nil {
+	if err := human.FillValue(timeInput, scheduleTime); err != nil {
 		return fmt.Errorf("填写定时发布时间失败: %w", err)
 	}
 
@@ -92,7 +92,7 @@ func (u *Uploader) publish(page playwright.Page, browserCtx *browser.PooledConte
 			Exact: playwright.Bool(true),
 		})
 		if count, _ := publishBtn.Count(); count > 0 {
-			if err := publishBtn.Click(); err != nil {
+			if err := human.Click(publishBtn); err != nil {
 				utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 发布 - 点击发布按钮失败: %v", err))
 			}
 		}
@@ -108,11 +108,11 @@ func (u *Uploader) publish(page playwright.Page, browserCtx *browser.PooledConte
 		if visible, _ := coverPrompt.IsVisible(); visible {
 			recommendCover := page.Locator("[class^='recommendCover-']").First()
 			if count, _ := recommendCover.Count(); count > 0 {
-				recommendCover.Click()
+				human.Click(recommendCover)
 				time.Sleep(1 * time.Second)
 				confirmBtn := page.GetByRole("button", playwright.PageGetByRoleOptions{Name: "确定"})
 				if count, _ := confirmBtn.Count(); count > 0 {
-					confirmBtn.Click()
+					human.Click(confirmBtn)
 					time.Sleep(1 * time.Second)
 				}
 			}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
@@ -95,7 +96,7 @@ func (u *Uploader) fillTitle(page playwright.Page, title string) error {
 
 	utils.InfoWithPlatform(u.platform, "填写标题...")
 
-	truncatedTitle := TruncateString(title, u.config.TitleMaxLength)
+	truncatedTitle := platformutils.ApplyTextRule(u.platform, platformutils.FieldTitle, title)
 
 	titleInput := page.Locator(`input[placeholder="填写作品标题，为作品获得更多流量"]`).First()
 	if err := titleInput.WaitFor(playwright.LocatorWaitForOptions{
@@ -104,7 +105,7 @@ func (u *Uploader) fillTitle(page playwright.Page, title string) error {
 		return fmt.Errorf("未找到标题输入框: %w", err)
 	}
 
-	if err := titleInput.Fill(truncatedTitle); err != nil {
+	if err := human.Fill(titleInput, truncatedTitle); err != nil {
 		return fmt.Errorf("填写标题失败: %w", err)
 	}
 
@@ -123,7 +124,8 @@ func (u *Uploader) fillDescription(page playwright.Page, description string) err
 		return fmt.Errorf("未找到描述输入框: %w", err)
 	}
 
-	if err := descContainer.Fill(description); err != nil {
+	description = platformutils.ApplyTextRule(u.platform, platformutils.FieldDescription, description)
+	if err := human.Fill(descContainer, description); err != nil {
 		return fmt.Errorf("填写描述失败: %w", err)
 	}
 
@@ -156,7 +158,8 @@ func (u *Uploader) addTags(page playwright.Page, tags []string) error {
 				continue
 			}
 
-			tagContainer.Type("#"+cleanTag, playwright.LocatorTypeOptions{Delay: playwright.Float(100)})
+			tagContainer.Focus()
+			human.Type(page, "#"+cleanTag, playwright.KeyboardTypeOptions{Delay: playwright.Float(100)})
 			tagContainer.Press("Space")
 			time.Sleep(300 * time.Millisecond)
 		}
@@ -176,14 +179,14 @@ func (u *Uploader) addProductLink(page playwright.Page, productLink, productTitl
 		return fmt.Errorf("未找到添加标签按钮: %w", err)
 	}
 
-	if err := addTagBtn.Click(); err != nil {
+	if err := human.Click(addTagBtn); err != nil {
 		return fmt.Errorf("点击添加标签失败: %w", err)
 	}
 	time.Sleep(1 * time.Second)
 
 	cartBtn := page.GetByText("购物车").First()
 	if count, _ := cartBtn.Count(); count > 0 {
-		cartBtn.Click()
+		human.Click(cartBtn)
 		time.Sleep(1 * time.Second)
 	}
 
@@ -194,16 +197,16 @@ func (u *Uploader) addProductLink(page playwright.Page, productLink, productTitl
 		return fmt.Errorf("未找到商品链接输入框: %w", err)
 	}
 
-	if err := linkInput.Fill(productLink); err != nil {
+	if err := human.FillValue(linkInput, productLink); err != nil {
 		return fmt.Errorf("填写商品链接失败: %w", err)
 	}
 
 	if productTitle != "" {
-		shortTitle := TruncateString(productTitle, u.config.ShortTitleMaxLength)
+		shortTitle := platformutils.ApplyTextRule(u.platform, platformutils.FieldProductTitle, productTitle)
 
 		titleInput := page.Locator("input[placeholder*='短标题']").First()
 		if count, _ := titleInput.Count(); count > 0 {
-			titleInput.Fill(shortTitle)
+			human.Fill(titleInput, shortTitle)
 		}
 	}
 
@@ -13,6 +13,9 @@ import (
 	"github.com/playwright-community/playwright-go"
 )
 
+// human 上传页面的点击和输入，按浏览器池配置的 douyin 人类交互预设执行
+var human = browser.HumanFor("douyin")
+
 func debugLog(format string, args ...interface{}) {
 	if config.Config != nil && config.Config.DebugMode {
 		utils.InfoWithPlatform("douyin", fmt.Sprintf("[调试] "+format, args...))
@@ -58,8 +61,10 @@ func NewUploaderWithAccount(accountID uint) *Uploader {
 	return u
 }
 
-func NewUploaderWithPool(accountID uint, pool *browser.Pool) *Uploader {
-	cookiePath := config.GetCookiePath("douyin", int(accountID))
+func NewUploaderWithPool(accountID uint, cookiePath string, pool *browser.Pool) *Uploader {
+	if cookiePath == "" {
+		cookiePath = config.GetCookiePath("douyin", int(accountID))
+	}
 	u := &Uploader{
 		accountID:   accountID,
 		cookiePath:  cookiePath,
@@ -21,7 +21,7 @@ func (u *Uploader) setCover(page playwright.Page, coverPath string) error {
 	}); err != nil {
 		return fmt.Errorf("未找到封面设置按钮: %v", err)
 	}
-	if err := coverSettingBtn.Click(); err != nil {
+	if err := human.Click(coverSettingBtn); err != nil {
 		return fmt.Errorf("点击封面设置按钮失败: %v", err)
 	}
 	time.Sleep(1 * time.Second)
@@ -34,7 +34,7 @@ func (u *Uploader) setCover(page playwright.Page, coverPath string) error {
 	}); err != nil {
 		return fmt.Errorf("未找到上传封面标签: %v", err)
 	}
-	if err := uploadCoverTab.Click(); err != nil {
+	if err := human.Click(uploadCoverTab); err != nil {
 		return fmt.Errorf("点击上传封面标签失败: %v", err)
 	}
 	time.Sleep(1 * time.Second)
@@ -56,7 +56,7 @@ func (u *Uploader) setCover(page playwright.Page, coverPath string) error {
 	}); err != nil {
 		return fmt.Errorf("未找到确认按钮: %v", err)
 	}
-	if err := confirmBtn.Click(); err != nil {
+	if err := human.Click(confirmBtn); err != nil {
 		return fmt.Errorf("点击确认按钮失败: %v", err)
 	}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
@@ -15,7 +16,7 @@ func (u *Uploader) handleNewFeatureGuide(page playwright.Page) error {
 	newFeatureBtn := page.Locator("button[type='button'] span:has-text('我知道了')")
 	count, _ := newFeatureBtn.Count()
 	if count > 0 {
-		if err := newFeatureBtn.Click(); err == nil {
+		if err := human.Click(newFeatureBtn); err == nil {
 			time.Sleep(1 * time.Second)
 		}
 	}
@@ -26,7 +27,7 @@ func (u *Uploader) handleSkipPopup(page playwright.Page) error {
 	skipBtn := page.Locator(`div[aria-label="Skip"][title="Skip"]`).First()
 	count, _ := skipBtn.Count()
 	if count > 0 {
-		if err := skipBtn.Click(); err != nil {
+		if err := human.Click(skipBtn); err != nil {
 			return fmt.Errorf("点击Skip按钮失败: %v", err)
 		}
 		time.Sleep(500 * time.Millisecond)
@@ -45,13 +46,13 @@ func (u *Uploader) setDownloadPermission(page playwright.Page, allowDownload boo
 	if parentCount, _ := parentCheckbox.Count(); parentCount > 0 {
 		isChecked, _ := parentCheckbox.IsChecked()
 		if isChecked != allowDownload {
-			if err := checkbox.Click(); err != nil {
+			if err := human.Click(checkbox); err != nil {
 				return fmt.Errorf("点击下载权限选项失败: %v", err)
 			}
 		}
 	} else {
 		if !allowDownload {
-			if err := checkbox.Click(); err != nil {
+			if err := human.Click(checkbox); err != nil {
 				return fmt.Errorf("点击下载权限选项失败: %v", err)
 			}
 		}
@@ -77,7 +78,7 @@ func (u *Uploader) fillDescription(page playwright.Page, title, description stri
 		}
 	}
 
-	if err := descArea.Click(); err != nil {
+	if err := human.Click(descArea); err != nil {
 		return fmt.Errorf("点击描述区域失败: %v", err)
 	}
 	time.Sleep(300 * time.Millisecond)
@@ -86,19 +87,14 @@ func (u *Uploader) fillDescription(page playwright.Page, title, description stri
 	page.Keyboard().Press("Delete")
 	time.Sleep(300 * time.Millisecond)
 
-	content := ""
-	if title != "" {
-		content = title
-	}
-	if description != "" {
-		if content != "" {
-			content += "\n"
-		}
-		content += description
-	}
+	// 标题和描述填写在同一个输入框，合并后的正文同样受长度上限约束
+	title, description = platformutils.ApplyCaptionRule(u.platform, title, description)
+	content := platformutils.JoinCaption(title, description)
 
 	if content != "" {
-		page.Keyboard().Type(content)
+		if err := human.Type(page, content); err != nil {
+			return fmt.Errorf("填写正文失败: %v", err)
+		}
 		utils.InfoWithPlatform(u.platform, fmt.Sprintf("标题已填写: %s", title))
 		utils.InfoWithPlatform(u.platform, "描述已填写")
 	}
@@ -108,14 +104,14 @@ func (u *Uploader) fillDescription(page playwright.Page, title, description stri
 }
 
 func (u *Uploader) addTags(page playwright.Page, tags []string) error {
-	maxTags := 3
-	if len(tags) < maxTags {
-		maxTags = len(tags)
+	tags, dropped := platformutils.LimitTags(u.platform, tags)
+	if len(dropped) > 0 {
+		utils.WarnWithPlatform(u.platform, fmt.Sprintf("标签超过平台上限 %d 个，已忽略: %s", platformutils.GetTagLimit(u.platform), strings.Join(dropped, ", ")))
 	}
 
 	utils.InfoWithPlatform(u.platform, "添加标签...")
 
-	for _, tag := range tags[:maxTags] {
+	for _, tag := range tags {
 		cleanTag := strings.TrimSpace(tag)
 		cleanTag = strings.ReplaceAll(cleanTag, "#", "")
 
@@ -123,12 +119,12 @@ func (u *Uploader) addTags(page playwright.Page, tags []string) error {
 			continue
 		}
 
-		page.Keyboard().Type(fmt.Sprintf("#%s", cleanTag))
+		human.Type(page, fmt.Sprintf("#%s", cleanTag))
 		time.Sleep(1 * time.Second)
 
 		suggestion := page.Locator(`[class*='tag-suggestion'], [class*='topic-item'], [class*='mention-item']`).First()
 		if count, _ := suggestion.Count(); count > 0 {
-			if err := suggestion.Click(); err == nil {
+			if err := human.Click(suggestion); err == nil {
 				utils.InfoWithPlatform(u.platform, fmt.Sprintf("标签添加成功: #%s", cleanTag))
 			}
 		}
@@ -148,7 +144,7 @@ func (u *Uploader) publish(page playwright.Page, browserCtx *browser.PooledConte
 		publishButton := page.GetByText("发布", playwright.PageGetByTextOptions{Exact: playwright.Bool(true)})
 		count, _ := publishButton.Count()
 		if count > 0 {
-			if err := publishButton.Click(); err != nil {
+			if err := human.Click(publishButton); err != nil {
 				utils.WarnWithPlatform(u.platform, fmt.Sprintf("点击发布按钮失败: %v", err))
 			}
 		}
@@ -158,7 +154,7 @@ func (u *Uploader) publish(page playwright.Page, browserCtx *browser.PooledConte
 		confirmButton := page.GetByText("确认发布")
 		confirmCount, _ := confirmButton.Count()
 		if confirmCount > 0 {
-			if err := confirmButton.Click(); err != nil {
+			if err := human.Click(confirmButton); err != nil {
 				utils.WarnWithPlatform(u.platform, fmt.Sprintf("点击确认发布失败: %v", err))
 			}
 		}
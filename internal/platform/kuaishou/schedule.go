@@ -28,9 +28,9 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	}
 
 	scheduleRadio := scheduleLabel.Locator("xpath=following-sibling::div").Locator(".ant-radio-input").Nth(1)
-	if err := scheduleRadio.Click(); err != nil {
+	if err := human.Click(scheduleRadio); err != nil {
 		scheduleText := page.GetByText("定时发布")
-		if err := scheduleText.Click(); err != nil {
+		if err := human.Click(scheduleText); err != nil {
 			return fmt.Errorf("点击定时发布失败: %v", err)
 		}
 	}
@@ -42,7 +42,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	}); err != nil {
 		return fmt.Errorf("未找到定时发布输入框: %v", err)
 	}
-	if err := scheduleInput.Click(); err != nil {
+	if err := human.Click(scheduleInput); err != nil {
 		return fmt.Errorf("点击定时发布输入框失败: %v", err)
 	}
 	time.Sleep(1 * time.Second)
@@ -54,7 +54,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	}); err != nil {
 		return fmt.Errorf("未找到日期单元格 %s: %v", dateStr, err)
 	}
-	if err := dateCell.Click(); err != nil {
+	if err := human.Click(dateCell); err != nil {
 		return fmt.Errorf("点击日期单元格失败: %v", err)
 	}
 	time.Sleep(500 * time.Millisecond)
@@ -62,7 +62,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	hourStr := targetTime.Format("15")
 	hourCell := page.Locator(fmt.Sprintf(`div.ant-picker-time-panel-column >> div.ant-picker-time-panel-cell-inner:has-text("%s")`, hourStr)).First()
 	if count, _ := hourCell.Count(); count > 0 {
-		if err := hourCell.Click(); err != nil {
+		if err := human.Click(hourCell); err != nil {
 			return fmt.Errorf("选择小时失败: %v", err)
 		}
 		time.Sleep(300 * time.Millisecond)
@@ -71,7 +71,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	minuteStr := targetTime.Format("04")
 	minuteCell := page.Locator(fmt.Sprintf(`div.ant-picker-time-panel-column >> div.ant-picker-time-panel-cell-inner:has-text("%s")`, minuteStr)).Nth(1)
 	if count, _ := minuteCell.Count(); count > 0 {
-		if err := minuteCell.Click(); err != nil {
+		if err := human.Click(minuteCell); err != nil {
 			return fmt.Errorf("选择分钟失败: %v", err)
 		}
 		time.Sleep(300 * time.Millisecond)
@@ -80,7 +80,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	secondStr := targetTime.Format("05")
 	secondCell := page.Locator(fmt.Sprintf(`div.ant-picker-time-panel-column >> div.ant-picker-time-panel-cell-inner:has-text("%s")`, secondStr)).Nth(2)
 	if count, _ := secondCell.Count(); count > 0 {
-		if err := secondCell.Click(); err != nil {
+		if err := human.Click(secondCell); err != nil {
 			return fmt.Errorf("选择秒失败: %v", err)
 		}
 		time.Sleep(300 * time.Millisecond)
@@ -92,7 +92,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	}); err != nil {
 		return fmt.Errorf("未找到确定按钮: %v", err)
 	}
-	if err := confirmBtn.Click(); err != nil {
+	if err := human.Click(confirmBtn); err != nil {
 		return fmt.Errorf("点击确定按钮失败: %v", err)
 	}
 	time.Sleep(1 * time.Second)
@@ -23,7 +23,7 @@ func (u *Uploader) uploadVideo(ctx context.Context, page playwright.Page, browse
 	}
 
 	fileChooser, err := page.ExpectFileChooser(func() error {
-		return uploadButton.Click()
+		return human.Click(uploadButton)
 	})
 	if err != nil {
 		return fmt.Errorf("等待文件选择器失败: %v", err)
@@ -14,6 +14,9 @@ import (
 	"github.com/playwright-community/playwright-go"
 )
 
+// human 上传页面的点击和输入，按浏览器池配置的 kuaishou 人类交互预设执行
+var human = browser.HumanFor("kuaishou")
+
 type Uploader struct {
 	accountID   uint
 	cookiePath  string
@@ -0,0 +1,112 @@
+package platformutils
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
+	"fmt"
+	"time"
+
+	// 内置时区数据，保证未安装 zoneinfo 的系统（如 Windows）也能解析账号时区
+	_ "time/tzdata"
+)
+
+// ScheduleRule 平台定时发布规则
+type ScheduleRule struct {
+	MinLead     time.Duration // 最少提前量
+	MaxHorizon  time.Duration // 最远可定时范围
+	Granularity time.Duration // 时间粒度，定时时间向上取整到该粒度
+}
+
+// platformScheduleRules 各平台定时发布规则（取各平台发布页的限制）
+var platformScheduleRules = map[string]ScheduleRule{
+	config.PlatformDouyin:      {MinLead: 2 * time.Hour, MaxHorizon: 14 * 24 * time.Hour, Granularity: time.Minute},
+	config.PlatformXiaohongshu: {MinLead: time.Hour, MaxHorizon: 14 * 24 * time.Hour, Granularity: time.Minute},
+	config.PlatformKuaishou:    {MinLead: time.Hour, MaxHorizon: 14 * 24 * time.Hour, Granularity: time.Minute},
+	config.PlatformTencent:     {MinLead: time.Hour, MaxHorizon: 30 * 24 * time.Hour, Granularity: time.Minute},
+	config.PlatformBilibili:    {MinLead: 2 * time.Hour, MaxHorizon: 15 * 24 * time.Hour, Granularity: time.Minute},
+	config.PlatformBaijiahao:   {MinLead: time.Hour, MaxHorizon: 7 * 24 * time.Hour, Granularity: time.Minute},
+	config.PlatformTiktok:      {MinLead: 20 * time.Minute, MaxHorizon: 10 * 24 * time.Hour, Granularity: 5 * time.Minute},
+}
+
+// defaultScheduleRule 未配置平台使用的规则
+var defaultScheduleRule = ScheduleRule{MinLead: 2 * time.Hour, MaxHorizon: 15 * 24 * time.Hour, Granularity: time.Minute}
+
+// PageTimeFormat 传给各平台 setScheduleTime 的时间格式
+const PageTimeFormat = "2006-01-02 15:04"
+
+// GetScheduleRule 获取平台定时发布规则
+func GetScheduleRule(platform string) ScheduleRule {
+	if rule, ok := platformScheduleRules[platform]; ok {
+		return rule
+	}
+	return defaultScheduleRule
+}
+
+// PageLocation 平台发布页使用的时区（与账号浏览器上下文的时区一致，见 config.AccountBrowserRegion）
+func PageLocation(platform, accountTimeZone string) *time.Location {
+	loc, err := time.LoadLocation(config.AccountBrowserRegion(platform, accountTimeZone).TimezoneID)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// CheckScheduleTime 校验并转换定时时间：
+// 不带时区偏移的输入按账号时区解析，按平台粒度向上取整，校验提前量和最远范围，
+// 再转换为平台发布页时区（pageLoc，见 PageLocation）下的时间字符串（PageTime），供各平台 setScheduleTime 直接使用
+func CheckScheduleTime(platform, input string, accountLoc, pageLoc *time.Location, now time.Time) (types.ScheduleTimeCheck, error) {
+	if accountLoc == nil {
+		accountLoc = time.Local
+	}
+	if pageLoc == nil {
+		pageLoc = PageLocation(platform, "")
+	}
+	rule := GetScheduleRule(platform)
+
+	earliest := now.Add(rule.MinLead)
+	latest := now.Add(rule.MaxHorizon)
+	check := types.ScheduleTimeCheck{
+		Platform:     platform,
+		Input:        input,
+		AccountZone:  accountLoc.String(),
+		PageZone:     pageLoc.String(),
+		MinLead:      utils.FormatDuration(rule.MinLead),
+		MaxHorizon:   utils.FormatDuration(rule.MaxHorizon),
+		EarliestTime: earliest.In(accountLoc).Format(PageTimeFormat),
+		LatestTime:   latest.In(accountLoc).Format(PageTimeFormat),
+	}
+
+	t, err := utils.ParseScheduleTimeIn(input, accountLoc)
+	if err != nil {
+		return check, err
+	}
+
+	rounded := CeilTime(t, rule.Granularity)
+	check.Rounded = !rounded.Equal(t)
+	t = rounded
+	check.Instant = t.Format(time.RFC3339)
+	check.PageTime = t.In(pageLoc).Format(PageTimeFormat)
+
+	if t.Before(earliest) {
+		return check, fmt.Errorf("%s 定时时间必须至少提前%s（最早 %s %s）",
+			platform, check.MinLead, check.EarliestTime, check.AccountZone)
+	}
+	if t.After(latest) {
+		return check, fmt.Errorf("%s 定时时间不能超过%s（最晚 %s %s）",
+			platform, check.MaxHorizon, check.LatestTime, check.AccountZone)
+	}
+	return check, nil
+}
+
+// CeilTime 向上取整到粒度
+func CeilTime(t time.Time, granularity time.Duration) time.Time {
+	if granularity <= 0 {
+		granularity = time.Minute
+	}
+	truncated := t.Truncate(granularity)
+	if truncated.Equal(t) {
+		return t
+	}
+	return truncated.Add(granularity)
+}
@@ -0,0 +1,74 @@
+package platformutils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckScheduleTime(t *testing.T) {
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	losAngeles, _ := time.LoadLocation("America/Los_Angeles")
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, shanghai)
+
+	t.Run("account_timezone_to_page", func(t *testing.T) {
+		// 洛杉矶 3 月 5 日 18:00 = 上海 3 月 6 日 10:00
+		check, err := CheckScheduleTime("douyin", "2024-03-05 18:00", losAngeles, nil, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.PageTime != "2024-03-06 10:00" || check.PageZone != "Asia/Shanghai" {
+			t.Errorf("平台页面时间错误: %s (%s)", check.PageTime, check.PageZone)
+		}
+	})
+
+	t.Run("tiktok_page_zone", func(t *testing.T) {
+		// 未设置时区的 TikTok 账号上下文使用伦敦时区：洛杉矶 3 月 5 日 18:00 = 伦敦 3 月 6 日 02:00
+		check, err := CheckScheduleTime("tiktok", "2024-03-05 18:00", losAngeles, PageLocation("tiktok", ""), now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.PageTime != "2024-03-06 02:00" || check.PageZone != "Europe/London" {
+			t.Errorf("TikTok 页面时间错误: %s (%s)", check.PageTime, check.PageZone)
+		}
+	})
+
+	t.Run("account_page_zone", func(t *testing.T) {
+		// 设置了时区的账号上下文使用账号时区，页面时间与输入一致
+		check, err := CheckScheduleTime("tiktok", "2024-03-05 18:00", losAngeles, PageLocation("tiktok", "America/Los_Angeles"), now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.PageTime != "2024-03-05 18:00" || check.PageZone != "America/Los_Angeles" {
+			t.Errorf("账号时区页面时间错误: %s (%s)", check.PageTime, check.PageZone)
+		}
+		if loc := PageLocation("douyin", "Invalid/Zone"); loc.String() != "Asia/Shanghai" {
+			t.Errorf("无效账号时区应使用平台默认时区: %s", loc)
+		}
+	})
+
+	t.Run("tiktok_granularity", func(t *testing.T) {
+		check, err := CheckScheduleTime("tiktok", "2024-03-05 18:02", shanghai, nil, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 上海 18:05 = 伦敦 10:05
+		if !check.Rounded || check.PageTime != "2024-03-05 10:05" {
+			t.Errorf("应向上取整到 5 分钟: %+v", check)
+		}
+	})
+
+	t.Run("platform_windows", func(t *testing.T) {
+		if _, err := CheckScheduleTime("douyin", "2024-03-05 13:30", shanghai, nil, now); err == nil {
+			t.Error("抖音应至少提前 2 小时")
+		}
+		if _, err := CheckScheduleTime("tencent", "2024-03-05 13:30", shanghai, nil, now); err != nil {
+			t.Errorf("视频号提前 1.5 小时应有效: %v", err)
+		}
+		if _, err := CheckScheduleTime("douyin", "2024-03-25 12:00", shanghai, nil, now); err == nil {
+			t.Error("抖音不能超过 14 天")
+		}
+		if _, err := CheckScheduleTime("tencent", "2024-03-25 12:00", shanghai, nil, now); err != nil {
+			t.Errorf("视频号 20 天内应有效: %v", err)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package platformutils
+
+import (
+	"Fuploader/internal/config"
+	"strings"
+)
+
+// platformTagLimits 各平台单条作品的标签数量上限（取各平台发布页的保守值）
+var platformTagLimits = map[string]int{
+	config.PlatformDouyin:      5,
+	config.PlatformKuaishou:    3,
+	config.PlatformXiaohongshu: 10,
+	config.PlatformTencent:     10,
+	config.PlatformBilibili:    10,
+	config.PlatformBaijiahao:   5,
+	config.PlatformTiktok:      5,
+}
+
+// GetTagLimit 获取平台标签数量上限，0 表示不限制
+func GetTagLimit(platform string) int {
+	return platformTagLimits[platform]
+}
+
+// LimitTags 清理标签（去掉 # 与空白、去重）并按平台上限截取
+// 返回保留的标签和被丢弃的标签
+func LimitTags(platform string, tags []string) ([]string, []string) {
+	seen := make(map[string]bool)
+	var kept, dropped []string
+	limit := GetTagLimit(platform)
+
+	for _, tag := range tags {
+		clean := strings.TrimSpace(strings.ReplaceAll(tag, "#", ""))
+		if platform == config.PlatformTiktok {
+			clean = strings.Join(strings.Fields(clean), "")
+		}
+		if clean == "" || seen[strings.ToLower(clean)] {
+			continue
+		}
+		seen[strings.ToLower(clean)] = true
+
+		if limit > 0 && len(kept) >= limit {
+			dropped = append(dropped, clean)
+			continue
+		}
+		kept = append(kept, clean)
+	}
+	return kept, dropped
+}
@@ -0,0 +1,43 @@
+package platformutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLimitTags(t *testing.T) {
+	t.Run("clean_and_dedupe", func(t *testing.T) {
+		kept, dropped := LimitTags("bilibili", []string{"#旅行", " 旅行 ", "Vlog", "vlog#", "", "#", "美食"})
+		if want := []string{"旅行", "Vlog", "美食"}; !reflect.DeepEqual(kept, want) {
+			t.Errorf("kept = %v, want %v", kept, want)
+		}
+		if len(dropped) != 0 {
+			t.Errorf("重复标签不应计入丢弃: %v", dropped)
+		}
+	})
+
+	t.Run("truncate_at_limit", func(t *testing.T) {
+		tags := []string{"a", "b", "A", "c", "d", "e", "f", "g"}
+		kept, dropped := LimitTags("douyin", tags)
+		if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(kept, want) {
+			t.Errorf("kept = %v, want %v", kept, want)
+		}
+		if want := []string{"f", "g"}; !reflect.DeepEqual(dropped, want) {
+			t.Errorf("dropped = %v, want %v", dropped, want)
+		}
+	})
+
+	t.Run("tiktok_removes_spaces", func(t *testing.T) {
+		kept, _ := LimitTags("tiktok", []string{"travel vlog", "travelvlog", "#food"})
+		if want := []string{"travelvlog", "food"}; !reflect.DeepEqual(kept, want) {
+			t.Errorf("kept = %v, want %v", kept, want)
+		}
+	})
+
+	t.Run("unknown_platform_unlimited", func(t *testing.T) {
+		tags := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+		if kept, dropped := LimitTags("other", tags); len(kept) != len(tags) || len(dropped) != 0 {
+			t.Errorf("kept = %v, dropped = %v", kept, dropped)
+		}
+	})
+}
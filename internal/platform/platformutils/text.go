@@ -0,0 +1,377 @@
+package platformutils
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// 文本字段
+const (
+	FieldTitle        = "title"
+	FieldShortTitle   = "shortTitle"
+	FieldDescription  = "description"
+	FieldProductTitle = "productTitle"
+	FieldCaption      = "caption" // 标题和描述合并填写的正文（如 TikTok、快手、视频号只有一个输入框）
+)
+
+// captionSeparator 合并正文时标题与描述之间的分隔
+const captionSeparator = "\n\n"
+
+// LengthMode 平台计算字数的方式
+type LengthMode string
+
+const (
+	LengthRunes LengthMode = "runes" // 按字符计数
+	LengthUTF16 LengthMode = "utf16" // 按 UTF-16 码元计数（网页 input maxlength 的计算方式，emoji 计为 2）
+	LengthBytes LengthMode = "bytes" // 按 UTF-8 字节计数
+)
+
+// TextRule 文本字段规则
+type TextRule struct {
+	MaxLength   int             // 最大长度，0 表示不限制
+	MinLength   int             // 最小长度，0 表示不限制
+	Mode        LengthMode      // 计数方式
+	EmojiWeight int             // emoji 计数权重，0 表示按计数方式计算
+	SingleLine  bool            // 单行文本，换行/制表符替换为空格
+	StripEmoji  bool            // 不支持 emoji
+	Allowed     func(rune) bool // 允许的字符，为 nil 时不过滤
+	Replace     map[rune]rune   // 字符替换
+	PadWith     string          // 长度不足时补齐内容，为空时不补齐
+}
+
+// platformTextRules 各平台文本规则
+var platformTextRules = map[string]map[string]TextRule{
+	config.PlatformDouyin: {
+		FieldTitle:        {MaxLength: 30, Mode: LengthUTF16, SingleLine: true},
+		FieldDescription:  {MaxLength: 1000, Mode: LengthUTF16},
+		FieldProductTitle: {MaxLength: 10, Mode: LengthRunes, SingleLine: true},
+	},
+	config.PlatformXiaohongshu: {
+		FieldTitle:       {MaxLength: 30, Mode: LengthRunes, EmojiWeight: 2, SingleLine: true},
+		FieldDescription: {MaxLength: 1000, Mode: LengthRunes, EmojiWeight: 2},
+	},
+	config.PlatformTencent: {
+		FieldShortTitle: {
+			MinLength:  6,
+			MaxLength:  16,
+			Mode:       LengthRunes,
+			SingleLine: true,
+			Allowed:    isTencentShortTitleRune,
+			Replace:    map[rune]rune{',': ' ', '，': ' '},
+			PadWith:    " ",
+		},
+		FieldDescription: {MaxLength: 1000, Mode: LengthRunes},
+		FieldCaption:     {MaxLength: 1000, Mode: LengthRunes},
+	},
+	config.PlatformBaijiahao: {
+		FieldTitle: {MinLength: 8, MaxLength: 30, Mode: LengthRunes, SingleLine: true, PadWith: " 你不知道的"},
+	},
+	config.PlatformBilibili: {
+		FieldTitle:       {MaxLength: 80, Mode: LengthRunes, SingleLine: true, StripEmoji: true},
+		FieldDescription: {MaxLength: 2000, Mode: LengthRunes},
+	},
+	config.PlatformKuaishou: {
+		FieldDescription: {MaxLength: 500, Mode: LengthRunes},
+		FieldCaption:     {MaxLength: 500, Mode: LengthRunes},
+	},
+	config.PlatformTiktok: {
+		FieldTitle:       {MaxLength: 150, Mode: LengthUTF16, SingleLine: true},
+		FieldDescription: {MaxLength: 2200, Mode: LengthUTF16},
+		FieldCaption:     {MaxLength: 2200, Mode: LengthUTF16},
+	},
+}
+
+// GetTextRule 获取平台字段规则
+func GetTextRule(platform, field string) (TextRule, bool) {
+	rules, ok := platformTextRules[platform]
+	if !ok {
+		return TextRule{}, false
+	}
+	rule, ok := rules[field]
+	return rule, ok
+}
+
+// isTencentShortTitleRune 视频号短标题只支持中英文、数字和少量符号
+func isTencentShortTitleRune(r rune) bool {
+	if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+		return true
+	}
+	if r >= 0x4e00 && r <= 0x9fff {
+		return true
+	}
+	return strings.ContainsRune("《》：+?%° ", r)
+}
+
+// isInvisibleRune 零宽字符等不可见字符
+func isInvisibleRune(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+		return true
+	}
+	return unicode.IsControl(r) && r != '\n' && r != '\t' && r != '\r'
+}
+
+// isEmojiRune 粗略判断 emoji（含变体选择符）
+func isEmojiRune(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) ||
+		(r >= 0x2600 && r <= 0x27BF) ||
+		r == 0xFE0F
+}
+
+// isWordRune 判断是否为需要保持完整的单词字符（拉丁字母、数字）
+// 中日韩文字之间可以任意断开，不视为单词字符
+func isWordRune(r rune) bool {
+	if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '-'
+}
+
+// isBreakRune 可作为截断点的标点或空白
+func isBreakRune(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("，。！？；、,.!?;|｜:：…~～", r)
+}
+
+// runeWeight 单个字符的计数权重
+func (rule TextRule) runeWeight(r rune) int {
+	if rule.EmojiWeight > 0 && isEmojiRune(r) {
+		return rule.EmojiWeight
+	}
+	switch rule.Mode {
+	case LengthUTF16:
+		return len(utf16.Encode([]rune{r}))
+	case LengthBytes:
+		return len(string(r))
+	default:
+		return 1
+	}
+}
+
+// Length 按平台方式计算文本长度
+func (rule TextRule) Length(text string) int {
+	n := 0
+	for _, r := range text {
+		n += rule.runeWeight(r)
+	}
+	return n
+}
+
+// NormalizeText 按平台规则规范化文本，返回结果及变换说明
+// 平台或字段没有规则时仅清理不可见字符
+func NormalizeText(platform, field, text string) types.TextNormalization {
+	rule, _ := GetTextRule(platform, field)
+	return rule.Normalize(field, text)
+}
+
+// ApplyTextRule 规范化文本并在日志中记录每一项变换，供上传器填写前调用
+func ApplyTextRule(platform, field, text string) string {
+	result := NormalizeText(platform, field, text)
+	for _, change := range result.Changes {
+		utils.WarnWithPlatform(platform, fmt.Sprintf("%s: %s", field, change))
+	}
+	return result.Result
+}
+
+// NormalizeCaption 按平台规则分别规范化标题和描述
+// 平台有合并正文上限时，继续截断描述，使标题和描述合并后的正文不超过上限
+func NormalizeCaption(platform, title, description string) (types.TextNormalization, types.TextNormalization) {
+	titleResult := NormalizeText(platform, FieldTitle, title)
+	descRule, _ := GetTextRule(platform, FieldDescription)
+	captionRule, ok := GetTextRule(platform, FieldCaption)
+	if !ok || captionRule.MaxLength == 0 || titleResult.Result == "" {
+		return titleResult, descRule.Normalize(FieldDescription, description)
+	}
+
+	remain := captionRule.MaxLength - captionRule.Length(titleResult.Result+captionSeparator)
+	if remain <= 0 {
+		descResult := descRule.Normalize(FieldDescription, description)
+		if descResult.Result != "" {
+			descResult.Result = ""
+			descResult.Length = 0
+			descResult.Truncated = true
+			descResult.Changes = append(descResult.Changes, "标题已达到正文长度上限，描述已省略")
+		}
+		return titleResult, descResult
+	}
+	if descRule.MaxLength == 0 || remain < descRule.MaxLength {
+		descRule.MaxLength = remain
+	}
+	return titleResult, descRule.Normalize(FieldDescription, description)
+}
+
+// ApplyCaptionRule 规范化标题和描述并在日志中记录每一项变换，供合并填写正文的上传器调用
+func ApplyCaptionRule(platform, title, description string) (string, string) {
+	titleResult, descResult := NormalizeCaption(platform, title, description)
+	for _, n := range []types.TextNormalization{titleResult, descResult} {
+		for _, change := range n.Changes {
+			utils.WarnWithPlatform(platform, fmt.Sprintf("%s: %s", n.Field, change))
+		}
+	}
+	return titleResult.Result, descResult.Result
+}
+
+// ShortTitleFor 平台实际填写的短标题：未单独填写短标题时使用标题；平台没有短标题字段时返回空
+func ShortTitleFor(platform, shortTitle, title string) string {
+	if _, ok := GetTextRule(platform, FieldShortTitle); !ok {
+		return ""
+	}
+	if strings.TrimSpace(shortTitle) != "" {
+		return shortTitle
+	}
+	return title
+}
+
+// JoinCaption 将标题和描述合并为正文
+func JoinCaption(title, description string) string {
+	if description == "" {
+		return title
+	}
+	if title == "" {
+		return description
+	}
+	return title + captionSeparator + description
+}
+
+// Normalize 按规则规范化文本
+func (rule TextRule) Normalize(field, text string) types.TextNormalization {
+	result := types.TextNormalization{
+		Field:     field,
+		Original:  text,
+		MaxLength: rule.MaxLength,
+	}
+
+	var invisible, newlines int
+	var removed []rune
+	runes := make([]rune, 0, len(text))
+	for _, r := range text {
+		switch {
+		case isInvisibleRune(r):
+			invisible++
+			continue
+		case rule.SingleLine && (r == '\n' || r == '\r' || r == '\t'):
+			newlines++
+			r = ' '
+		}
+		if to, ok := rule.Replace[r]; ok {
+			r = to
+		}
+		if (rule.StripEmoji && isEmojiRune(r)) || (rule.Allowed != nil && !rule.Allowed(r)) {
+			removed = append(removed, r)
+			continue
+		}
+		runes = append(runes, r)
+	}
+
+	if invisible > 0 {
+		result.Changes = append(result.Changes, fmt.Sprintf("移除 %d 个不可见字符", invisible))
+	}
+	if newlines > 0 {
+		result.Changes = append(result.Changes, "换行已替换为空格")
+	}
+	if len(removed) > 0 {
+		result.Changes = append(result.Changes, fmt.Sprintf("移除平台不支持的字符: %s", uniqueRunes(removed)))
+	}
+
+	cleaned := string(runes)
+	if rule.SingleLine {
+		cleaned = strings.Join(strings.Fields(cleaned), " ")
+	} else {
+		cleaned = strings.TrimSpace(cleaned)
+	}
+
+	if rule.MaxLength > 0 {
+		if length := rule.Length(cleaned); length > rule.MaxLength {
+			cleaned = rule.truncate(cleaned)
+			result.Truncated = true
+			result.Changes = append(result.Changes,
+				fmt.Sprintf("超出长度限制，已截断: %d → %d（上限 %d）", length, rule.Length(cleaned), rule.MaxLength))
+		}
+	}
+
+	if rule.MinLength > 0 && rule.PadWith != "" && cleaned != "" {
+		if length := rule.Length(cleaned); length < rule.MinLength {
+			for rule.Length(cleaned) < rule.MinLength {
+				cleaned += rule.PadWith
+			}
+			if rule.MaxLength > 0 && rule.Length(cleaned) > rule.MaxLength {
+				cleaned = rule.hardCut(cleaned)
+			}
+			result.Changes = append(result.Changes,
+				fmt.Sprintf("不足最少 %d 字，已补齐: %q", rule.MinLength, cleaned))
+		}
+	}
+
+	result.Result = cleaned
+	result.Length = rule.Length(cleaned)
+	return result
+}
+
+// hardCut 在长度上限处直接截断
+func (rule TextRule) hardCut(text string) string {
+	runes := []rune(text)
+	n, total := 0, 0
+	for n < len(runes) {
+		w := rule.runeWeight(runes[n])
+		if total+w > rule.MaxLength {
+			break
+		}
+		total += w
+		n++
+	}
+	return string(runes[:n])
+}
+
+// truncate 截断到长度上限，优先在标点/空白处断开，避免截断英文单词
+func (rule TextRule) truncate(text string) string {
+	runes := []rune(text)
+	n := len([]rune(rule.hardCut(text)))
+	cut := n
+
+	// 在末尾 30% 范围内寻找标点或空白
+	window := n * 3 / 10
+	for i := n; i > n-window && i > 0; i-- {
+		if isBreakRune(runes[i-1]) {
+			cut = i
+			break
+		}
+		if i < len(runes) && isBreakRune(runes[i]) {
+			cut = i
+			break
+		}
+	}
+
+	// 没有合适的标点时，避免从英文单词中间截断
+	if cut == n && n < len(runes) && n > 0 && isWordRune(runes[n-1]) && isWordRune(runes[n]) {
+		i := n
+		for i > 0 && isWordRune(runes[i-1]) {
+			i--
+		}
+		if i >= n/2 {
+			cut = i
+		}
+	}
+
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("，、,|｜:：-—（(《「【[", r)
+	})
+}
+
+// uniqueRunes 去重后拼接字符
+func uniqueRunes(runes []rune) string {
+	seen := make(map[rune]bool)
+	var b strings.Builder
+	for _, r := range runes {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		b.WriteRune(r)
+	}
+	return b.String()
+}
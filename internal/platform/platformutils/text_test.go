@@ -0,0 +1,132 @@
+package platformutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	t.Run("truncate_at_punctuation", func(t *testing.T) {
+		n := NormalizeText("douyin", FieldTitle, "今天去了海边看日落，风景特别好，下次还要再来一次，推荐给大家周末一起去")
+		if !n.Truncated || n.Length > 30 {
+			t.Fatalf("应截断到 30 以内: %+v", n)
+		}
+		if !strings.HasSuffix(n.Result, "再来一次") {
+			t.Errorf("应在标点处截断: %s", n.Result)
+		}
+	})
+
+	t.Run("keep_english_words", func(t *testing.T) {
+		n := NormalizeText("douyin", FieldTitle, "My weekend trip vlog with friends in Shanghai")
+		if strings.HasSuffix(n.Result, "Shan") || strings.HasSuffix(n.Result, "frie") {
+			t.Errorf("不应截断英文单词: %q", n.Result)
+		}
+		if n.Result != "My weekend trip vlog with" {
+			t.Errorf("截断结果错误: %q", n.Result)
+		}
+	})
+
+	t.Run("utf16_emoji_weight", func(t *testing.T) {
+		rule := TextRule{MaxLength: 4, Mode: LengthUTF16}
+		if got := rule.Length("a😀b"); got != 4 {
+			t.Errorf("UTF-16 计数错误: %d", got)
+		}
+		if got := (TextRule{Mode: LengthRunes, EmojiWeight: 2}).Length("😀😀"); got != 4 {
+			t.Errorf("emoji 权重错误: %d", got)
+		}
+	})
+
+	t.Run("tencent_short_title", func(t *testing.T) {
+		n := NormalizeText("tencent", FieldShortTitle, "好看😀,推荐")
+		if n.Result != "好看 推荐 " {
+			t.Errorf("短标题规范化错误: %q", n.Result)
+		}
+		if len(n.Changes) < 2 {
+			t.Errorf("应报告移除字符和补齐: %v", n.Changes)
+		}
+	})
+
+	t.Run("single_line_and_invisible", func(t *testing.T) {
+		n := NormalizeText("bilibili", FieldTitle, "第一行\n第二行\u200b")
+		if n.Result != "第一行 第二行" {
+			t.Errorf("单行规范化错误: %q", n.Result)
+		}
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		n := NormalizeText("douyin", FieldTitle, "正常标题")
+		if len(n.Changes) != 0 || n.Result != "正常标题" {
+			t.Errorf("无需变换的文本不应改变: %+v", n)
+		}
+	})
+}
+
+func TestNormalizeCaption(t *testing.T) {
+	caption, _ := GetTextRule("tiktok", FieldCaption)
+
+	t.Run("combined_limit", func(t *testing.T) {
+		// 标题和描述各自都在上限内，合并后超出 2200
+		title := strings.Repeat("travel ", 20) + "vlog"       // 144
+		description := strings.Repeat("sunset ", 310) + "end" // 2173
+		titleResult, descResult := NormalizeCaption("tiktok", title, description)
+		if titleResult.Result != title || len(titleResult.Changes) != 0 {
+			t.Errorf("标题不应改变: %+v", titleResult)
+		}
+		if !descResult.Truncated || len(descResult.Changes) == 0 {
+			t.Errorf("描述应被截断并报告: %+v", descResult)
+		}
+		if got := caption.Length(JoinCaption(titleResult.Result, descResult.Result)); got > caption.MaxLength || got < caption.MaxLength-10 {
+			t.Errorf("合并正文长度 %d，应接近且不超过 %d", got, caption.MaxLength)
+		}
+		if !strings.HasSuffix(descResult.Result, "sunset") {
+			t.Errorf("应在单词边界截断: %q", descResult.Result[len(descResult.Result)-20:])
+		}
+	})
+
+	t.Run("description_only", func(t *testing.T) {
+		description := strings.Repeat("a", 2200)
+		_, descResult := NormalizeCaption("tiktok", "", description)
+		if descResult.Result != description {
+			t.Errorf("没有标题时描述可使用全部长度: %d", len(descResult.Result))
+		}
+	})
+
+	t.Run("single_box_platforms", func(t *testing.T) {
+		// 快手和视频号的标题和描述同样填写在一个输入框
+		for platform, limit := range map[string]int{"kuaishou": 500, "tencent": 1000} {
+			rule, _ := GetTextRule(platform, FieldCaption)
+			title := strings.Repeat("标", 20)
+			titleResult, descResult := NormalizeCaption(platform, title, strings.Repeat("好", limit))
+			if got := rule.Length(JoinCaption(titleResult.Result, descResult.Result)); got != limit || !descResult.Truncated {
+				t.Errorf("%s: 合并正文长度 %d，应截断到 %d", platform, got, limit)
+			}
+		}
+	})
+
+	t.Run("other_platform", func(t *testing.T) {
+		description := strings.Repeat("好", 1000)
+		_, descResult := NormalizeCaption("douyin", strings.Repeat("标", 30), description)
+		if descResult.Result != description {
+			t.Errorf("分开填写的平台不应按合并长度截断: %d", len([]rune(descResult.Result)))
+		}
+	})
+
+	if got := JoinCaption("标题", ""); got != "标题" {
+		t.Errorf("JoinCaption = %q", got)
+	}
+	if got := JoinCaption("标题", "描述"); got != "标题\n\n描述" {
+		t.Errorf("JoinCaption = %q", got)
+	}
+}
+
+func TestShortTitleFor(t *testing.T) {
+	if got := ShortTitleFor("tencent", "短标题", "标题"); got != "短标题" {
+		t.Errorf("填写了短标题时应使用短标题: %q", got)
+	}
+	if got := ShortTitleFor("tencent", " ", "标题"); got != "标题" {
+		t.Errorf("未填写短标题时应使用标题: %q", got)
+	}
+	if got := ShortTitleFor("douyin", "短标题", "标题"); got != "" {
+		t.Errorf("没有短标题字段的平台应返回空: %q", got)
+	}
+}
@@ -111,6 +111,57 @@ func (rl *RateLimiter) Allow(platform string) bool {
 	return limiter.Allow()
 }
 
+// Check 检查当前是否有可用令牌（不消耗令牌）
+func (rl *RateLimiter) Check(platform string) bool {
+	rl.mutex.RLock()
+	limiter, exists := rl.limiters[platform]
+	rl.mutex.RUnlock()
+
+	if !exists {
+		return true
+	}
+
+	return limiter.Tokens() >= 1
+}
+
+// Delay 距离下一个可用令牌的等待时间
+func (rl *RateLimiter) Delay(platform string) time.Duration {
+	rl.mutex.RLock()
+	limiter, exists := rl.limiters[platform]
+	rl.mutex.RUnlock()
+
+	if !exists || limiter.Limit() <= 0 {
+		return 0
+	}
+
+	tokens := limiter.Tokens()
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / float64(limiter.Limit()) * float64(time.Second))
+}
+
+// Record 记录一次发生在指定时间的请求（消耗一个令牌）
+func (rl *RateLimiter) Record(platform string, at time.Time) {
+	rl.mutex.RLock()
+	limiter, exists := rl.limiters[platform]
+	rl.mutex.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	limiter.AllowN(at, 1)
+}
+
+// Restore 重置令牌桶并按时间顺序重放历史请求，用于重启后恢复限流状态
+func (rl *RateLimiter) Restore(platform string, history []time.Time) {
+	rl.Reset(platform)
+	for _, at := range history {
+		rl.Record(platform, at)
+	}
+}
+
 // AllowN 检查是否允许 N 个请求
 func (rl *RateLimiter) AllowN(platform string, n int) bool {
 	rl.mutex.RLock()
@@ -304,6 +355,32 @@ func (lws *LimiterWithStats) Allow(platform string) bool {
 	return allowed
 }
 
+// Check 检查当前是否有可用令牌（不消耗令牌、不记录统计），排期和预览时可随意调用
+func (lws *LimiterWithStats) Check(platform string) bool {
+	return lws.limiter.Check(platform)
+}
+
+// Delay 距离下一个可用令牌的等待时间
+func (lws *LimiterWithStats) Delay(platform string) time.Duration {
+	return lws.limiter.Delay(platform)
+}
+
+// Record 记录一次实际发布（消耗一个令牌）并计入允许统计
+func (lws *LimiterWithStats) Record(platform string, at time.Time) {
+	lws.limiter.Record(platform, at)
+	lws.stats.RecordAllowed(platform)
+}
+
+// Reject 记录一次因限流被推迟的上传
+func (lws *LimiterWithStats) Reject(platform string) {
+	lws.stats.RecordRejected(platform)
+}
+
+// Restore 按历史发布记录恢复令牌桶状态
+func (lws *LimiterWithStats) Restore(platform string, history []time.Time) {
+	lws.limiter.Restore(platform, history)
+}
+
 // Wait 等待直到允许请求
 func (lws *LimiterWithStats) Wait(ctx context.Context, platform string) error {
 	err := lws.limiter.Wait(ctx, platform)
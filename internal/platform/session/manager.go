@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"Fuploader/internal/platform/vault"
 	"Fuploader/internal/types"
 	"Fuploader/internal/utils"
 )
@@ -195,7 +196,7 @@ func (m *Manager) GetSessionStatus(accountID uint, platform string) (*Session, e
 func (m *Manager) loadFromFile(accountID uint, platform string) (*Session, error) {
 	cookiePath := m.getCookiePath(accountID, platform)
 
-	data, err := os.ReadFile(cookiePath)
+	data, err := vault.ReadFile(cookiePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("session not found")
@@ -215,18 +216,13 @@ func (m *Manager) loadFromFile(accountID uint, platform string) (*Session, error
 func (m *Manager) saveToFile(session *Session) error {
 	cookiePath := m.getCookiePath(session.AccountID, session.Platform)
 
-	// 确保目录存在
-	dir := filepath.Dir(cookiePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-
 	data, err := json.MarshalIndent(session, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(cookiePath, data, 0644)
+	// 加密写入凭据库（权限 0600）
+	return vault.WriteFile(cookiePath, data)
 }
 
 // getCookiePath 获取 Cookie 文件路径
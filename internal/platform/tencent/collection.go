@@ -23,12 +23,12 @@ func (u *Uploader) addToCollection(page playwright.Page, collection string) erro
 	}
 
 	if count > 1 {
-		if err := collectionContent.Click(); err != nil {
+		if err := human.Click(collectionContent); err != nil {
 			return fmt.Errorf("失败: 添加到合集 - 点击合集选项失败: %w", err)
 		}
 		time.Sleep(1 * time.Second)
 
-		if err := collectionElements.First().Click(); err != nil {
+		if err := human.Click(collectionElements.First()); err != nil {
 			return fmt.Errorf("失败: 添加到合集 - 选择合集失败: %w", err)
 		}
 		time.Sleep(500 * time.Millisecond)
@@ -9,8 +9,6 @@ type Config struct {
 	SubmitCheckTimeout   time.Duration
 	MaxPublishRetries    int
 	MaxUploadRetries     int
-	ShortTitleMinLength  int
-	ShortTitleMaxLength  int
 	UploadCheckInterval  time.Duration
 }
 
@@ -21,8 +19,6 @@ var defaultConfig = Config{
 	SubmitCheckTimeout:   30 * time.Second,
 	MaxPublishRetries:    3,
 	MaxUploadRetries:     3,
-	ShortTitleMinLength:  6,
-	ShortTitleMaxLength:  16,
 	UploadCheckInterval:  2 * time.Second,
 }
 
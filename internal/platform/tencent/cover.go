@@ -3,9 +3,9 @@ package tencent
 import (
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
@@ -25,7 +25,7 @@ func (u *Uploader) setCover(page playwright.Page, coverPath string) error {
 		return fmt.Errorf("失败: 设置封面 - 未找到封面设置按钮: %w", err)
 	}
 
-	if err := coverBtn.Click(); err != nil {
+	if err := human.Click(coverBtn); err != nil {
 		return fmt.Errorf("失败: 设置封面 - 点击封面设置按钮失败: %w", err)
 	}
 	time.Sleep(2 * time.Second)
@@ -45,7 +45,7 @@ func (u *Uploader) setCover(page playwright.Page, coverPath string) error {
 
 	finishBtn := page.Locator("button:has-text('完成'), button:has-text('确定')").First()
 	if count, _ := finishBtn.Count(); count > 0 {
-		if err := finishBtn.Click(); err != nil {
+		if err := human.Click(finishBtn); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 设置封面 - 点击完成按钮失败: %v", err))
 		}
 	}
@@ -55,51 +55,13 @@ func (u *Uploader) setCover(page playwright.Page, coverPath string) error {
 	return nil
 }
 
-func formatStrForShortTitle(originTitle string, minLength, maxLength int) string {
-	allowedSpecialChars := "《》" + "：+?%°"
-
-	var filteredChars []rune
-	for _, char := range originTitle {
-		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') {
-			filteredChars = append(filteredChars, char)
-		} else if char >= 0x4e00 && char <= 0x9fff {
-			filteredChars = append(filteredChars, char)
-		} else if char == ',' {
-			filteredChars = append(filteredChars, ' ')
-		} else {
-			allowed := false
-			for _, allowedChar := range allowedSpecialChars {
-				if char == allowedChar {
-					allowed = true
-					break
-				}
-			}
-			if allowed {
-				filteredChars = append(filteredChars, char)
-			}
-		}
-	}
-	formattedString := string(filteredChars)
-
-	runeLen := len([]rune(formattedString))
-	if runeLen > maxLength {
-		runes := []rune(formattedString)
-		formattedString = string(runes[:maxLength])
-	} else if runeLen < minLength {
-		spacesNeeded := minLength - runeLen
-		formattedString += strings.Repeat(" ", spacesNeeded)
-	}
-
-	return formattedString
-}
-
 func (u *Uploader) setShortTitle(page playwright.Page, title string) error {
-	shortTitle := formatStrForShortTitle(title, u.config.ShortTitleMinLength, u.config.ShortTitleMaxLength)
+	shortTitle := platformutils.ApplyTextRule(u.platform, platformutils.FieldShortTitle, title)
 
 	shortTitleInput := page.Locator("input[placeholder*='字数建议6-16个字符']").First()
 
 	if count, _ := shortTitleInput.Count(); count > 0 {
-		if err := shortTitleInput.Fill(shortTitle); err != nil {
+		if err := human.Fill(shortTitleInput, shortTitle); err != nil {
 			return fmt.Errorf("失败: 设置短标题 - 填写短标题失败: %w", err)
 		}
 	}
@@ -24,7 +24,7 @@ func (u *Uploader) setOriginal(page playwright.Page, category string) error {
 		}
 
 		declareBtn := page.GetByRole("button", playwright.PageGetByRoleOptions{Name: "声明原创"}).First()
-		if err := declareBtn.Click(); err != nil {
+		if err := human.Click(declareBtn); err != nil {
 			return fmt.Errorf("失败: 设置原创声明 - 点击声明原创按钮失败: %w", err)
 		}
 	}
@@ -35,27 +35,27 @@ func (u *Uploader) setOriginal(page playwright.Page, category string) error {
 		isDisabled, _ := originalCheckboxNew.IsDisabled()
 
 		if !isDisabled {
-			if err := originalCheckboxNew.Click(); err != nil {
+			if err := human.Click(originalCheckboxNew); err != nil {
 				return fmt.Errorf("失败: 设置原创声明 - 点击新版原创复选框失败: %w", err)
 			}
 
 			checkedWrapper := page.Locator("div.declare-original-dialog label.ant-checkbox-wrapper.ant-checkbox-wrapper-checked:visible").First()
 			if count, _ := checkedWrapper.Count(); count == 0 {
 				visibleCheckbox := page.Locator("div.declare-original-dialog input.ant-checkbox-input:visible").First()
-				visibleCheckbox.Click()
+				human.Click(visibleCheckbox)
 			}
 		}
 
 		originalTypeForm := page.Locator("div.original-type-form > div.form-label:has-text('原创类型'):visible").First()
 		if count, _ := originalTypeForm.Count(); count > 0 {
 			dropdown := page.Locator("div.form-content:visible").First()
-			if err := dropdown.Click(); err != nil {
+			if err := human.Click(dropdown); err != nil {
 				return fmt.Errorf("失败: 设置原创声明 - 点击原创类型下拉菜单失败: %w", err)
 			}
 			time.Sleep(1 * time.Second)
 
 			typeOption := page.Locator(fmt.Sprintf("div.form-content:visible ul.weui-desktop-dropdown__list li.weui-desktop-dropdown__list-ele:has-text('%s')", category)).First()
-			if err := typeOption.Click(); err != nil {
+			if err := human.Click(typeOption); err != nil {
 				return fmt.Errorf("失败: 设置原创声明 - 选择原创类型失败: %w", err)
 			}
 			time.Sleep(1 * time.Second)
@@ -63,7 +63,7 @@ func (u *Uploader) setOriginal(page playwright.Page, category string) error {
 
 		declareBtnNew := page.Locator("button:has-text('声明原创'):visible").First()
 		if count, _ := declareBtnNew.Count(); count > 0 {
-			if err := declareBtnNew.Click(); err != nil {
+			if err := human.Click(declareBtnNew); err != nil {
 				return fmt.Errorf("失败: 设置原创声明 - 点击新版声明原创按钮失败: %w", err)
 			}
 		}
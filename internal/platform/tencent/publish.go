@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
@@ -21,22 +22,22 @@ func (u *Uploader) fillTitleAndDescription(page playwright.Page, title, descript
 		return fmt.Errorf("未找到编辑器: %w", err)
 	}
 
-	if err := editor.Click(); err != nil {
+	if err := human.Click(editor); err != nil {
 		return fmt.Errorf("点击编辑器失败: %w", err)
 	}
 	time.Sleep(500 * time.Millisecond)
 
 	page.Keyboard().Press("Control+KeyA")
 	page.Keyboard().Press("Delete")
-	page.Keyboard().Type(title)
-	page.Keyboard().Press("Enter")
 
-	utils.InfoWithPlatform(u.platform, fmt.Sprintf("标题已填写: %s", title))
+	// 标题和描述填写在同一个输入框，合并后的正文同样受长度上限约束
+	title, description = platformutils.ApplyCaptionRule(u.platform, title, description)
+	if err := human.Type(page, platformutils.JoinCaption(title, description)); err != nil {
+		return fmt.Errorf("填写正文失败: %w", err)
+	}
 
+	utils.InfoWithPlatform(u.platform, fmt.Sprintf("标题已填写: %s", title))
 	if description != "" {
-		utils.InfoWithPlatform(u.platform, "填写描述...")
-		page.Keyboard().Press("Enter")
-		page.Keyboard().Type(description)
 		utils.InfoWithPlatform(u.platform, "描述已填写")
 	}
 
@@ -54,7 +55,7 @@ func (u *Uploader) addTags(page playwright.Page, tags []string) error {
 			continue
 		}
 
-		page.Keyboard().Type("#" + cleanTag)
+		human.Type(page, "#"+cleanTag)
 		page.Keyboard().Press("Space")
 		time.Sleep(500 * time.Millisecond)
 	}
@@ -73,7 +74,7 @@ func (u *Uploader) publish(page playwright.Page, browserCtx *browser.PooledConte
 		return fmt.Errorf("失败: 准备发布 - 未找到发表按钮: %w", err)
 	}
 
-	if err := publishBtn.Click(); err != nil {
+	if err := human.Click(publishBtn); err != nil {
 		return fmt.Errorf("失败: 准备发布 - 点击发表按钮失败: %w", err)
 	}
 
@@ -114,7 +115,7 @@ func (u *Uploader) saveDraft(page playwright.Page, browserCtx *browser.PooledCon
 	}
 
 	if count, _ := draftBtn.Count(); count > 0 {
-		if err := draftBtn.Click(); err != nil {
+		if err := human.Click(draftBtn); err != nil {
 			return fmt.Errorf("失败: 保存草稿 - 点击保存草稿按钮失败: %w", err)
 		}
 	}
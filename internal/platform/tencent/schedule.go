@@ -23,13 +23,13 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		return fmt.Errorf("失败: 设置定时发布 - 未找到定时发表选项: %w", err)
 	}
 
-	if err := scheduleLabel.Click(); err != nil {
+	if err := human.Click(scheduleLabel); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 点击定时发表失败: %w", err)
 	}
 	time.Sleep(1 * time.Second)
 
 	timeInput := page.Locator("input.weui-desktop-form-input__input[placeholder='请选择发表时间']").First()
-	if err := timeInput.Click(); err != nil {
+	if err := human.Click(timeInput); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 点击时间输入框失败: %w", err)
 	}
 	time.Sleep(1 * time.Second)
@@ -43,7 +43,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 
 	for pageMonth != strMonth {
 		nextMonthBtn := page.Locator("button.weui-desktop-btn__icon__right").First()
-		if err := nextMonthBtn.Click(); err != nil {
+		if err := human.Click(nextMonthBtn); err != nil {
 			return fmt.Errorf("失败: 设置定时发布 - 点击下个月按钮失败: %w", err)
 		}
 		time.Sleep(500 * time.Millisecond)
@@ -54,13 +54,13 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		}
 	}
 
-	elements, err := page.QuerySelectorAll("table.weui-desktop-picker__table a")
+	elements, err := page.Locator("table.weui-desktop-picker__table a").All()
 	if err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 获取日期元素失败: %w", err)
 	}
 
 	for _, element := range elements {
-		className, err := element.Evaluate("el => el.className")
+		className, err := element.Evaluate("el => el.className", nil)
 		if err != nil {
 			continue
 		}
@@ -76,7 +76,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		}
 
 		if strings.TrimSpace(text) == fmt.Sprintf("%d", targetTime.Day()) {
-			if err := element.Click(); err != nil {
+			if err := human.Click(element); err != nil {
 				return fmt.Errorf("失败: 设置定时发布 - 点击日期失败: %w", err)
 			}
 			break
@@ -84,7 +84,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	}
 
 	hourInput := page.Locator("input.weui-desktop-form-input__input[placeholder='请选择时间']").First()
-	if err := hourInput.Click(); err != nil {
+	if err := human.Click(hourInput); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 点击时间选择框失败: %w", err)
 	}
 	time.Sleep(500 * time.Millisecond)
@@ -92,7 +92,7 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 	page.Keyboard().Press("Control+KeyA")
 	page.Keyboard().Type(fmt.Sprintf("%d", targetTime.Hour()))
 
-	page.Locator("[contenteditable][data-placeholder='添加描述']").Click()
+	human.Click(page.Locator("[contenteditable][data-placeholder='添加描述']"))
 
 	time.Sleep(1 * time.Second)
 	return nil
@@ -109,12 +109,12 @@ func (u *Uploader) waitForUploadComplete(ctx context.Context, page playwright.Pa
 
 func (u *Uploader) handleUploadError(page playwright.Page, videoPath string) error {
 	deleteBtn := page.Locator("div.media-status-content div.tag-inner:has-text('删除')").First()
-	if err := deleteBtn.Click(); err != nil {
+	if err := human.Click(deleteBtn); err != nil {
 		return fmt.Errorf("失败: 处理上传错误 - 点击删除按钮失败: %w", err)
 	}
 
 	confirmBtn := page.GetByRole("button", playwright.PageGetByRoleOptions{Name: "删除", Exact: playwright.Bool(true)}).First()
-	if err := confirmBtn.Click(); err != nil {
+	if err := human.Click(confirmBtn); err != nil {
 		return fmt.Errorf("失败: 处理上传错误 - 点击确认删除失败: %w", err)
 	}
 
@@ -9,12 +9,16 @@ import (
 
 	"Fuploader/internal/config"
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/types"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
 )
 
+// human 上传页面的点击和输入，按浏览器池配置的 tencent 人类交互预设执行
+var human = browser.HumanFor("tencent")
+
 func debugLog(format string, args ...interface{}) {
 	if config.Config != nil && config.Config.DebugMode {
 		utils.InfoWithPlatform("tencent", fmt.Sprintf("[调试] "+format, args...))
@@ -50,12 +54,19 @@ func NewUploaderWithPool(cookiePath string, pool *browser.Pool) *Uploader {
 }
 
 func NewUploaderWithAccount(accountID uint) *Uploader {
-	cookiePath := config.GetCookiePath("tencent", int(accountID))
+	return NewUploaderWithAccountPool(accountID, "", browser.GetDefaultPool())
+}
+
+// NewUploaderWithAccountPool 使用指定浏览器池创建账号上传器，cookiePath 为空时使用账号的默认 Cookie 路径
+func NewUploaderWithAccountPool(accountID uint, cookiePath string, pool *browser.Pool) *Uploader {
+	if cookiePath == "" {
+		cookiePath = config.GetCookiePath("tencent", int(accountID))
+	}
 	u := &Uploader{
 		accountID:   accountID,
 		cookiePath:  cookiePath,
 		platform:    "tencent",
-		browserPool: browser.GetDefaultPool(),
+		browserPool: pool,
 		config:      DefaultConfig(),
 	}
 	debugLog("创建上传器 - 地址: %p, accountID: %d, cookiePath: '%s'", u, accountID, cookiePath)
@@ -150,7 +161,7 @@ func (u *Uploader) Upload(ctx context.Context, task *types.VideoTask) error {
 		}
 	}
 
-	if err := u.setShortTitle(page, task.Title); err != nil {
+	if err := u.setShortTitle(page, platformutils.ShortTitleFor(u.platform, task.ShortTitle, task.Title)); err != nil {
 		utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 设置短标题 - %v", err))
 	}
 
@@ -9,7 +9,6 @@ type Config struct {
 	SubmitCheckTimeout  time.Duration
 	MaxPublishRetries   int
 	MaxUploadRetries    int
-	TitleMaxLength      int
 	UploadCheckInterval time.Duration
 }
 
@@ -20,7 +19,6 @@ var defaultConfig = Config{
 	SubmitCheckTimeout:  120 * time.Second,
 	MaxPublishRetries:   60,
 	MaxUploadRetries:    60,
-	TitleMaxLength:      150,
 	UploadCheckInterval: 2 * time.Second,
 }
 
@@ -30,7 +30,7 @@ func (u *Uploader) publish(ctx context.Context, page playwright.Page, locatorBas
 
 		publishBtn := locatorBase.Locator("div.btn-post")
 		if count, _ := publishBtn.Count(); count > 0 {
-			publishBtn.Click()
+			human.Click(publishBtn)
 		}
 
 		time.Sleep(3 * time.Second)
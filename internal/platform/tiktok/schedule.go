@@ -31,19 +31,19 @@ func (u *Uploader) setScheduleTime(locatorBase playwright.Locator, scheduleTime
 		return fmt.Errorf("未找到Schedule按钮: %w", err)
 	}
 
-	scheduleBtn.Click()
+	human.Click(scheduleBtn)
 	time.Sleep(1 * time.Second)
 
 	allowBtn := u.findFirstVisibleLocator(locatorBase, locators.AllowButton)
 	if allowBtn != nil {
-		if err := allowBtn.Click(); err != nil {
+		if err := human.Click(allowBtn); err != nil {
 			utils.WarnWithPlatform(u.platform, fmt.Sprintf("点击Allow按钮失败: %v", err))
 		}
 	}
 
 	scheduledPicker := locatorBase.Locator(locators.DatePicker)
 	calendarBtn := scheduledPicker.Nth(1)
-	calendarBtn.Click()
+	human.Click(calendarBtn)
 	time.Sleep(500 * time.Millisecond)
 
 	calendarWrapper := locatorBase.Locator(locators.CalendarWrapper)
@@ -65,32 +65,32 @@ func (u *Uploader) setScheduleTime(locatorBase playwright.Locator, scheduleTime
 	for i := 0; i < count; i++ {
 		dayText, _ := validDays.Nth(i).TextContent()
 		if strings.TrimSpace(dayText) == targetDay {
-			validDays.Nth(i).Click()
+			human.Click(validDays.Nth(i))
 			break
 		}
 	}
 
 	timePicker := locatorBase.Locator(locators.TimePicker)
-	timePicker.Nth(0).Click()
+	human.Click(timePicker.Nth(0))
 	time.Sleep(500 * time.Millisecond)
 
 	hourStr := publishDate.Format("15")
 	hourSelector := fmt.Sprintf("%s:has-text('%s')", locators.HourPicker, hourStr)
 	hourElement := locatorBase.Locator(hourSelector)
-	hourElement.Click()
+	human.Click(hourElement)
 	time.Sleep(500 * time.Millisecond)
 
-	timePicker.Nth(0).Click()
+	human.Click(timePicker.Nth(0))
 	time.Sleep(500 * time.Millisecond)
 
 	correctMinute := int(publishDate.Minute()/5) * 5
 	minuteStr := fmt.Sprintf("%02d", correctMinute)
 	minuteSelector := fmt.Sprintf("%s:has-text('%s')", locators.MinutePicker, minuteStr)
 	minuteElement := locatorBase.Locator(minuteSelector)
-	minuteElement.Click()
+	human.Click(minuteElement)
 
 	uploadTitle := locatorBase.Locator("h1:has-text('Upload video')")
-	uploadTitle.Click()
+	human.Click(uploadTitle)
 
 	utils.InfoWithPlatform(u.platform, "定时发布设置完成")
 	return nil
@@ -111,7 +111,7 @@ func (u *Uploader) switchMonth(calendarWrapper playwright.Locator, currentMonth,
 			clickCount = 12 - clickCount
 		}
 		for i := 0; i < clickCount && i < int(arrowCount); i++ {
-			arrows.Nth(int(arrowCount) - 1).Click()
+			human.Click(arrows.Nth(int(arrowCount) - 1))
 			time.Sleep(300 * time.Millisecond)
 		}
 	} else {
@@ -120,7 +120,7 @@ func (u *Uploader) switchMonth(calendarWrapper playwright.Locator, currentMonth,
 			clickCount = 12 - clickCount
 		}
 		for i := 0; i < clickCount; i++ {
-			arrows.Nth(0).Click()
+			human.Click(arrows.Nth(0))
 			time.Sleep(300 * time.Millisecond)
 		}
 	}
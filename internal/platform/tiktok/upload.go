@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
@@ -34,7 +35,7 @@ func (u *Uploader) uploadVideo(ctx context.Context, page playwright.Page, browse
 	}
 
 	fileChooser, err := page.ExpectFileChooser(func() error {
-		return uploadButton.Click()
+		return human.Click(uploadButton)
 	})
 	if err != nil {
 		return fmt.Errorf("等待文件选择器失败: %w", err)
@@ -112,7 +113,7 @@ func (u *Uploader) fillTitleAndDescription(locatorBase playwright.Locator, title
 		return fmt.Errorf("未找到编辑器: %w", err)
 	}
 
-	if err := editorLocator.Click(); err != nil {
+	if err := human.Click(editorLocator); err != nil {
 		return fmt.Errorf("点击编辑器失败: %w", err)
 	}
 	time.Sleep(500 * time.Millisecond)
@@ -128,12 +129,13 @@ func (u *Uploader) fillTitleAndDescription(locatorBase playwright.Locator, title
 	page.Keyboard().Press("End")
 	time.Sleep(500 * time.Millisecond)
 
-	content := title
-	if description != "" {
-		content += "\n\n" + description
-	}
+	// 标题和描述填写在同一个输入框，合并后的正文同样受长度上限约束
+	title, description = platformutils.ApplyCaptionRule(u.platform, title, description)
+	content := platformutils.JoinCaption(title, description)
 
-	page.Keyboard().Type(content)
+	if err := human.Type(page, content); err != nil {
+		return fmt.Errorf("填写正文失败: %w", err)
+	}
 	time.Sleep(500 * time.Millisecond)
 	page.Keyboard().Press("End")
 	page.Keyboard().Press("Enter")
@@ -164,7 +166,7 @@ func (u *Uploader) addTags(locatorBase playwright.Locator, tags []string) error
 
 		page.Keyboard().Press("End")
 		time.Sleep(500 * time.Millisecond)
-		page.Keyboard().Type("#" + cleanTag + " ")
+		human.Type(page, "#"+cleanTag+" ")
 		page.Keyboard().Press("Space")
 		time.Sleep(500 * time.Millisecond)
 		page.Keyboard().Press("Backspace")
@@ -194,20 +196,20 @@ func (u *Uploader) setCover(page playwright.Page, locatorBase playwright.Locator
 		return fmt.Errorf("未找到封面区域: %w", err)
 	}
 
-	if err := coverContainer.Click(); err != nil {
+	if err := human.Click(coverContainer); err != nil {
 		return fmt.Errorf("点击封面区域失败: %w", err)
 	}
 	time.Sleep(2 * time.Second)
 
 	uploadCoverBtn := u.findFirstVisibleLocator(locatorBase, locators.UploadCover)
 	if uploadCoverBtn != nil {
-		uploadCoverBtn.Click()
+		human.Click(uploadCoverBtn)
 		time.Sleep(1 * time.Second)
 	}
 
 	fileChooser, err := page.ExpectFileChooser(func() error {
 		uploadBtn := locatorBase.Locator("button:has-text('Upload'):visible").First()
-		return uploadBtn.Click()
+		return human.Click(uploadBtn)
 	})
 	if err != nil {
 		return fmt.Errorf("等待文件选择器失败: %w", err)
@@ -221,7 +223,7 @@ func (u *Uploader) setCover(page playwright.Page, locatorBase playwright.Locator
 
 	confirmBtn := u.findFirstVisibleLocator(locatorBase, locators.ConfirmCover)
 	if confirmBtn != nil {
-		confirmBtn.Click()
+		human.Click(confirmBtn)
 		time.Sleep(1 * time.Second)
 	}
 
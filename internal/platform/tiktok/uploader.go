@@ -14,6 +14,9 @@ import (
 	"github.com/playwright-community/playwright-go"
 )
 
+// human 上传页面的点击和输入，按浏览器池配置的 tiktok 人类交互预设执行
+var human = browser.HumanFor("tiktok")
+
 func debugLog(format string, args ...interface{}) {
 	if config.Config != nil && config.Config.DebugMode {
 		utils.InfoWithPlatform("tiktok", fmt.Sprintf("[调试] "+format, args...))
@@ -53,6 +56,20 @@ func NewUploaderWithAccount(accountID uint) *Uploader {
 	return u
 }
 
+func NewUploaderWithPool(accountID uint, cookiePath string, pool *browser.Pool) *Uploader {
+	if cookiePath == "" {
+		cookiePath = config.GetCookiePath("tiktok", int(accountID))
+	}
+	u := &Uploader{
+		accountID:   accountID,
+		cookiePath:  cookiePath,
+		platform:    "tiktok",
+		browserPool: pool,
+	}
+	debugLog("创建上传器 - 地址: %p, accountID: %d, cookiePath: '%s'", u, accountID, cookiePath)
+	return u
+}
+
 func (u *Uploader) Platform() string {
 	return u.platform
 }
@@ -141,12 +158,14 @@ func (u *Uploader) Upload(ctx context.Context, task *types.VideoTask) error {
 }
 
 func (u *Uploader) getContextOptions() *browser.ContextOptions {
+	// 无法关联账号时使用平台默认地区；账号上下文由浏览器池换成账号固定指纹，时区与 platformutils.PageLocation 一致
+	region := config.PlatformBrowserRegion(config.PlatformTiktok)
 	return &browser.ContextOptions{
 		UserAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:120.0) Gecko/20100101 Firefox/120.0",
 		Viewport:    &playwright.Size{Width: 1920, Height: 1080},
-		Locale:      "en-GB",
-		TimezoneId:  "Europe/London",
-		Geolocation: &playwright.Geolocation{Latitude: 51.5074, Longitude: -0.1278},
+		Locale:      region.Locale,
+		TimezoneId:  region.TimezoneID,
+		Geolocation: &playwright.Geolocation{Latitude: region.Latitude, Longitude: region.Longitude},
 		ExtraHeaders: map[string]string{
 			"Accept-Language": "en-GB,en;q=0.9",
 		},
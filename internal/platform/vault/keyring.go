@@ -0,0 +1,129 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// 系统钥匙串中的条目
+const (
+	keyringService = "Fuploader"
+	keyringAccount = "cookie-vault"
+)
+
+var (
+	errKeyringUnavailable = errors.New("系统钥匙串不可用")
+	errKeyNotFound        = errors.New("系统钥匙串中没有凭据库密钥")
+)
+
+// keyringGet 从系统钥匙串读取密钥：
+// macOS 使用钥匙串（security），Linux 使用 Secret Service（secret-tool），Windows 使用 DPAPI 保护的密钥文件
+func keyringGet(dir string) ([]byte, error) {
+	var out []byte
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		out, err = runKeyringCommand(nil, "security", "find-generic-password", "-s", keyringService, "-a", keyringAccount, "-w")
+	case "linux":
+		out, err = runKeyringCommand(nil, "secret-tool", "lookup", "service", keyringService, "account", keyringAccount)
+	case "windows":
+		blob, readErr := os.ReadFile(dpapiKeyPath(dir))
+		if os.IsNotExist(readErr) {
+			return nil, errKeyNotFound
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+		out, err = runKeyringCommand(blob, "powershell", "-NoProfile", "-NonInteractive", "-Command",
+			"Add-Type -AssemblyName System.Security; "+
+				"$b = [Convert]::FromBase64String([Console]::In.ReadToEnd().Trim()); "+
+				"[Convert]::ToBase64String([Security.Cryptography.ProtectedData]::Unprotect($b, $null, 'CurrentUser'))")
+	default:
+		return nil, errKeyringUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value := strings.TrimSpace(string(out))
+	if value == "" {
+		return nil, errKeyNotFound
+	}
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// keyringSet 将密钥写入系统钥匙串
+func keyringSet(dir string, key []byte) error {
+	value := base64.StdEncoding.EncodeToString(key)
+	switch runtime.GOOS {
+	case "darwin":
+		// 通过 security 的交互模式从标准输入传入命令，避免密钥出现在命令行参数中被 ps 看到
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", keyringService, keyringAccount, value)
+		if _, err := runKeyringCommand([]byte(command), "security", "-i"); err != nil {
+			return err
+		}
+		// 交互模式下子命令失败时 security 仍可能正常退出，读回确认已写入
+		stored, err := keyringGet(dir)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(stored, key) {
+			return fmt.Errorf("security: 写入钥匙串的密钥校验失败")
+		}
+		return nil
+	case "linux":
+		_, err := runKeyringCommand([]byte(value), "secret-tool", "store", "--label=Fuploader cookie vault",
+			"service", keyringService, "account", keyringAccount)
+		return err
+	case "windows":
+		out, err := runKeyringCommand([]byte(value), "powershell", "-NoProfile", "-NonInteractive", "-Command",
+			"Add-Type -AssemblyName System.Security; "+
+				"$b = [Convert]::FromBase64String([Console]::In.ReadToEnd().Trim()); "+
+				"[Convert]::ToBase64String([Security.Cryptography.ProtectedData]::Protect($b, $null, 'CurrentUser'))")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(dpapiKeyPath(dir), bytes.TrimSpace(out))
+	default:
+		return errKeyringUnavailable
+	}
+}
+
+// runKeyringCommand 执行钥匙串命令，命令不存在时返回 errKeyringUnavailable
+func runKeyringCommand(stdin []byte, name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, errKeyringUnavailable
+	}
+	cmd := exec.Command(name, args...)
+	hideWindow(cmd)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// 条目不存在时各平台命令都以非零状态退出且没有输出
+		if _, ok := err.(*exec.ExitError); ok && len(bytes.TrimSpace(out)) == 0 {
+			msg := strings.TrimSpace(stderr.String())
+			if msg == "" || strings.Contains(msg, "could not be found") {
+				return nil, errKeyNotFound
+			}
+			return nil, fmt.Errorf("%s: %s", name, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// dpapiKeyPath Windows 下 DPAPI 保护的密钥文件
+func dpapiKeyPath(dir string) string {
+	return filepath.Join(dir, "vault.key.dpapi")
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package vault
+
+import "os/exec"
+
+// hideWindow 仅 Windows 需要隐藏控制台窗口
+func hideWindow(cmd *exec.Cmd) {}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package vault
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideWindow 不为 powershell 弹出控制台窗口
+func hideWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+}
@@ -0,0 +1,389 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"Fuploader/internal/utils"
+)
+
+// 加密文件格式：magic + 12 字节 nonce + AES-256-GCM 密文
+const magic = "FUVAULT1"
+
+// 密钥来源
+const (
+	SourceKeyring    = "keyring"    // 系统钥匙串（Windows 为 DPAPI）
+	SourcePassphrase = "passphrase" // 口令派生（PBKDF2-SHA256）
+	SourceFile       = "file"       // 本地密钥文件（钥匙串不可用时的兜底）
+)
+
+// PassphraseEnv 设置后使用口令派生密钥
+const PassphraseEnv = "FUPLOADER_VAULT_PASSPHRASE"
+
+const (
+	keySize          = 32
+	pbkdf2Iterations = 600000
+	checkPlaintext   = "fuploader-vault"
+)
+
+// ErrLocked 凭据库未解锁（口令模式下尚未提供口令）
+var ErrLocked = errors.New("凭据库未解锁，请输入口令")
+
+// meta 凭据库元数据，记录密钥来源和用于校验密钥的密文
+type meta struct {
+	Source     string `json:"source"`
+	Salt       []byte `json:"salt,omitempty"`
+	Iterations int    `json:"iterations,omitempty"`
+	Check      []byte `json:"check"`
+}
+
+var (
+	mu      sync.RWMutex
+	key     []byte
+	source  string
+	metaDir string
+	initErr error
+)
+
+// Init 初始化凭据库，dir 为存放元数据的目录
+// 首次初始化时按 口令（设置了 FUPLOADER_VAULT_PASSPHRASE）> 系统钥匙串 > 本地密钥文件 的顺序选择密钥来源，之后固定使用该来源
+func Init(dir string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	metaDir = dir
+	initErr = load(os.Getenv(PassphraseEnv))
+	return initErr
+}
+
+// Unlock 使用口令解锁凭据库（口令模式）
+func Unlock(passphrase string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if metaDir == "" {
+		return fmt.Errorf("凭据库未初始化")
+	}
+	initErr = load(passphrase)
+	return initErr
+}
+
+// Status 当前密钥来源（未解锁时为空）及初始化/解锁错误
+func Status() (string, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return source, initErr
+}
+
+// load 加载或创建密钥，调用方持有写锁
+func load(passphrase string) error {
+	key, source = nil, ""
+	if err := os.MkdirAll(metaDir, 0700); err != nil {
+		return fmt.Errorf("create vault directory failed: %w", err)
+	}
+
+	m, err := readMeta()
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return create(passphrase)
+	}
+
+	var k []byte
+	switch m.Source {
+	case SourcePassphrase:
+		if passphrase == "" {
+			return ErrLocked
+		}
+		if k, err = deriveKey(passphrase, m.Salt, m.Iterations); err != nil {
+			return err
+		}
+	case SourceKeyring:
+		if k, err = keyringGet(metaDir); err != nil {
+			return fmt.Errorf("读取系统钥匙串中的凭据库密钥失败: %w", err)
+		}
+	case SourceFile:
+		if k, err = os.ReadFile(keyFilePath()); err != nil {
+			return fmt.Errorf("读取凭据库密钥文件失败: %w", err)
+		}
+	default:
+		return fmt.Errorf("未知的凭据库密钥来源: %s", m.Source)
+	}
+
+	if plain, err := decrypt(k, m.Check); err != nil || string(plain) != checkPlaintext {
+		if m.Source == SourcePassphrase {
+			return fmt.Errorf("凭据库口令错误")
+		}
+		return fmt.Errorf("凭据库密钥校验失败")
+	}
+	key, source = k, m.Source
+	return nil
+}
+
+// create 首次初始化：生成密钥并写入元数据
+func create(passphrase string) error {
+	m := &meta{}
+	var k []byte
+	var err error
+
+	switch {
+	case passphrase != "":
+		m.Source = SourcePassphrase
+		m.Iterations = pbkdf2Iterations
+		m.Salt = make([]byte, 16)
+		if _, err := rand.Read(m.Salt); err != nil {
+			return err
+		}
+		if k, err = deriveKey(passphrase, m.Salt, m.Iterations); err != nil {
+			return err
+		}
+	default:
+		k = make([]byte, keySize)
+		if _, err := rand.Read(k); err != nil {
+			return err
+		}
+		m.Source = SourceKeyring
+		if err := keyringSet(metaDir, k); err != nil {
+			utils.Warn(fmt.Sprintf("[-] 系统钥匙串不可用（%v），凭据库密钥将保存在本地文件，建议设置 %s 使用口令保护", err, PassphraseEnv))
+			m.Source = SourceFile
+			if err := writeFileAtomic(keyFilePath(), k); err != nil {
+				return fmt.Errorf("write vault key failed: %w", err)
+			}
+		}
+	}
+
+	if m.Check, err = encrypt(k, []byte(checkPlaintext)); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(metaPath(), data); err != nil {
+		return fmt.Errorf("write vault meta failed: %w", err)
+	}
+	key, source = k, m.Source
+	utils.Info(fmt.Sprintf("[+] 凭据库已初始化，密钥来源: %s", m.Source))
+	return nil
+}
+
+func readMeta() (*meta, error) {
+	data, err := os.ReadFile(metaPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read vault meta failed: %w", err)
+	}
+	var m meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse vault meta failed: %w", err)
+	}
+	return &m, nil
+}
+
+func metaPath() string    { return filepath.Join(metaDir, "vault.json") }
+func keyFilePath() string { return filepath.Join(metaDir, "vault.key") }
+
+func deriveKey(passphrase string, salt []byte, iterations int) ([]byte, error) {
+	if iterations <= 0 {
+		iterations = pbkdf2Iterations
+	}
+	return pbkdf2.Key(sha256.New, passphrase, salt, iterations, keySize)
+}
+
+// currentKey 当前密钥，未初始化时返回错误
+func currentKey() ([]byte, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if key == nil {
+		if initErr != nil {
+			return nil, initErr
+		}
+		return nil, fmt.Errorf("凭据库未初始化")
+	}
+	return key, nil
+}
+
+// IsEncrypted 数据是否为凭据库加密格式
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+// ReadFile 读取凭据文件：加密文件自动解密，旧版本的明文文件原样返回
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	k, err := currentKey()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := decrypt(k, data)
+	if err != nil {
+		return nil, fmt.Errorf("解密凭据文件 %s 失败: %w", filepath.Base(path), err)
+	}
+	return plain, nil
+}
+
+// WriteFile 加密写入凭据文件（权限 0600）
+func WriteFile(path string, data []byte) error {
+	k, err := currentKey()
+	if err != nil {
+		return err
+	}
+	sealed, err := encrypt(k, data)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("create directory failed: %w", err)
+		}
+	}
+	return writeFileAtomic(path, sealed)
+}
+
+// EncryptString 加密短文本凭据（如代理密码），返回可保存到数据库的 base64 文本，空文本原样返回
+func EncryptString(plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+	k, err := currentKey()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := encrypt(k, []byte(plain))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// IsEncryptedString 文本是否为 EncryptString 的结果
+func IsEncryptedString(value string) bool {
+	data, err := base64.StdEncoding.DecodeString(value)
+	return err == nil && IsEncrypted(data)
+}
+
+// DecryptString 解密 EncryptString 的结果，旧版本保存的明文原样返回
+func DecryptString(value string) (string, error) {
+	if !IsEncryptedString(value) {
+		return value, nil
+	}
+	data, _ := base64.StdEncoding.DecodeString(value)
+	k, err := currentKey()
+	if err != nil {
+		return "", err
+	}
+	plain, err := decrypt(k, data)
+	if err != nil {
+		return "", fmt.Errorf("解密凭据失败: %w", err)
+	}
+	return string(plain), nil
+}
+
+// MigrateDir 将目录下的明文 JSON 凭据文件加密（一次性迁移），返回迁移的文件数
+func MigrateDir(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return count, err
+		}
+		if IsEncrypted(data) || !json.Valid(data) {
+			continue
+		}
+		if err := WriteFile(path, data); err != nil {
+			return count, fmt.Errorf("加密 %s 失败: %w", entry.Name(), err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+func encrypt(k, plain []byte) ([]byte, error) {
+	gcm, err := newGCM(k)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(magic), nonce...)
+	return gcm.Seal(out, nonce, plain, []byte(magic)), nil
+}
+
+func decrypt(k, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("not a vault file")
+	}
+	gcm, err := newGCM(k)
+	if err != nil {
+		return nil, err
+	}
+	body := data[len(magic):]
+	if len(body) < gcm.NonceSize() {
+		return nil, fmt.Errorf("vault file truncated")
+	}
+	return gcm.Open(nil, body[:gcm.NonceSize()], body[gcm.NonceSize():], []byte(magic))
+}
+
+func newGCM(k []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic 先写临时文件再替换，避免写入中断导致凭据文件损坏
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil && !errors.Is(err, os.ErrPermission) {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,153 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useKey 在测试中直接设置密钥，绕过 Init（首次初始化会访问系统钥匙串）
+func useKey(t *testing.T, k []byte) {
+	t.Helper()
+	mu.Lock()
+	key, source, initErr = k, SourceFile, nil
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		key, source, metaDir, initErr = nil, "", "", nil
+		mu.Unlock()
+	})
+}
+
+func TestReadWriteFile(t *testing.T) {
+	useKey(t, bytes.Repeat([]byte{7}, keySize))
+	dir := t.TempDir()
+	path := filepath.Join(dir, "account.json")
+	plain := []byte(`{"cookies":[{"name":"SESSDATA","value":"secret"}]}`)
+
+	if err := WriteFile(path, plain); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(path)
+	if !IsEncrypted(raw) || bytes.Contains(raw, []byte("secret")) {
+		t.Fatalf("文件未加密: %q", raw)
+	}
+	got, err := ReadFile(path)
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("got %q, %v", got, err)
+	}
+
+	// 密钥不匹配时解密失败
+	useKey(t, bytes.Repeat([]byte{8}, keySize))
+	if _, err := ReadFile(path); err == nil {
+		t.Error("expected decrypt error with wrong key")
+	}
+}
+
+func TestReadFilePlaintextPassthrough(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "legacy.json")
+	plain := []byte(`{"cookies":[]}`)
+	os.WriteFile(path, plain, 0644)
+
+	// 明文文件无需密钥即可读取
+	got, err := ReadFile(path)
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if err := WriteFile(path, plain); err == nil {
+		t.Error("未初始化时写入应失败")
+	}
+}
+
+func TestMigrateDir(t *testing.T) {
+	useKey(t, bytes.Repeat([]byte{7}, keySize))
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"a":1}`), 0644)
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{`), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`hello`), 0644)
+	if err := WriteFile(filepath.Join(dir, "b.json"), []byte(`{"b":2}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := MigrateDir(dir)
+	if err != nil || n != 1 {
+		t.Fatalf("migrated %d, %v; want 1", n, err)
+	}
+	for name, encrypted := range map[string]bool{"a.json": true, "b.json": true, "broken.json": false, "notes.txt": false} {
+		raw, _ := os.ReadFile(filepath.Join(dir, name))
+		if IsEncrypted(raw) != encrypted {
+			t.Errorf("%s: encrypted=%v, want %v", name, !encrypted, encrypted)
+		}
+	}
+	if got, _ := ReadFile(filepath.Join(dir, "a.json")); string(got) != `{"a":1}` {
+		t.Errorf("a.json = %q", got)
+	}
+	if n, _ := MigrateDir(filepath.Join(dir, "missing")); n != 0 {
+		t.Errorf("missing dir migrated %d", n)
+	}
+}
+
+func TestUnlockPassphrase(t *testing.T) {
+	useKey(t, nil)
+	dir := t.TempDir()
+	salt := []byte("0123456789abcdef")
+	k, err := deriveKey("correct horse", salt, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check, _ := encrypt(k, []byte(checkPlaintext))
+	data, _ := json.Marshal(meta{Source: SourcePassphrase, Salt: salt, Iterations: 1000, Check: check})
+	os.WriteFile(filepath.Join(dir, "vault.json"), data, 0600)
+
+	mu.Lock()
+	metaDir = dir
+	mu.Unlock()
+
+	if err := Unlock(""); err != ErrLocked {
+		t.Errorf("empty passphrase: got %v, want ErrLocked", err)
+	}
+	if err := Unlock("wrong"); err == nil {
+		t.Error("wrong passphrase should fail")
+	}
+	if src, _ := Status(); src != "" {
+		t.Errorf("source = %q after failed unlock", src)
+	}
+	if err := Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if src, err := Status(); src != SourcePassphrase || err != nil {
+		t.Errorf("status = %q, %v", src, err)
+	}
+}
+
+func TestEncryptString(t *testing.T) {
+	useKey(t, bytes.Repeat([]byte{7}, keySize))
+
+	sealed, err := EncryptString("proxy-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncryptedString(sealed) || strings.Contains(sealed, "proxy-secret") {
+		t.Fatalf("文本未加密: %q", sealed)
+	}
+	if got, err := DecryptString(sealed); err != nil || got != "proxy-secret" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+
+	// 空文本和旧版本的明文原样返回
+	if got, _ := EncryptString(""); got != "" {
+		t.Errorf("EncryptString(\"\") = %q", got)
+	}
+	if got, err := DecryptString("plain-password"); err != nil || got != "plain-password" {
+		t.Errorf("明文应原样返回: %q, %v", got, err)
+	}
+
+	useKey(t, bytes.Repeat([]byte{8}, keySize))
+	if _, err := DecryptString(sealed); err == nil {
+		t.Error("expected decrypt error with wrong key")
+	}
+}
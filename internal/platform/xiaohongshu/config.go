@@ -8,7 +8,6 @@ type Config struct {
 	ElementWaitTimeout   time.Duration
 	SubmitCheckTimeout   time.Duration
 	MaxPublishRetries    int
-	TitleMaxLength       int
 	UploadCheckInterval  time.Duration
 	MaxLoginWaitAttempts int
 }
@@ -19,7 +18,6 @@ var defaultConfig = Config{
 	ElementWaitTimeout:   5 * time.Second,
 	SubmitCheckTimeout:   30 * time.Second,
 	MaxPublishRetries:    20,
-	TitleMaxLength:       30,
 	UploadCheckInterval:  500 * time.Millisecond,
 	MaxLoginWaitAttempts: 30,
 }
@@ -36,14 +36,14 @@ func (h *CoverHandler) SetCover(page playwright.Page, coverPath string) error {
 		return fmt.Errorf("失败: 设置封面 - 未找到封面设置按钮: %w", err)
 	}
 
-	if err := coverBtn.Click(); err != nil {
+	if err := human.Click(coverBtn); err != nil {
 		return fmt.Errorf("失败: 设置封面 - 点击封面设置按钮失败: %w", err)
 	}
 	time.Sleep(2 * time.Second)
 
 	verticalCoverBtn := page.GetByText("设置竖封面").First()
 	if count, _ := verticalCoverBtn.Count(); count > 0 {
-		verticalCoverBtn.Click()
+		human.Click(verticalCoverBtn)
 		time.Sleep(2 * time.Second)
 	}
 
@@ -67,7 +67,7 @@ func (h *CoverHandler) SetCover(page playwright.Page, coverPath string) error {
 
 	finishBtn := page.Locator("div[class^='extractFooter'] button:visible:has-text('完成')").First()
 	if count, _ := finishBtn.Count(); count > 0 {
-		if err := finishBtn.Click(); err != nil {
+		if err := human.Click(finishBtn); err != nil {
 			utils.WarnWithPlatform("xiaohongshu", fmt.Sprintf("失败: 设置封面 - 点击完成按钮失败: %v", err))
 		}
 	}
@@ -25,13 +25,13 @@ func (u *Uploader) setScheduleTime(page playwright.Page, scheduleTime string) er
 		return fmt.Errorf("失败: 设置定时发布 - 未找到定时发布选项: %w", err)
 	}
 
-	if err := labelElement.Click(); err != nil {
+	if err := human.Click(labelElement); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 点击定时发布失败: %w", err)
 	}
 	time.Sleep(1 * time.Second)
 
 	scheduleInput := page.Locator(".el-input__inner[placeholder=\"选择日期和时间\"]")
-	if err := scheduleInput.Click(); err != nil {
+	if err := human.Click(scheduleInput); err != nil {
 		return fmt.Errorf("失败: 设置定时发布 - 点击时间输入框失败: %w", err)
 	}
 	time.Sleep(500 * time.Millisecond)
@@ -60,14 +60,14 @@ func (u *Uploader) publish(page playwright.Page, browserCtx *browser.PooledConte
 		if isScheduled {
 			button := page.Locator("button:has-text('定时发布')")
 			if count, _ := button.Count(); count > 0 {
-				if err := button.Click(); err != nil {
+				if err := human.Click(button); err != nil {
 					utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 发布 - 点击定时发布按钮失败: %v", err))
 				}
 			}
 		} else {
 			button := page.Locator("button:has-text('发布')")
 			if count, _ := button.Count(); count > 0 {
-				if err := button.Click(); err != nil {
+				if err := human.Click(button); err != nil {
 					utils.WarnWithPlatform(u.platform, fmt.Sprintf("失败: 发布 - 点击发布按钮失败: %v", err))
 				}
 			}
@@ -9,12 +9,16 @@ import (
 
 	"Fuploader/internal/config"
 	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/types"
 	"Fuploader/internal/utils"
 
 	"github.com/playwright-community/playwright-go"
 )
 
+// human 上传页面的点击和输入，按浏览器池配置的 xiaohongshu 人类交互预设执行
+var human = browser.HumanFor("xiaohongshu")
+
 type Uploader struct {
 	accountID    uint
 	cookiePath   string
@@ -24,18 +28,12 @@ type Uploader struct {
 	coverHandler *CoverHandler
 }
 
-var defaultPool *browser.Pool
-
-func init() {
-	defaultPool = browser.NewPoolFromConfig()
-}
-
 func NewUploader(cookiePath string) *Uploader {
 	u := &Uploader{
 		accountID:    0,
 		cookiePath:   cookiePath,
 		platform:     "xiaohongshu",
-		browserPool:  defaultPool,
+		browserPool:  browser.GetDefaultPool(),
 		config:       DefaultConfig(),
 	}
 	u.coverHandler = NewCoverHandler(u.config)
@@ -52,7 +50,7 @@ func NewUploaderWithAccount(accountID uint) *Uploader {
 		accountID:    accountID,
 		cookiePath:   cookiePath,
 		platform:     "xiaohongshu",
-		browserPool:  defaultPool,
+		browserPool:  browser.GetDefaultPool(),
 		config:       DefaultConfig(),
 	}
 	u.coverHandler = NewCoverHandler(u.config)
@@ -60,8 +58,10 @@ func NewUploaderWithAccount(accountID uint) *Uploader {
 	return u
 }
 
-func NewUploaderWithPool(accountID uint, pool *browser.Pool) *Uploader {
-	cookiePath := config.GetCookiePath("xiaohongshu", int(accountID))
+func NewUploaderWithPool(accountID uint, cookiePath string, pool *browser.Pool) *Uploader {
+	if cookiePath == "" {
+		cookiePath = config.GetCookiePath("xiaohongshu", int(accountID))
+	}
 	u := &Uploader{
 		accountID:    accountID,
 		cookiePath:   cookiePath,
@@ -95,7 +95,7 @@ func (u *Uploader) getBrowserPool() *browser.Pool {
 	if u.browserPool != nil {
 		return u.browserPool
 	}
-	return defaultPool
+	return browser.GetDefaultPool()
 }
 
 func (u *Uploader) Upload(ctx context.Context, task *types.VideoTask) error {
@@ -180,24 +180,21 @@ func (u *Uploader) fillTitle(page playwright.Page, title string) error {
 
 	utils.InfoWithPlatform(u.platform, "填写标题...")
 
-	runes := []rune(title)
-	if len(runes) > u.config.TitleMaxLength {
-		title = string(runes[:u.config.TitleMaxLength])
-	}
+	title = platformutils.ApplyTextRule(u.platform, platformutils.FieldTitle, title)
 
 	newInput := page.Locator("input.d-text[placeholder*='标题']")
 	newCount, _ := newInput.Count()
 	if newCount > 0 {
-		newInput.Fill(title)
+		human.Fill(newInput, title)
 	} else {
 		oldInput := page.Locator("input.d-text[type='text']").First()
 		oldCount, _ := oldInput.Count()
 		if oldCount > 0 {
-			oldInput.Click()
+			human.Click(oldInput)
 			page.Keyboard().Press("Backspace")
 			page.Keyboard().Press("Control+KeyA")
 			page.Keyboard().Press("Delete")
-			page.Keyboard().Type(title)
+			human.Type(page, title)
 			page.Keyboard().Press("Enter")
 		} else {
 			return fmt.Errorf("失败: 填写标题 - 未找到标题输入框")
@@ -212,6 +209,8 @@ func (u *Uploader) fillTitle(page playwright.Page, title string) error {
 func (u *Uploader) fillDescription(page playwright.Page, description string) error {
 	utils.InfoWithPlatform(u.platform, "填写描述...")
 
+	description = platformutils.ApplyTextRule(u.platform, platformutils.FieldDescription, description)
+
 	editor := page.Locator(".tiptap.ProseMirror")
 	if err := editor.WaitFor(playwright.LocatorWaitForOptions{
 		Timeout: playwright.Float(float64(u.config.ElementWaitTimeout.Milliseconds())),
@@ -219,14 +218,16 @@ func (u *Uploader) fillDescription(page playwright.Page, description string) err
 		return fmt.Errorf("失败: 填写描述 - 未找到描述编辑器: %w", err)
 	}
 
-	if err := editor.Click(); err != nil {
+	if err := human.Click(editor); err != nil {
 		return fmt.Errorf("失败: 填写描述 - 点击编辑器失败: %w", err)
 	}
 	time.Sleep(300 * time.Millisecond)
 
 	page.Keyboard().Press("Control+KeyA")
 	page.Keyboard().Press("Delete")
-	page.Keyboard().Type(description)
+	if err := human.Type(page, description); err != nil {
+		return fmt.Errorf("失败: 填写描述 - %w", err)
+	}
 
 	utils.InfoWithPlatform(u.platform, "描述已填写")
 	time.Sleep(500 * time.Millisecond)
@@ -244,7 +245,7 @@ func (u *Uploader) addTags(page playwright.Page, tags []string) error {
 		return fmt.Errorf("失败: 添加标签 - 未找到编辑器: %w", err)
 	}
 
-	if err := editor.Click(); err != nil {
+	if err := human.Click(editor); err != nil {
 		return fmt.Errorf("失败: 添加标签 - 点击编辑器失败: %w", err)
 	}
 
@@ -255,8 +256,9 @@ func (u *Uploader) addTags(page playwright.Page, tags []string) error {
 			continue
 		}
 
-		page.Type(cssSelector, "#"+cleanTag)
-		page.Press(cssSelector, "Space")
+		// 编辑器已在上面点击聚焦，话题逐个输入在光标处
+		human.Type(page, "#"+cleanTag)
+		page.Keyboard().Press("Space")
 		time.Sleep(500 * time.Millisecond)
 	}
 
@@ -267,19 +269,19 @@ func (u *Uploader) addTags(page playwright.Page, tags []string) error {
 func (u *Uploader) setLocation(page playwright.Page, location string) error {
 	utils.InfoWithPlatform(u.platform, "设置位置...")
 
-	locEle, err := page.WaitForSelector("div.d-text.d-select-placeholder.d-text-ellipsis.d-text-nowrap", playwright.PageWaitForSelectorOptions{
+	locSelector := "div.d-text.d-select-placeholder.d-text-ellipsis.d-text-nowrap"
+	if _, err := page.WaitForSelector(locSelector, playwright.PageWaitForSelectorOptions{
 		Timeout: playwright.Float(float64(u.config.ElementWaitTimeout.Milliseconds())),
-	})
-	if err != nil {
+	}); err != nil {
 		return fmt.Errorf("失败: 设置位置 - 未找到位置选择器: %w", err)
 	}
 
-	if err := locEle.Click(); err != nil {
+	if err := human.Click(page.Locator(locSelector).First()); err != nil {
 		return fmt.Errorf("失败: 设置位置 - 点击位置选择器失败: %w", err)
 	}
 	time.Sleep(1 * time.Second)
 
-	page.Keyboard().Type(location)
+	human.Type(page, location)
 	time.Sleep(3 * time.Second)
 
 	flexibleXPath := fmt.Sprintf(
@@ -298,7 +300,7 @@ func (u *Uploader) setLocation(page playwright.Page, location string) error {
 
 		isVisible, _ := locationOption.IsVisible()
 		if isVisible {
-			if err := locationOption.Click(); err != nil {
+			if err := human.Click(page.Locator(flexibleXPath).First()); err != nil {
 				return fmt.Errorf("失败: 设置位置 - 点击位置选项失败: %w", err)
 			}
 			return nil
@@ -307,7 +309,7 @@ func (u *Uploader) setLocation(page playwright.Page, location string) error {
 
 	fallbackOption := page.Locator(fmt.Sprintf("div:has-text('%s')", location)).First()
 	if count, _ := fallbackOption.Count(); count > 0 {
-		fallbackOption.Click()
+		human.Click(fallbackOption)
 		return nil
 	}
 
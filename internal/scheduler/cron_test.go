@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-03-05 是周二
+	from := time.Date(2024, 3, 5, 20, 30, 0, 0, loc)
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 9,18 * * *", time.Date(2024, 3, 6, 9, 0, 0, 0, loc)},
+		{"30 20 * * *", time.Date(2024, 3, 6, 20, 30, 0, 0, loc)},
+		{"0 19 * * 1-5", time.Date(2024, 3, 6, 19, 0, 0, 0, loc)},
+		{"0 10 * * 6,7", time.Date(2024, 3, 9, 10, 0, 0, 0, loc)},
+		{"*/15 21 * * *", time.Date(2024, 3, 5, 21, 0, 0, 0, loc)},
+		{"0 12 1 * *", time.Date(2024, 4, 1, 12, 0, 0, 0, loc)},
+		{"@daily", time.Date(2024, 3, 6, 0, 0, 0, 0, loc)},
+	}
+	for _, c := range cases {
+		spec, err := ParseCron(c.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", c.expr, err)
+		}
+		if got := spec.Next(from); !got.Equal(c.want) {
+			t.Errorf("%s: got %s, want %s", c.expr, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"", "0 9 * *", "60 * * * *", "0 9 * * 8", "a b c d e"} {
+		if _, err := ParseCron(bad); err == nil {
+			t.Errorf("%q 应解析失败", bad)
+		}
+	}
+}
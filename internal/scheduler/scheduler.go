@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"Fuploader/internal/config"
 	"Fuploader/internal/database"
 	"Fuploader/internal/types"
 	"Fuploader/internal/utils"
@@ -22,8 +23,14 @@ type EnhancedScheduler struct {
 	wg         sync.WaitGroup
 	mu         sync.RWMutex
 	running    bool
+
+	// executor 上传任务执行器，执行关联了上传任务（本地定时）的定时任务
+	executor TaskExecutor
 }
 
+// TaskExecutor 定时任务执行器
+type TaskExecutor func(ctx context.Context, task *database.ScheduledTask) error
+
 func NewEnhancedScheduler(db *gorm.DB, workers int) *EnhancedScheduler {
 	return &EnhancedScheduler{
 		db:        db,
@@ -40,6 +47,13 @@ func (s *EnhancedScheduler) RegisterUploader(platform string, uploader types.Upl
 	s.uploaders[platform] = uploader
 }
 
+// SetTaskExecutor 设置上传任务执行器
+func (s *EnhancedScheduler) SetTaskExecutor(executor TaskExecutor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.executor = executor
+}
+
 func (s *EnhancedScheduler) Start() {
 	s.mu.Lock()
 	if s.running {
@@ -49,6 +63,8 @@ func (s *EnhancedScheduler) Start() {
 	s.running = true
 	s.mu.Unlock()
 
+	s.recoverStaleTasks()
+
 	for i := 0; i < s.workers; i++ {
 		s.wg.Add(1)
 		go s.worker(i)
@@ -60,6 +76,65 @@ func (s *EnhancedScheduler) Start() {
 	utils.Info(fmt.Sprintf("[+] 调度器已启动，工作线程数: %d", s.workers))
 }
 
+// interruptedTaskError 上次执行被中断的任务的错误信息
+const interruptedTaskError = "上次执行时应用被中断，视频可能已经发布，请在平台确认后手动重试"
+
+// recoverStaleTasks 处理上次运行中断（崩溃或强制退出）时仍处于 running 的任务
+// 中断时可能已经点击了发布，自动重新执行会重复发布，因此任务和关联的上传任务都标记为失败，由用户确认后重试
+func (s *EnhancedScheduler) recoverStaleTasks() {
+	var stale []database.ScheduledTask
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ?", database.TaskStatusRunning).Find(&stale).Error; err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(stale))
+		var uploadTaskIDs []int
+		for _, task := range stale {
+			ids = append(ids, task.ID)
+			if task.UploadTaskID > 0 {
+				uploadTaskIDs = append(uploadTaskIDs, task.UploadTaskID)
+			}
+		}
+		now := time.Now()
+		if err := tx.Model(&database.ScheduledTask{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       database.TaskStatusFailed,
+				"error":        interruptedTaskError,
+				"completed_at": now,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return err
+		}
+		if len(uploadTaskIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&database.UploadTask{}).
+			Where("id IN ? AND status = ?", uploadTaskIDs, config.TaskStatusUploading).
+			Updates(map[string]interface{}{
+				"status":    config.TaskStatusFailed,
+				"error_msg": interruptedTaskError,
+			}).Error
+	})
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 恢复中断的定时任务失败: %v", err))
+		return
+	}
+	if len(stale) > 0 {
+		utils.Warn(fmt.Sprintf("[-] %d 个定时任务上次执行时被中断，已标记为失败，请确认是否已发布后重试", len(stale)))
+	}
+}
+
+// CountRunningTasks 统计正在执行的定时任务数量
+func (s *EnhancedScheduler) CountRunningTasks() int64 {
+	var count int64
+	s.db.Model(&database.ScheduledTask{}).Where("status = ?", database.TaskStatusRunning).Count(&count)
+	return count
+}
+
 func (s *EnhancedScheduler) Stop() {
 	s.mu.Lock()
 	if !s.running {
@@ -80,11 +155,19 @@ func (s *EnhancedScheduler) AddTask(task *database.ScheduledTask) error {
 	task.Status = database.TaskStatusPending
 	task.CreatedAt = time.Now()
 	task.UpdatedAt = time.Now()
+	// 统一以 UTC 存储，保证 SQLite 中按字符串比较时间的结果正确
+	task.ScheduleTime = task.ScheduleTime.UTC()
 
 	if err := s.db.Create(task).Error; err != nil {
 		return fmt.Errorf("保存任务失败: %w", err)
 	}
 
+	// 未到定时时间的任务由定时检查入队
+	if task.ScheduleTime.After(time.Now()) {
+		utils.Info(fmt.Sprintf("[+] 任务已保存，将于 %s 执行: %s", task.ScheduleTime.Local().Format("2006-01-02 15:04"), task.ID))
+		return nil
+	}
+
 	select {
 	case s.taskQueue <- task:
 		utils.Info(fmt.Sprintf("[+] 任务已添加到队列: %s", task.ID))
@@ -128,7 +211,7 @@ func (s *EnhancedScheduler) scheduler() {
 
 func (s *EnhancedScheduler) checkPendingTasks() {
 	var tasks []database.ScheduledTask
-	now := time.Now()
+	now := time.Now().UTC()
 
 	if err := s.db.Where("status = ? AND schedule_time <= ?", database.TaskStatusPending, now).
 		Order("priority DESC, schedule_time ASC").
@@ -145,6 +228,26 @@ func (s *EnhancedScheduler) checkPendingTasks() {
 }
 
 func (s *EnhancedScheduler) executeTask(task *database.ScheduledTask) {
+	// 抢占任务，避免同一任务被重复入队后执行多次
+	now := time.Now()
+	result := s.db.Model(&database.ScheduledTask{}).
+		Where("id = ? AND status = ?", task.ID, database.TaskStatusPending).
+		Updates(map[string]interface{}{
+			"status":      database.TaskStatusRunning,
+			"executed_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return
+	}
+	task.Status = database.TaskStatusRunning
+	task.ExecutedAt = &now
+
+	if task.UploadTaskID > 0 {
+		s.executeUploadTask(task)
+		return
+	}
+
 	s.mu.RLock()
 	uploader, ok := s.uploaders[task.Platform]
 	s.mu.RUnlock()
@@ -154,8 +257,6 @@ func (s *EnhancedScheduler) executeTask(task *database.ScheduledTask) {
 		return
 	}
 
-	s.updateTaskStatus(task, database.TaskStatusRunning, "")
-
 	videoTask := &types.VideoTask{
 		VideoPath:   task.VideoPath,
 		Title:       task.Title,
@@ -171,6 +272,35 @@ func (s *EnhancedScheduler) executeTask(task *database.ScheduledTask) {
 	s.updateTaskStatus(task, database.TaskStatusCompleted, "")
 }
 
+// executeUploadTask 交给上传任务执行器执行本地定时任务
+func (s *EnhancedScheduler) executeUploadTask(task *database.ScheduledTask) {
+	s.mu.RLock()
+	executor := s.executor
+	s.mu.RUnlock()
+
+	if executor == nil {
+		s.updateTaskStatus(task, database.TaskStatusFailed, "未设置上传任务执行器")
+		return
+	}
+
+	if err := executor(context.Background(), task); err != nil {
+		s.updateTaskStatus(task, database.TaskStatusFailed, err.Error())
+		return
+	}
+
+	s.updateTaskStatus(task, database.TaskStatusCompleted, "")
+}
+
+// CancelUploadTask 取消上传任务关联的未执行定时任务
+func (s *EnhancedScheduler) CancelUploadTask(uploadTaskID int) error {
+	return s.db.Model(&database.ScheduledTask{}).
+		Where("upload_task_id = ? AND status = ?", uploadTaskID, database.TaskStatusPending).
+		Updates(map[string]interface{}{
+			"status":     database.TaskStatusCancelled,
+			"updated_at": time.Now(),
+		}).Error
+}
+
 func (s *EnhancedScheduler) updateTaskStatus(task *database.ScheduledTask, status database.TaskStatus, errMsg string) {
 	task.Status = status
 	task.Error = errMsg
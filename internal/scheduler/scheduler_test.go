@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var testConfigOnce sync.Once
+
+func newTestScheduler(t *testing.T) *EnhancedScheduler {
+	t.Helper()
+	// 调度器会写日志，日志文件需要配置目录
+	testConfigOnce.Do(func() {
+		if config.Config == nil {
+			dir, err := os.MkdirTemp("", "fuploader-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			config.Config = &config.AppConfig{LogPath: dir}
+		}
+	})
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return NewEnhancedScheduler(db, 1)
+}
+
+func addJob(t *testing.T, s *EnhancedScheduler, id string, status database.TaskStatus, uploadTaskID int) *database.ScheduledTask {
+	t.Helper()
+	job := &database.ScheduledTask{
+		ID:           id,
+		Platform:     "douyin",
+		ScheduleTime: time.Now().Add(-time.Minute).UTC(),
+		Status:       status,
+		UploadTaskID: uploadTaskID,
+	}
+	if err := s.db.Create(job).Error; err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func loadJob(t *testing.T, s *EnhancedScheduler, id string) database.ScheduledTask {
+	t.Helper()
+	var job database.ScheduledTask
+	if err := s.db.First(&job, "id = ?", id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestExecuteTaskClaimsOnce(t *testing.T) {
+	s := newTestScheduler(t)
+	var calls int32
+	s.SetTaskExecutor(func(ctx context.Context, task *database.ScheduledTask) error {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	job := addJob(t, s, "upload_1", database.TaskStatusPending, 1)
+
+	// 同一任务被重复入队，只有一个执行者能抢占
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queued := *job
+			s.executeTask(&queued)
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("executor calls = %d, want 1", calls)
+	}
+	if got := loadJob(t, s, job.ID); got.Status != database.TaskStatusCompleted || got.CompletedAt == nil {
+		t.Errorf("status = %s completedAt = %v, want completed", got.Status, got.CompletedAt)
+	}
+
+	// 已完成的任务不会再次执行
+	s.executeTask(job)
+	if calls != 1 {
+		t.Errorf("executor calls after completion = %d, want 1", calls)
+	}
+}
+
+func TestExecuteUploadTask(t *testing.T) {
+	s := newTestScheduler(t)
+
+	job := addJob(t, s, "no_executor", database.TaskStatusPending, 1)
+	s.executeTask(job)
+	if got := loadJob(t, s, job.ID); got.Status != database.TaskStatusFailed || got.Error == "" {
+		t.Errorf("without executor: status = %s error = %q, want failed", got.Status, got.Error)
+	}
+
+	var received database.ScheduledTask
+	s.SetTaskExecutor(func(ctx context.Context, task *database.ScheduledTask) error {
+		received = *task
+		return errors.New("上传失败")
+	})
+	job = addJob(t, s, "fails", database.TaskStatusPending, 7)
+	s.executeTask(job)
+	if received.UploadTaskID != 7 || received.Status != database.TaskStatusRunning {
+		t.Fatalf("executor received %+v, want running job for upload task 7", received)
+	}
+	if got := loadJob(t, s, job.ID); got.Status != database.TaskStatusFailed || got.Error != "上传失败" {
+		t.Errorf("status = %s error = %q, want failed with executor error", got.Status, got.Error)
+	}
+}
+
+func TestRecoverStaleTasks(t *testing.T) {
+	s := newTestScheduler(t)
+
+	uploading := database.UploadTask{Status: config.TaskStatusUploading}
+	if err := s.db.Create(&uploading).Error; err != nil {
+		t.Fatal(err)
+	}
+	addJob(t, s, "stale", database.TaskStatusRunning, uploading.ID)
+	addJob(t, s, "done", database.TaskStatusCompleted, 0)
+
+	s.recoverStaleTasks()
+
+	// 中断时可能已经发布，不自动重新执行，标记为失败由用户确认后重试
+	if got := loadJob(t, s, "stale"); got.Status != database.TaskStatusFailed || got.Error != interruptedTaskError {
+		t.Errorf("stale job status = %s error = %q, want failed", got.Status, got.Error)
+	}
+	if got := loadJob(t, s, "done"); got.Status != database.TaskStatusCompleted {
+		t.Errorf("completed job status = %s, want unchanged", got.Status)
+	}
+	var task database.UploadTask
+	if err := s.db.First(&task, uploading.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != config.TaskStatusFailed || task.ErrorMsg != interruptedTaskError {
+		t.Errorf("upload task status = %s error = %q, want failed", task.Status, task.ErrorMsg)
+	}
+	if s.CountRunningTasks() != 0 {
+		t.Errorf("running tasks = %d, want 0", s.CountRunningTasks())
+	}
+}
@@ -3,14 +3,9 @@ package service
 import (
 	"Fuploader/internal/config"
 	"Fuploader/internal/database"
-	"Fuploader/internal/platform/baijiahao"
-	"Fuploader/internal/platform/bilibili"
-	"Fuploader/internal/platform/douyin"
-	"Fuploader/internal/platform/kuaishou"
-	"Fuploader/internal/platform/tencent"
-	"Fuploader/internal/platform/tiktok"
-	"Fuploader/internal/platform/xiaohongshu"
+	"Fuploader/internal/platform/browser"
 	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
 	"context"
 	"fmt"
 	"os"
@@ -20,11 +15,12 @@ import (
 )
 
 type AccountService struct {
-	db *gorm.DB
+	db   *gorm.DB
+	pool *browser.Pool
 }
 
-func NewAccountService(db *gorm.DB) *AccountService {
-	return &AccountService{db: db}
+func NewAccountService(db *gorm.DB, pool *browser.Pool) *AccountService {
+	return &AccountService{db: db, pool: pool}
 }
 
 func (s *AccountService) GetAccounts(ctx context.Context) ([]database.Account, error) {
@@ -78,6 +74,9 @@ func (s *AccountService) AddAccount(ctx context.Context, platform string, name s
 }
 
 func (s *AccountService) DeleteAccount(ctx context.Context, id int) error {
+	var account database.Account
+	s.db.Select("id", "cookie_path").Limit(1).Find(&account, id)
+
 	result := s.db.Delete(&database.Account{}, id)
 	if result.Error != nil {
 		return fmt.Errorf("delete account failed: %w", result.Error)
@@ -85,6 +84,12 @@ func (s *AccountService) DeleteAccount(ctx context.Context, id int) error {
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("account not found")
 	}
+	// 删除账号的固定指纹和代理，避免账号ID被复用时沿用旧配置
+	s.db.Where("account_id = ?", id).Delete(&database.AccountFingerprint{})
+	s.db.Where("account_id = ?", id).Delete(&database.AccountProxy{})
+	if err := browser.RemoveUserDataDir(account.CookiePath); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 删除账号浏览器用户数据目录失败: %v", err))
+	}
 	return nil
 }
 
@@ -103,7 +108,10 @@ func (s *AccountService) ValidateAccount(ctx context.Context, id int) (bool, err
 		return false, fmt.Errorf("account not found")
 	}
 
-	uploader := s.getUploader(account.Platform, uint(account.ID), account.CookiePath)
+	uploader, err := s.getUploader(account.Platform, uint(account.ID), account.CookiePath)
+	if err != nil {
+		return false, err
+	}
 	valid, err := uploader.ValidateCookie(ctx)
 	if err != nil {
 		return false, err
@@ -139,7 +147,10 @@ func (s *AccountService) LoginAccount(ctx context.Context, id int) error {
 	fmt.Printf("[DEBUG] LoginAccount - AccountID: %d, Platform: %s, CookiePath: %s\n",
 		account.ID, account.Platform, account.CookiePath)
 
-	uploader := s.getUploader(account.Platform, uint(account.ID), account.CookiePath)
+	uploader, err := s.getUploader(account.Platform, uint(account.ID), account.CookiePath)
+	if err != nil {
+		return err
+	}
 	if err := uploader.Login(); err != nil {
 		return fmt.Errorf("login failed: %w", err)
 	}
@@ -173,9 +184,16 @@ func (s *AccountService) ReloginAccount(ctx context.Context, id int) error {
 				return fmt.Errorf("remove old cookie file failed: %w", err)
 			}
 		}
+		// 持久化模式下旧登录态还保存在用户数据目录中
+		if err := browser.RemoveUserDataDir(account.CookiePath); err != nil {
+			return fmt.Errorf("remove old user data dir failed: %w", err)
+		}
 	}
 
-	uploader := s.getUploader(account.Platform, uint(account.ID), account.CookiePath)
+	uploader, err := s.getUploader(account.Platform, uint(account.ID), account.CookiePath)
+	if err != nil {
+		return err
+	}
 	if err := uploader.Login(); err != nil {
 		return fmt.Errorf("login failed: %w", err)
 	}
@@ -186,25 +204,12 @@ func (s *AccountService) ReloginAccount(ctx context.Context, id int) error {
 	return nil
 }
 
-func (s *AccountService) getUploader(platform string, accountID uint, cookiePath string) types.Uploader {
-	switch platform {
-	case config.PlatformDouyin:
-		return douyin.NewUploader(cookiePath)
-	case config.PlatformTencent:
-		return tencent.NewUploaderWithAccount(accountID)
-	case config.PlatformKuaishou:
-		return kuaishou.NewUploader(cookiePath)
-	case config.PlatformTiktok:
-		return tiktok.NewUploader(cookiePath)
-	case config.PlatformXiaohongshu:
-		return xiaohongshu.NewUploader(cookiePath)
-	case config.PlatformBaijiahao:
-		return baijiahao.NewUploader(cookiePath)
-	case config.PlatformBilibili:
-		return bilibili.NewUploader(cookiePath)
-	default:
-		return douyin.NewUploader(cookiePath)
+func (s *AccountService) getUploader(platform string, accountID uint, cookiePath string) (types.Uploader, error) {
+	uploader := NewUploader(platform, accountID, cookiePath, s.pool)
+	if uploader == nil {
+		return nil, fmt.Errorf("unsupported platform: %s", platform)
 	}
+	return uploader, nil
 }
 
 func (s *AccountService) GetCookiePath(platform string, accountID uint) string {
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxLimitIterations 计算最早可发布时间时的最大调整次数
+const maxLimitIterations = 1000
+
+// activeTaskStatuses 尚未完成、将要发布的任务状态
+var activeTaskStatuses = []string{config.TaskStatusPending, config.TaskStatusRateLimited, config.TaskStatusUploading}
+
+// AccountLimitService 账号级限额：每日/每小时发布数和最小发布间隔
+type AccountLimitService struct {
+	db        *gorm.DB
+	ledger    *PublishLedger
+	schedules *ScheduleService
+}
+
+// NewAccountLimitService 创建账号级限额服务
+func NewAccountLimitService(db *gorm.DB, ledger *PublishLedger, schedules *ScheduleService) *AccountLimitService {
+	return &AccountLimitService{
+		db:        db,
+		ledger:    ledger,
+		schedules: schedules,
+	}
+}
+
+// GetLimit 获取账号限额，未设置时返回全 0（不限制）
+func (s *AccountLimitService) GetLimit(ctx context.Context, accountID int) (*database.AccountRateLimit, error) {
+	limit := database.AccountRateLimit{AccountID: accountID}
+	if err := s.db.Where("account_id = ?", accountID).Limit(1).Find(&limit).Error; err != nil {
+		return nil, fmt.Errorf("query account rate limit failed: %w", err)
+	}
+	return &limit, nil
+}
+
+// SetLimit 设置账号限额
+func (s *AccountLimitService) SetLimit(ctx context.Context, limit *database.AccountRateLimit) error {
+	if limit.DailyLimit < 0 || limit.HourlyLimit < 0 || limit.MinSpacingMinutes < 0 {
+		return fmt.Errorf("限额不能为负数")
+	}
+	if limit.DailyLimit > 0 && limit.HourlyLimit > limit.DailyLimit {
+		return fmt.Errorf("每小时限额不能大于每日限额")
+	}
+	var count int64
+	s.db.Model(&database.Account{}).Where("id = ?", limit.AccountID).Count(&count)
+	if count == 0 {
+		return fmt.Errorf("account not found")
+	}
+	if err := s.db.Save(limit).Error; err != nil {
+		return fmt.Errorf("save account rate limit failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteLimit 删除账号限额（恢复为不限制）
+func (s *AccountLimitService) DeleteLimit(ctx context.Context, accountID int) error {
+	if err := s.db.Delete(&database.AccountRateLimit{}, "account_id = ?", accountID).Error; err != nil {
+		return fmt.Errorf("delete account rate limit failed: %w", err)
+	}
+	return nil
+}
+
+// EarliestAllowed 按账号限额计算不早于 at 的最早可发布时间，返回调整原因（未调整时为空）
+// 已发布/已提交平台定时的记录和尚未完成的任务都计入，excludeTaskID 为正在排期的任务自身
+func (s *AccountLimitService) EarliestAllowed(ctx context.Context, account *database.Account, at time.Time, excludeTaskID int) (time.Time, string) {
+	limit, err := s.GetLimit(ctx, account.ID)
+	if err != nil || (limit.DailyLimit == 0 && limit.HourlyLimit == 0 && limit.MinSpacingMinutes == 0) {
+		return at, ""
+	}
+	publishes, err := s.plannedPublishes(ctx, account, at.Add(-ledgerDailyWindow), excludeTaskID)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 查询账号 %s 发布记录失败: %v", account.Name, err))
+		return at, ""
+	}
+	return EarliestAllowedTime(*limit, publishes, at)
+}
+
+// plannedPublishes 账号在 from 之后已发布和计划发布的时间（升序）
+func (s *AccountLimitService) plannedPublishes(ctx context.Context, account *database.Account, from time.Time, excludeTaskID int) ([]time.Time, error) {
+	publishes, err := s.ledger.AccountPublishTimes(account.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	unrecorded, err := s.unrecordedPublishes(ctx, s.db.Where("account_id = ?", account.ID), from, excludeTaskID)
+	if err != nil {
+		return nil, err
+	}
+	publishes = append(publishes, unrecorded...)
+	sort.Slice(publishes, func(i, j int) bool { return publishes[i].Before(publishes[j]) })
+	return publishes, nil
+}
+
+// PlatformPlannedPublishes 平台在 from 之后已发布和计划发布的时间（升序）
+func (s *AccountLimitService) PlatformPlannedPublishes(ctx context.Context, platform string, from time.Time, excludeTaskID int) ([]time.Time, error) {
+	publishes, err := s.ledger.PlatformPublishTimes(platform, from)
+	if err != nil {
+		return nil, err
+	}
+	unrecorded, err := s.unrecordedPublishes(ctx, s.db.Where("platform = ?", platform), from, excludeTaskID)
+	if err != nil {
+		return nil, err
+	}
+	publishes = append(publishes, unrecorded...)
+	sort.Slice(publishes, func(i, j int) bool { return publishes[i].Before(publishes[j]) })
+	return publishes, nil
+}
+
+// unrecordedPublishes 尚未计入台账的任务的计划发布时间：
+// 等待执行/排队中的任务按定时时间，上传中的任务按定时时间或当前时间
+func (s *AccountLimitService) unrecordedPublishes(ctx context.Context, query *gorm.DB, from time.Time, excludeTaskID int) ([]time.Time, error) {
+	var tasks []database.UploadTask
+	if err := query.Preload("Account").
+		Where("status IN ? AND id <> ?", activeTaskStatuses, excludeTaskID).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	// 同一时区只解析一次（未设置时区的账号需要读取发布配置）
+	locations := make(map[string]*time.Location)
+	var publishes []time.Time
+	for i := range tasks {
+		task := &tasks[i]
+		loc, ok := locations[task.Account.TimeZone]
+		if !ok {
+			loc = s.schedules.AccountLocation(ctx, &task.Account)
+			locations[task.Account.TimeZone] = loc
+		}
+		t := parseTaskScheduleTime(task.ScheduleTime, loc)
+		if task.Status == config.TaskStatusUploading && (t == nil || task.ScheduleMode == config.ScheduleModeLocal) {
+			t = &now
+		}
+		if t != nil && t.After(from) {
+			publishes = append(publishes, *t)
+		}
+	}
+	return publishes, nil
+}
+
+// GetStats 获取所有账号的限额统计
+func (s *AccountLimitService) GetStats(ctx context.Context) ([]types.AccountRateLimitStats, error) {
+	var accounts []database.Account
+	if err := s.db.Order("id ASC").Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("query accounts failed: %w", err)
+	}
+
+	now := time.Now()
+	stats := make([]types.AccountRateLimitStats, 0, len(accounts))
+	for i := range accounts {
+		account := &accounts[i]
+		limit, err := s.GetLimit(ctx, account.ID)
+		if err != nil {
+			return nil, err
+		}
+		item := types.AccountRateLimitStats{
+			AccountID:         account.ID,
+			AccountName:       account.Name,
+			Platform:          account.Platform,
+			DailyLimit:        limit.DailyLimit,
+			HourlyLimit:       limit.HourlyLimit,
+			MinSpacingMinutes: limit.MinSpacingMinutes,
+		}
+
+		published, err := s.ledger.AccountPublishTimes(account.ID, now.Add(-ledgerDailyWindow))
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range published {
+			if t.After(now) {
+				continue
+			}
+			item.DailyCount++
+			if t.After(now.Add(-ledgerHourlyWindow)) {
+				item.HourlyCount++
+			}
+			item.LastPublishedAt = t.Format(time.RFC3339)
+		}
+
+		var pending int64
+		s.db.Model(&database.UploadTask{}).
+			Where("account_id = ? AND status IN ?", account.ID, activeTaskStatuses).
+			Count(&pending)
+		item.PendingCount = int(pending)
+
+		next, _ := s.EarliestAllowed(ctx, account, now, 0)
+		item.NextAllowedAt = next.Format(time.RFC3339)
+		stats = append(stats, item)
+	}
+	return stats, nil
+}
+
+// EarliestAllowedTime 计算不早于 at 且满足账号限额的最早发布时间，publishes 为升序的已发布/计划发布时间
+// 每日/每小时按滚动窗口 (e-窗口, e] 计数，包含 t 的所有窗口（含 t 之后的计划发布）都不能超限；
+// 最小间隔同时约束前后两侧的发布
+func EarliestAllowedTime(limit database.AccountRateLimit, publishes []time.Time, at time.Time) (time.Time, string) {
+	spacing := time.Duration(limit.MinSpacingMinutes) * time.Minute
+	t := at
+	reason := ""
+	for i := 0; i < maxLimitIterations; i++ {
+		next := t
+		why := ""
+
+		if limit.DailyLimit > 0 {
+			if d := windowEarliest(publishes, t, ledgerDailyWindow, limit.DailyLimit); d.After(next) {
+				next, why = d, fmt.Sprintf("已达到账号每日发布上限 (%d)", limit.DailyLimit)
+			}
+		}
+		if limit.HourlyLimit > 0 {
+			if h := windowEarliest(publishes, t, ledgerHourlyWindow, limit.HourlyLimit); h.After(next) {
+				next, why = h, fmt.Sprintf("已达到账号每小时发布上限 (%d)", limit.HourlyLimit)
+			}
+		}
+		if spacing > 0 {
+			for _, p := range publishes {
+				if p.After(t.Add(-spacing)) && p.Before(t.Add(spacing)) {
+					if sp := p.Add(spacing); sp.After(next) {
+						next, why = sp, fmt.Sprintf("距离上次发布不足 %d 分钟", limit.MinSpacingMinutes)
+					}
+				}
+			}
+		}
+
+		if !next.After(t) {
+			break
+		}
+		if reason == "" {
+			reason = why
+		}
+		t = next
+	}
+	return t, reason
+}
+
+// windowEarliest 检查包含 t 的所有滚动窗口，返回 t 需要推迟到的时间（未超限时返回 t）
+// 窗口 (e-window, e] 包含 t 时 e 落在 [t, t+window)，计数只在 e 经过发布时间时增加，
+// 因此只需检查 e=t 和 (t, t+window) 内的每个计划发布
+func windowEarliest(publishes []time.Time, t time.Time, window time.Duration, limit int) time.Time {
+	next := t
+	check := func(e time.Time) {
+		// 窗口已满时 t 必须晚于窗口内倒数第 limit 个发布一个窗口长度
+		if in := publishesIn(publishes, e.Add(-window), e); len(in) >= limit {
+			if w := in[len(in)-limit].Add(window); w.After(next) {
+				next = w
+			}
+		}
+	}
+	check(t)
+	for _, p := range publishes {
+		if p.After(t) && p.Before(t.Add(window)) {
+			check(p)
+		}
+	}
+	return next
+}
+
+// publishesIn 返回 (from, to] 内的发布时间
+func publishesIn(publishes []time.Time, from, to time.Time) []time.Time {
+	var result []time.Time
+	for _, p := range publishes {
+		if p.After(from) && !p.After(to) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"testing"
+	"time"
+)
+
+func TestEarliestAllowedTime(t *testing.T) {
+	base := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("no_limit", func(t *testing.T) {
+		got, reason := EarliestAllowedTime(database.AccountRateLimit{}, []time.Time{base}, base)
+		if !got.Equal(base) || reason != "" {
+			t.Errorf("got %s %q", got, reason)
+		}
+	})
+
+	t.Run("spacing", func(t *testing.T) {
+		limit := database.AccountRateLimit{MinSpacingMinutes: 30}
+		publishes := []time.Time{base.Add(-10 * time.Minute), base.Add(20 * time.Minute)}
+		// 11:50 之后需间隔 30 分钟到 12:20，但 12:20 已有计划发布，继续顺延到 12:50
+		got, reason := EarliestAllowedTime(limit, publishes, base)
+		if want := base.Add(50 * time.Minute); !got.Equal(want) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if reason == "" {
+			t.Error("expected reason")
+		}
+	})
+
+	t.Run("hourly", func(t *testing.T) {
+		limit := database.AccountRateLimit{HourlyLimit: 2}
+		publishes := []time.Time{base.Add(-50 * time.Minute), base.Add(-20 * time.Minute)}
+		got, _ := EarliestAllowedTime(limit, publishes, base)
+		if want := base.Add(10 * time.Minute); !got.Equal(want) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("daily", func(t *testing.T) {
+		limit := database.AccountRateLimit{DailyLimit: 3}
+		publishes := []time.Time{base.Add(-20 * time.Hour), base.Add(-10 * time.Hour), base.Add(-time.Hour)}
+		got, _ := EarliestAllowedTime(limit, publishes, base)
+		if want := base.Add(4 * time.Hour); !got.Equal(want) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("future_hourly", func(t *testing.T) {
+		// 12:30 已有计划发布，12:00 发布会使 (11:30, 12:30] 超限，推迟到 13:30
+		limit := database.AccountRateLimit{HourlyLimit: 1}
+		publishes := []time.Time{base.Add(30 * time.Minute)}
+		got, reason := EarliestAllowedTime(limit, publishes, base)
+		if want := base.Add(90 * time.Minute); !got.Equal(want) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if reason == "" {
+			t.Error("expected reason")
+		}
+	})
+
+	t.Run("future_daily", func(t *testing.T) {
+		// 之前 24 小时只有 1 次发布，但 14:00 的计划发布所在窗口已有 2 次
+		limit := database.AccountRateLimit{DailyLimit: 2}
+		publishes := []time.Time{base.Add(-20 * time.Hour), base.Add(2 * time.Hour)}
+		got, _ := EarliestAllowedTime(limit, publishes, base)
+		if want := base.Add(4 * time.Hour); !got.Equal(want) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("future_outside_window", func(t *testing.T) {
+		limit := database.AccountRateLimit{HourlyLimit: 1}
+		publishes := []time.Time{base.Add(time.Hour)}
+		if got, reason := EarliestAllowedTime(limit, publishes, base); !got.Equal(base) || reason != "" {
+			t.Errorf("got %s %q, want unchanged", got, reason)
+		}
+	})
+}
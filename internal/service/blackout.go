@@ -0,0 +1,264 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxBlackoutChain 顺延时最多连续跨越的禁发时段数
+const maxBlackoutChain = 100
+
+// BlackoutHit 禁发时段命中结果
+type BlackoutHit struct {
+	Window  *database.BlackoutWindow // 首个命中的禁发时段
+	Until   time.Time                // 连续禁发时段结束后的最早可发布时间
+	Blocked bool                     // 命中的禁发时段中存在阻止策略
+}
+
+// Message 命中说明
+func (h *BlackoutHit) Message() string {
+	if h.Blocked {
+		return fmt.Sprintf("处于禁发时段「%s」，已阻止发布（至 %s）", h.Window.Name, h.Until.Format("2006-01-02 15:04"))
+	}
+	return fmt.Sprintf("处于禁发时段「%s」，顺延至 %s", h.Window.Name, h.Until.Format("2006-01-02 15:04"))
+}
+
+// BlackoutService 禁发时段服务
+type BlackoutService struct {
+	db *gorm.DB
+}
+
+// NewBlackoutService 创建禁发时段服务
+func NewBlackoutService(db *gorm.DB) *BlackoutService {
+	return &BlackoutService{db: db}
+}
+
+// GetWindows 获取所有禁发时段
+func (s *BlackoutService) GetWindows(ctx context.Context) ([]database.BlackoutWindow, error) {
+	var windows []database.BlackoutWindow
+	if err := s.db.Order("id ASC").Find(&windows).Error; err != nil {
+		return nil, fmt.Errorf("query blackout windows failed: %w", err)
+	}
+	return windows, nil
+}
+
+// CreateWindow 创建禁发时段
+func (s *BlackoutService) CreateWindow(ctx context.Context, window *database.BlackoutWindow) error {
+	if err := validateBlackoutWindow(window); err != nil {
+		return err
+	}
+	window.ID = 0
+	if err := s.db.Create(window).Error; err != nil {
+		return fmt.Errorf("create blackout window failed: %w", err)
+	}
+	return nil
+}
+
+// UpdateWindow 更新禁发时段
+func (s *BlackoutService) UpdateWindow(ctx context.Context, window *database.BlackoutWindow) error {
+	if err := validateBlackoutWindow(window); err != nil {
+		return err
+	}
+	var existing database.BlackoutWindow
+	if err := s.db.First(&existing, window.ID).Error; err != nil {
+		return fmt.Errorf("blackout window not found: %w", err)
+	}
+	window.CreatedAt = existing.CreatedAt
+	if err := s.db.Save(window).Error; err != nil {
+		return fmt.Errorf("update blackout window failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteWindow 删除禁发时段
+func (s *BlackoutService) DeleteWindow(ctx context.Context, id int) error {
+	result := s.db.Delete(&database.BlackoutWindow{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("delete blackout window failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("blackout window not found")
+	}
+	return nil
+}
+
+// Check 检查时间是否落在账号适用的禁发时段内，account 为 nil 时只检查全局禁发时段
+// 未命中时返回 nil；defaultLoc 为未设置时区的禁发时段使用的时区
+func (s *BlackoutService) Check(account *database.Account, at time.Time, defaultLoc *time.Location) *BlackoutHit {
+	windows, err := s.applicableWindows(account)
+	if err != nil || len(windows) == 0 {
+		return nil
+	}
+	return CheckBlackout(windows, at, defaultLoc)
+}
+
+// applicableWindows 账号适用的已启用禁发时段
+func (s *BlackoutService) applicableWindows(account *database.Account) ([]database.BlackoutWindow, error) {
+	query := s.db.Where("enabled = ?", true)
+	if account == nil {
+		query = query.Where("scope = ?", database.BlackoutScopeGlobal)
+	} else {
+		query = query.Where("scope = ? OR (scope = ? AND platform = ?) OR (scope = ? AND account_id = ?)",
+			database.BlackoutScopeGlobal,
+			database.BlackoutScopePlatform, account.Platform,
+			database.BlackoutScopeAccount, account.ID)
+	}
+	var windows []database.BlackoutWindow
+	if err := query.Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// CheckBlackout 检查时间是否落在禁发时段内，命中时连续跨越相邻/重叠的禁发时段计算最早可发布时间
+func CheckBlackout(windows []database.BlackoutWindow, at time.Time, defaultLoc *time.Location) *BlackoutHit {
+	var hit *BlackoutHit
+	t := at
+	for i := 0; i < maxBlackoutChain; i++ {
+		moved := false
+		for j := range windows {
+			end, ok := blackoutEnd(&windows[j], t, defaultLoc)
+			if !ok {
+				continue
+			}
+			if hit == nil {
+				hit = &BlackoutHit{Window: &windows[j]}
+			}
+			if windows[j].Policy == database.BlackoutPolicyBlock {
+				hit.Blocked = true
+			}
+			t = end
+			moved = true
+		}
+		if !moved {
+			break
+		}
+	}
+	if hit != nil {
+		hit.Until = t
+	}
+	return hit
+}
+
+// blackoutEnd 时间落在禁发时段内时返回该时段的结束时间
+func blackoutEnd(w *database.BlackoutWindow, at time.Time, defaultLoc *time.Location) (time.Time, bool) {
+	loc := defaultLoc
+	if w.TimeZone != "" {
+		if l, err := time.LoadLocation(w.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+
+	switch w.Type {
+	case database.BlackoutTypeRange:
+		start, err1 := time.ParseInLocation("2006-01-02 15:04", w.StartAt, loc)
+		end, err2 := time.ParseInLocation("2006-01-02 15:04", w.EndAt, loc)
+		if err1 != nil || err2 != nil {
+			return time.Time{}, false
+		}
+		if !at.Before(start) && at.Before(end) {
+			return end, true
+		}
+	case database.BlackoutTypeDaily:
+		startHM, err1 := time.Parse("15:04", w.StartTime)
+		endHM, err2 := time.Parse("15:04", w.EndTime)
+		if err1 != nil || err2 != nil {
+			return time.Time{}, false
+		}
+		t := at.In(loc)
+		// 跨零点的时段可能从前一天开始
+		for _, offset := range []int{0, -1} {
+			day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, loc)
+			if !weekdayMatches(w.Weekdays, day.Weekday()) {
+				continue
+			}
+			start := time.Date(day.Year(), day.Month(), day.Day(), startHM.Hour(), startHM.Minute(), 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), endHM.Hour(), endHM.Minute(), 0, 0, loc)
+			if !end.After(start) {
+				end = end.AddDate(0, 0, 1)
+			}
+			if !t.Before(start) && t.Before(end) {
+				return end, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// validateBlackoutWindow 校验禁发时段
+func validateBlackoutWindow(w *database.BlackoutWindow) error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" {
+		return fmt.Errorf("禁发时段名称不能为空")
+	}
+
+	switch w.Scope {
+	case "", database.BlackoutScopeGlobal:
+		w.Scope = database.BlackoutScopeGlobal
+	case database.BlackoutScopePlatform:
+		if w.Platform == "" {
+			return fmt.Errorf("请选择平台")
+		}
+	case database.BlackoutScopeAccount:
+		if w.AccountID == 0 {
+			return fmt.Errorf("请选择账号")
+		}
+	default:
+		return fmt.Errorf("无效的作用范围: %s", w.Scope)
+	}
+
+	switch w.Policy {
+	case "":
+		w.Policy = database.BlackoutPolicyShift
+	case database.BlackoutPolicyShift, database.BlackoutPolicyBlock:
+	default:
+		return fmt.Errorf("无效的处理策略: %s", w.Policy)
+	}
+
+	if w.TimeZone != "" {
+		if _, err := time.LoadLocation(w.TimeZone); err != nil {
+			return fmt.Errorf("无效时区: %s", w.TimeZone)
+		}
+	}
+
+	switch w.Type {
+	case database.BlackoutTypeRange:
+		start, err := time.Parse("2006-01-02 15:04", w.StartAt)
+		if err != nil {
+			return fmt.Errorf("开始时间格式错误，应为 2006-01-02 15:04")
+		}
+		end, err := time.Parse("2006-01-02 15:04", w.EndAt)
+		if err != nil {
+			return fmt.Errorf("结束时间格式错误，应为 2006-01-02 15:04")
+		}
+		if !end.After(start) {
+			return fmt.Errorf("结束时间必须晚于开始时间")
+		}
+	case database.BlackoutTypeDaily:
+		if _, err := time.Parse("15:04", w.StartTime); err != nil {
+			return fmt.Errorf("开始时间格式错误，应为 HH:MM")
+		}
+		if _, err := time.Parse("15:04", w.EndTime); err != nil {
+			return fmt.Errorf("结束时间格式错误，应为 HH:MM")
+		}
+		if w.StartTime == w.EndTime {
+			return fmt.Errorf("开始时间和结束时间不能相同")
+		}
+		for _, d := range w.Weekdays {
+			if d < 0 || d > 6 {
+				return fmt.Errorf("无效星期: %d", d)
+			}
+		}
+	default:
+		return fmt.Errorf("无效的禁发时段类型: %s", w.Type)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"testing"
+	"time"
+)
+
+func TestCheckBlackout(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	quiet := database.BlackoutWindow{
+		Name:      "夜间静默",
+		Type:      database.BlackoutTypeDaily,
+		Policy:    database.BlackoutPolicyShift,
+		StartTime: "22:00",
+		EndTime:   "08:00",
+	}
+
+	t.Run("overnight", func(t *testing.T) {
+		// 2024-03-05 是周二，凌晨 02:00 属于前一天 22:00 开始的时段
+		hit := CheckBlackout([]database.BlackoutWindow{quiet}, time.Date(2024, 3, 5, 2, 0, 0, 0, loc), loc)
+		if hit == nil {
+			t.Fatal("expected hit")
+		}
+		if want := time.Date(2024, 3, 5, 8, 0, 0, 0, loc); !hit.Until.Equal(want) || hit.Blocked {
+			t.Errorf("got %s blocked=%v, want %s", hit.Until, hit.Blocked, want)
+		}
+		if hit := CheckBlackout([]database.BlackoutWindow{quiet}, time.Date(2024, 3, 5, 8, 0, 0, 0, loc), loc); hit != nil {
+			t.Errorf("结束时间不属于禁发时段: %s", hit.Until)
+		}
+	})
+
+	t.Run("weekdays", func(t *testing.T) {
+		w := quiet
+		w.Weekdays = []int{5, 6} // 仅周五、周六晚上
+		if hit := CheckBlackout([]database.BlackoutWindow{w}, time.Date(2024, 3, 5, 23, 0, 0, 0, loc), loc); hit != nil {
+			t.Errorf("周二不应命中")
+		}
+		// 周六 01:00 属于周五晚上开始的时段
+		if hit := CheckBlackout([]database.BlackoutWindow{w}, time.Date(2024, 3, 9, 1, 0, 0, 0, loc), loc); hit == nil {
+			t.Errorf("周六凌晨应命中")
+		}
+	})
+
+	t.Run("chained", func(t *testing.T) {
+		event := database.BlackoutWindow{
+			Name:    "发布会",
+			Type:    database.BlackoutTypeRange,
+			Policy:  database.BlackoutPolicyBlock,
+			StartAt: "2024-03-05 07:30",
+			EndAt:   "2024-03-05 12:00",
+		}
+		hit := CheckBlackout([]database.BlackoutWindow{quiet, event}, time.Date(2024, 3, 5, 6, 0, 0, 0, loc), loc)
+		if hit == nil {
+			t.Fatal("expected hit")
+		}
+		if want := time.Date(2024, 3, 5, 12, 0, 0, 0, loc); !hit.Until.Equal(want) {
+			t.Errorf("got %s, want %s", hit.Until, want)
+		}
+		if !hit.Blocked || hit.Window.Name != "夜间静默" {
+			t.Errorf("got window=%s blocked=%v", hit.Window.Name, hit.Blocked)
+		}
+	})
+
+	t.Run("window_timezone", func(t *testing.T) {
+		w := quiet
+		w.TimeZone = "UTC"
+		// 北京时间 23:00 = UTC 15:00，不在 UTC 静默时段内
+		if hit := CheckBlackout([]database.BlackoutWindow{w}, time.Date(2024, 3, 5, 23, 0, 0, 0, loc), loc); hit != nil {
+			t.Errorf("按禁发时段时区不应命中")
+		}
+	})
+}
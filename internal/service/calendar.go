@@ -0,0 +1,235 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/ratelimit"
+	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalendarService 发布日历服务
+type CalendarService struct {
+	db        *gorm.DB
+	schedules *ScheduleService
+	limiter   *ratelimit.LimiterWithStats
+}
+
+// NewCalendarService 创建发布日历服务
+func NewCalendarService(db *gorm.DB, uploads *UploadService, schedules *ScheduleService) *CalendarService {
+	return &CalendarService{
+		db:        db,
+		schedules: schedules,
+		limiter:   uploads.rateLimiter,
+	}
+}
+
+// calendarEntry 日历计算用的中间数据
+type calendarEntry struct {
+	task    database.UploadTask
+	at      time.Time
+	account *types.CalendarAccount
+	item    *types.CalendarItem
+}
+
+// GetCalendar 获取日期范围内（含结束日期）所有已排期和已发布的任务，按账号分组并标记冲突
+func (s *CalendarService) GetCalendar(ctx context.Context, startDate, endDate string) (*types.PublishCalendar, error) {
+	loc := s.schedules.Location(ctx, "")
+	start, err := time.ParseInLocation("2006-01-02", startDate, loc)
+	if err != nil {
+		return nil, fmt.Errorf("开始日期格式错误: %w", err)
+	}
+	end, err := time.ParseInLocation("2006-01-02", endDate, loc)
+	if err != nil {
+		return nil, fmt.Errorf("结束日期格式错误: %w", err)
+	}
+	end = end.AddDate(0, 0, 1)
+	if !end.After(start) {
+		return nil, fmt.Errorf("结束日期不能早于开始日期")
+	}
+
+	var tasks []database.UploadTask
+	if err := s.db.Preload("Video").Preload("Account").
+		Where("status <> ?", config.TaskStatusCancelled).
+		Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("query tasks failed: %w", err)
+	}
+
+	now := time.Now()
+	calendar := &types.PublishCalendar{StartDate: startDate, EndDate: endDate}
+	accounts := make(map[int]*types.CalendarAccount)
+	var entries []*calendarEntry
+
+	for _, task := range tasks {
+		at, ok := s.taskTime(ctx, &task)
+		if !ok || at.Before(start) || !at.Before(end) {
+			continue
+		}
+
+		account, exists := accounts[task.AccountID]
+		if !exists {
+			account = &types.CalendarAccount{
+				AccountID:   task.AccountID,
+				AccountName: task.Account.Name,
+				Platform:    task.Platform,
+				CookieValid: task.Account.Status == config.AccountStatusValid,
+			}
+			accounts[task.AccountID] = account
+		}
+
+		entries = append(entries, &calendarEntry{
+			task:    task,
+			at:      at,
+			account: account,
+			item: &types.CalendarItem{
+				TaskID:       task.ID,
+				VideoID:      task.VideoID,
+				Title:        resolveTaskTitle(&task, &task.Video),
+				Time:         at.In(loc).Format(time.RFC3339),
+				Kind:         calendarKind(&task, at, now),
+				Status:       task.Status,
+				ScheduleMode: task.ScheduleMode,
+			},
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].at.Before(entries[j].at) })
+	calendar.Conflicts = s.detectConflicts(entries, loc, now)
+
+	for _, entry := range entries {
+		entry.account.Items = append(entry.account.Items, *entry.item)
+	}
+	for _, account := range accounts {
+		calendar.Accounts = append(calendar.Accounts, *account)
+	}
+	sort.Slice(calendar.Accounts, func(i, j int) bool {
+		a, b := calendar.Accounts[i], calendar.Accounts[j]
+		if a.Platform != b.Platform {
+			return a.Platform < b.Platform
+		}
+		return a.AccountID < b.AccountID
+	})
+	return calendar, nil
+}
+
+// detectConflicts 检测冲突：同账号间隔过近、超出平台每日上传限制、账号登录失效时仍有待发布任务
+func (s *CalendarService) detectConflicts(entries []*calendarEntry, loc *time.Location, now time.Time) []types.CalendarConflict {
+	var conflicts []types.CalendarConflict
+	spacing := ReleaseSpacing{}
+	lastByAccount := make(map[int]*calendarEntry)
+	dailyCount := make(map[string][]*calendarEntry)
+
+	for _, entry := range entries {
+		if entry.item.Kind == types.CalendarKindFailed {
+			continue
+		}
+		platform := entry.task.Platform
+		date := entry.at.In(loc).Format("2006-01-02")
+
+		if last, ok := lastByAccount[entry.task.AccountID]; ok {
+			gap := spacing.gap(platform)
+			if entry.at.Sub(last.at) < gap {
+				conflicts = append(conflicts, types.CalendarConflict{
+					Type:      types.ConflictTooClose,
+					AccountID: entry.task.AccountID,
+					Platform:  platform,
+					Date:      date,
+					TaskIDs:   []int{last.task.ID, entry.task.ID},
+					Message: fmt.Sprintf("账号 %s 两次发布间隔 %s，少于 %s",
+						entry.account.AccountName, utils.FormatDuration(entry.at.Sub(last.at)), utils.FormatDuration(gap)),
+				})
+				markConflict(last.item, types.ConflictTooClose)
+				markConflict(entry.item, types.ConflictTooClose)
+			}
+		}
+		lastByAccount[entry.task.AccountID] = entry
+
+		key := platform + "|" + date
+		dailyCount[key] = append(dailyCount[key], entry)
+
+		if !entry.account.CookieValid && entry.item.Kind == types.CalendarKindScheduled && entry.at.After(now) {
+			conflicts = append(conflicts, types.CalendarConflict{
+				Type:      types.ConflictCookieExpired,
+				AccountID: entry.task.AccountID,
+				Platform:  platform,
+				Date:      date,
+				TaskIDs:   []int{entry.task.ID},
+				Message:   fmt.Sprintf("账号 %s 登录已失效，定时任务可能无法发布", entry.account.AccountName),
+			})
+			markConflict(entry.item, types.ConflictCookieExpired)
+		}
+	}
+
+	keys := make([]string, 0, len(dailyCount))
+	for key := range dailyCount {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		group := dailyCount[key]
+		platform := group[0].task.Platform
+		limit, ok := s.limiter.GetLimit(platform)
+		if !ok || limit.DailyLimit <= 0 || len(group) <= limit.DailyLimit {
+			continue
+		}
+		ids := make([]int, len(group))
+		for i, entry := range group {
+			ids[i] = entry.task.ID
+			markConflict(entry.item, types.ConflictDailyLimit)
+		}
+		conflicts = append(conflicts, types.CalendarConflict{
+			Type:     types.ConflictDailyLimit,
+			Platform: platform,
+			Date:     group[0].at.In(loc).Format("2006-01-02"),
+			TaskIDs:  ids,
+			Message:  fmt.Sprintf("%s 当天共 %d 条，超出每日上传限制 %d", platform, len(group), limit.DailyLimit),
+		})
+	}
+	return conflicts
+}
+
+// taskTime 任务在日历上的时间：定时任务取定时时间，否则取完成/创建时间
+func (s *CalendarService) taskTime(ctx context.Context, task *database.UploadTask) (time.Time, bool) {
+	if task.ScheduleTime != nil && *task.ScheduleTime != "" {
+		if at, err := utils.ParseScheduleTimeIn(*task.ScheduleTime, s.schedules.AccountLocation(ctx, &task.Account)); err == nil {
+			return at, true
+		}
+	}
+	ts := task.CreatedAt
+	if task.Status == config.TaskStatusSuccess && task.UpdatedAt != "" {
+		ts = task.UpdatedAt
+	}
+	at, err := time.Parse(time.RFC3339, ts)
+	return at, err == nil
+}
+
+// calendarKind 日历条目类型
+func calendarKind(task *database.UploadTask, at, now time.Time) string {
+	switch task.Status {
+	case config.TaskStatusFailed:
+		return types.CalendarKindFailed
+	case config.TaskStatusSuccess:
+		if at.After(now) {
+			return types.CalendarKindScheduled
+		}
+		return types.CalendarKindPublished
+	default:
+		return types.CalendarKindScheduled
+	}
+}
+
+// markConflict 标记条目冲突（去重）
+func markConflict(item *types.CalendarItem, conflict string) {
+	for _, c := range item.Conflicts {
+		if c == conflict {
+			return
+		}
+	}
+	item.Conflicts = append(item.Conflicts, conflict)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/ratelimit"
+	"Fuploader/internal/types"
+	"testing"
+	"time"
+)
+
+func TestDetectConflicts(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	now := time.Date(2024, 3, 5, 9, 0, 0, 0, loc)
+	s := &CalendarService{limiter: ratelimit.NewLimiterWithStats()}
+
+	valid := &types.CalendarAccount{AccountID: 1, AccountName: "a", Platform: "douyin", CookieValid: true}
+	expired := &types.CalendarAccount{AccountID: 2, AccountName: "b", Platform: "douyin"}
+	entry := func(id int, account *types.CalendarAccount, at time.Time) *calendarEntry {
+		return &calendarEntry{
+			task:    database.UploadTask{ID: id, AccountID: account.AccountID, Platform: "douyin", Status: config.TaskStatusPending},
+			at:      at,
+			account: account,
+			item:    &types.CalendarItem{TaskID: id, Kind: types.CalendarKindScheduled},
+		}
+	}
+
+	entries := []*calendarEntry{
+		entry(1, valid, time.Date(2024, 3, 5, 18, 0, 0, 0, loc)),
+		entry(2, valid, time.Date(2024, 3, 5, 18, 5, 0, 0, loc)),
+		entry(3, expired, time.Date(2024, 3, 5, 20, 0, 0, 0, loc)),
+	}
+	conflicts := s.detectConflicts(entries, loc, now)
+
+	found := make(map[string]bool)
+	for _, c := range conflicts {
+		found[c.Type] = true
+	}
+	if !found[types.ConflictTooClose] || !found[types.ConflictCookieExpired] {
+		t.Errorf("冲突检测错误: %+v", conflicts)
+	}
+	if found[types.ConflictDailyLimit] {
+		t.Errorf("未超出每日限制: %+v", conflicts)
+	}
+	if len(entries[0].item.Conflicts) != 1 || len(entries[2].item.Conflicts) != 1 {
+		t.Errorf("条目冲突标记错误: %v %v", entries[0].item.Conflicts, entries[2].item.Conflicts)
+	}
+}
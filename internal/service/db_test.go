@@ -0,0 +1,47 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var testConfigOnce sync.Once
+
+// newTestDB 创建临时 SQLite 数据库并迁移所有数据表
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	// 服务在出错时会写日志，日志文件需要配置目录
+	testConfigOnce.Do(func() {
+		if config.Config == nil {
+			dir, err := os.MkdirTemp("", "fuploader-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			config.Config = &config.AppConfig{LogPath: dir, CookiePath: dir, ExportPath: dir}
+		}
+	})
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
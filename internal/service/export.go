@@ -0,0 +1,290 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/types"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExportService 任务历史导出服务
+type ExportService struct {
+	db *gorm.DB
+}
+
+// NewExportService 创建导出服务
+func NewExportService(db *gorm.DB) *ExportService {
+	return &ExportService{db: db}
+}
+
+// csvHeader CSV 表头
+var csvHeader = []string{
+	"task_id", "platform", "account_id", "account_name", "video_id", "video_filename",
+	"title", "schedule_time", "status", "error_msg", "retry_count", "publish_url",
+	"recording_path", "created_at", "updated_at", "total_ms", "step_durations",
+}
+
+// ExportTaskHistory 按条件导出任务历史到 CSV/JSON 文件
+func (s *ExportService) ExportTaskHistory(ctx context.Context, query types.TaskExportQuery) (*types.TaskExportResult, error) {
+	format := strings.ToLower(query.Format)
+	if format == "" {
+		format = types.ExportFormatCSV
+	}
+	if format != types.ExportFormatCSV && format != types.ExportFormatJSON {
+		return nil, fmt.Errorf("unsupported export format: %s", query.Format)
+	}
+
+	records, err := s.CollectTaskRecords(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	outputPath := query.OutputPath
+	if outputPath == "" {
+		filename := fmt.Sprintf("tasks_%s.%s", time.Now().Format("20060102_150405"), format)
+		outputPath = filepath.Join(config.Config.ExportPath, filename)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return nil, fmt.Errorf("create export dir failed: %w", err)
+	}
+
+	if format == types.ExportFormatJSON {
+		err = writeRecordsJSON(outputPath, records)
+	} else {
+		err = writeRecordsCSV(outputPath, records)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TaskExportResult{
+		Path:   outputPath,
+		Format: format,
+		Count:  len(records),
+	}, nil
+}
+
+// CollectTaskRecords 查询任务并关联视频、账号和上传日志
+func (s *ExportService) CollectTaskRecords(ctx context.Context, query types.TaskExportQuery) ([]types.TaskExportRecord, error) {
+	var startTime, endTime time.Time
+	if query.StartDate != "" {
+		t, err := time.ParseInLocation("2006-01-02", query.StartDate, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date: %w", err)
+		}
+		startTime = t
+	}
+	if query.EndDate != "" {
+		t, err := time.ParseInLocation("2006-01-02", query.EndDate, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date: %w", err)
+		}
+		endTime = t.Add(24 * time.Hour)
+	}
+
+	db := s.db.Preload("Video").Preload("Account").Order("id ASC")
+	if query.Platform != "" {
+		db = db.Where("platform = ?", query.Platform)
+	}
+	if query.AccountID > 0 {
+		db = db.Where("account_id = ?", query.AccountID)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var tasks []database.UploadTask
+	if err := db.Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("query tasks failed: %w", err)
+	}
+
+	// created_at 以 RFC3339 字符串存储，日期范围在内存中比较以避免时区偏差
+	filtered := tasks[:0]
+	for _, task := range tasks {
+		createdAt, err := time.Parse(time.RFC3339, task.CreatedAt)
+		if err != nil {
+			continue
+		}
+		if !startTime.IsZero() && createdAt.Before(startTime) {
+			continue
+		}
+		if !endTime.IsZero() && !createdAt.Before(endTime) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+
+	logsByTask, err := s.loadTaskLogs(filtered)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]types.TaskExportRecord, 0, len(filtered))
+	for _, task := range filtered {
+		records = append(records, buildExportRecord(task, logsByTask[uint(task.ID)]))
+	}
+	return records, nil
+}
+
+// loadTaskLogs 批量加载任务的上传日志，按任务ID分组
+func (s *ExportService) loadTaskLogs(tasks []database.UploadTask) (map[uint][]database.UploadLog, error) {
+	result := make(map[uint][]database.UploadLog)
+	if len(tasks) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, uint(task.ID))
+	}
+
+	// SQLite 单条语句的参数个数有限制，分批查询
+	const batchSize = 500
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var logs []database.UploadLog
+		if err := s.db.Where("task_id IN ?", ids[start:end]).Order("created_at ASC, id ASC").Find(&logs).Error; err != nil {
+			return nil, fmt.Errorf("query upload logs failed: %w", err)
+		}
+		for _, log := range logs {
+			result[log.TaskID] = append(result[log.TaskID], log)
+		}
+	}
+	return result, nil
+}
+
+// buildExportRecord 构建导出记录
+func buildExportRecord(task database.UploadTask, logs []database.UploadLog) types.TaskExportRecord {
+	title := task.Title
+	if title == "" {
+		title = task.Video.Title
+	}
+	if title == "" {
+		title = task.Video.Filename
+	}
+
+	record := types.TaskExportRecord{
+		TaskID:        task.ID,
+		Platform:      task.Platform,
+		AccountID:     task.AccountID,
+		AccountName:   task.Account.Name,
+		VideoID:       task.VideoID,
+		VideoFilename: task.Video.Filename,
+		Title:         title,
+		Status:        task.Status,
+		ErrorMsg:      task.ErrorMsg,
+		RetryCount:    task.RetryCount,
+		PublishURL:    task.PublishURL,
+		RecordingPath: task.RecordingPath,
+		CreatedAt:     task.CreatedAt,
+		UpdatedAt:     task.UpdatedAt,
+		Steps:         stepDurations(logs),
+	}
+	if task.ScheduleTime != nil {
+		record.ScheduleTime = *task.ScheduleTime
+	}
+	for _, step := range record.Steps {
+		record.TotalMs += step.DurationMs
+	}
+	return record
+}
+
+// stepDurations 计算每个步骤的耗时
+// 日志记录了耗时则直接使用，否则以到下一条日志的时间差作为该步骤耗时
+func stepDurations(logs []database.UploadLog) []types.TaskExportStep {
+	steps := make([]types.TaskExportStep, 0, len(logs))
+	for i, log := range logs {
+		duration := log.Duration
+		if duration <= 0 && i+1 < len(logs) {
+			duration = logs[i+1].CreatedAt.Sub(log.CreatedAt).Milliseconds()
+		}
+		if duration < 0 {
+			duration = 0
+		}
+		steps = append(steps, types.TaskExportStep{
+			Step:       log.Step,
+			Status:     log.Status,
+			DurationMs: duration,
+		})
+	}
+	return steps
+}
+
+// writeRecordsJSON 写入 JSON 文件
+func writeRecordsJSON(path string, records []types.TaskExportRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal export records failed: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write export file failed: %w", err)
+	}
+	return nil
+}
+
+// writeRecordsCSV 写入 CSV 文件（带 UTF-8 BOM，便于 Excel 正确识别中文）
+func writeRecordsCSV(path string, records []types.TaskExportRecord) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create export file failed: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString("\xEF\xBB\xBF"); err != nil {
+		return fmt.Errorf("write export file failed: %w", err)
+	}
+
+	w := csv.NewWriter(file)
+	if err := w.Write(csvHeader); err != nil {
+		return fmt.Errorf("write export file failed: %w", err)
+	}
+
+	for _, r := range records {
+		steps := make([]string, 0, len(r.Steps))
+		for _, step := range r.Steps {
+			steps = append(steps, fmt.Sprintf("%s=%dms", step.Step, step.DurationMs))
+		}
+		row := []string{
+			strconv.Itoa(r.TaskID),
+			r.Platform,
+			strconv.Itoa(r.AccountID),
+			r.AccountName,
+			strconv.Itoa(r.VideoID),
+			r.VideoFilename,
+			r.Title,
+			r.ScheduleTime,
+			r.Status,
+			r.ErrorMsg,
+			strconv.Itoa(r.RetryCount),
+			r.PublishURL,
+			r.RecordingPath,
+			r.CreatedAt,
+			r.UpdatedAt,
+			strconv.FormatInt(r.TotalMs, 10),
+			strings.Join(steps, ";"),
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("write export file failed: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("write export file failed: %w", err)
+	}
+	return nil
+}
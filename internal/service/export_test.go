@@ -0,0 +1,191 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"Fuploader/internal/types"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCollectTaskRecords(t *testing.T) {
+	db := newTestDB(t)
+	accounts := []database.Account{{Platform: "douyin", Name: "抖音号"}, {Platform: "bilibili", Name: "B站号"}}
+	videos := []database.Video{{Filename: "a.mp4", FilePath: "/v/a.mp4", Title: "视频标题"}, {Filename: "b.mp4", FilePath: "/v/b.mp4"}}
+	if err := db.Create(&accounts).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&videos).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := []database.UploadTask{
+		{VideoID: videos[0].ID, AccountID: accounts[0].ID, Platform: "douyin", Status: "success"},
+		{VideoID: videos[1].ID, AccountID: accounts[0].ID, Platform: "douyin", Status: "failed"},
+		{VideoID: videos[0].ID, AccountID: accounts[1].ID, Platform: "bilibili", Status: "success", Title: "自定义标题"},
+		{VideoID: videos[0].ID, AccountID: accounts[0].ID, Platform: "douyin", Status: "success"},
+	}
+	if err := db.Create(&tasks).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 最后一个任务创建于很久以前，应被日期范围过滤掉
+	if err := db.Model(&tasks[3]).UpdateColumn("created_at", "2020-01-01T00:00:00Z").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	logs := []database.UploadLog{
+		{TaskID: uint(tasks[0].ID), Step: "open", Status: "success", CreatedAt: base},
+		{TaskID: uint(tasks[0].ID), Step: "upload", Status: "success", Duration: 5000, CreatedAt: base.Add(2 * time.Second)},
+		{TaskID: uint(tasks[0].ID), Step: "publish", Status: "success", CreatedAt: base.Add(9 * time.Second)},
+	}
+	if err := db.Create(&logs).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewExportService(db)
+	records, err := svc.CollectTaskRecords(context.Background(), types.TaskExportQuery{
+		Platform:  "douyin",
+		StartDate: time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("records = %+v, want tasks %d and %d", records, tasks[0].ID, tasks[1].ID)
+	}
+
+	first := records[0]
+	if first.TaskID != tasks[0].ID || first.AccountName != "抖音号" || first.VideoFilename != "a.mp4" || first.Title != "视频标题" {
+		t.Errorf("first record = %+v", first)
+	}
+	wantSteps := []types.TaskExportStep{
+		{Step: "open", Status: "success", DurationMs: 2000},
+		{Step: "upload", Status: "success", DurationMs: 5000},
+		{Step: "publish", Status: "success", DurationMs: 0},
+	}
+	if !reflect.DeepEqual(first.Steps, wantSteps) || first.TotalMs != 7000 {
+		t.Errorf("steps = %+v, total = %d", first.Steps, first.TotalMs)
+	}
+	// 视频没有标题时使用文件名
+	if records[1].Title != "b.mp4" || len(records[1].Steps) != 0 {
+		t.Errorf("second record = %+v", records[1])
+	}
+
+	records, err = svc.CollectTaskRecords(context.Background(), types.TaskExportQuery{AccountID: accounts[1].ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Title != "自定义标题" {
+		t.Errorf("account records = %+v", records)
+	}
+
+	if _, err := svc.CollectTaskRecords(context.Background(), types.TaskExportQuery{StartDate: "2024/03/05"}); err == nil {
+		t.Error("expected invalid start date error")
+	}
+}
+
+func TestStepDurations(t *testing.T) {
+	base := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		logs []database.UploadLog
+		want []int64
+	}{
+		{"empty", nil, []int64{}},
+		{
+			// 未记录耗时的步骤以到下一条日志的时间差计算，最后一步没有下一条日志则为 0
+			"missing durations",
+			[]database.UploadLog{
+				{Step: "open", CreatedAt: base},
+				{Step: "upload", Duration: 1500, CreatedAt: base.Add(time.Second)},
+				{Step: "publish", CreatedAt: base.Add(4 * time.Second)},
+			},
+			[]int64{1000, 1500, 0},
+		},
+		{
+			// 日志时间乱序时差值为负，按 0 处理
+			"unordered",
+			[]database.UploadLog{
+				{Step: "upload", CreatedAt: base.Add(5 * time.Second)},
+				{Step: "open", CreatedAt: base},
+				{Step: "publish", Duration: -10, CreatedAt: base.Add(time.Second)},
+			},
+			[]int64{0, 1000, 0},
+		},
+	}
+	for _, c := range cases {
+		steps := stepDurations(c.logs)
+		got := make([]int64, 0, len(steps))
+		for i, step := range steps {
+			if step.Step != c.logs[i].Step {
+				t.Errorf("%s: steps[%d] = %q, want %q", c.name, i, step.Step, c.logs[i].Step)
+			}
+			got = append(got, step.DurationMs)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: durations = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestWriteRecordsCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.csv")
+	records := []types.TaskExportRecord{{
+		TaskID:      7,
+		Platform:    "douyin",
+		AccountID:   3,
+		AccountName: "带,逗号",
+		Title:       "带\"引号\"的标题\n第二行",
+		ErrorMsg:    "timeout, retry",
+		TotalMs:     3500,
+		Steps: []types.TaskExportStep{
+			{Step: "upload", DurationMs: 3000},
+			{Step: "publish", DurationMs: 500},
+		},
+	}}
+	if err := writeRecordsCSV(path, records); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("\xEF\xBB\xBF")) {
+		t.Fatal("missing UTF-8 BOM")
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data[3:])).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want header and one record", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], csvHeader) {
+		t.Errorf("header = %v", rows[0])
+	}
+
+	row := rows[1]
+	if len(row) != len(csvHeader) {
+		t.Fatalf("row has %d columns, want %d", len(row), len(csvHeader))
+	}
+	want := map[string]string{
+		"task_id":        "7",
+		"account_name":   "带,逗号",
+		"title":          "带\"引号\"的标题\n第二行",
+		"error_msg":      "timeout, retry",
+		"total_ms":       "3500",
+		"step_durations": "upload=3000ms;publish=500ms",
+	}
+	for i, column := range csvHeader {
+		if value, ok := want[column]; ok && row[i] != value {
+			t.Errorf("%s = %q, want %q", column, row[i], value)
+		}
+	}
+}
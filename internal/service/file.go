@@ -78,14 +78,28 @@ func (s *FileService) AddVideo(ctx context.Context, filePath string) (*database.
 	return video, nil
 }
 
+// UpdateVideo 更新视频信息，不修改自定义字段（前端视频表单不包含该字段），自定义字段通过 SetVideoCustomFields 修改
 func (s *FileService) UpdateVideo(ctx context.Context, video *database.Video) error {
-	result := s.db.Save(video)
+	result := s.db.Omit("custom_fields").Save(video)
 	if result.Error != nil {
 		return fmt.Errorf("update video failed: %w", result.Error)
 	}
 	return nil
 }
 
+// SetVideoCustomFields 设置视频自定义字段，fields 为空时清空
+func (s *FileService) SetVideoCustomFields(ctx context.Context, id int, fields map[string]string) error {
+	video, err := s.GetVideoByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	video.CustomFields = fields
+	if err := s.db.Select("custom_fields").Save(video).Error; err != nil {
+		return fmt.Errorf("update video custom fields failed: %w", err)
+	}
+	return nil
+}
+
 func (s *FileService) DeleteVideo(ctx context.Context, id int) error {
 	var video database.Video
 	result := s.db.First(&video, id)
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"context"
+	"testing"
+)
+
+func TestVideoCustomFields(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewFileService(db)
+	ctx := context.Background()
+
+	video := database.Video{Filename: "a.mp4", FilePath: "/v/a.mp4", Tags: []string{"旅行"}, CustomFields: map[string]string{"系列": "周末", "集数": "3"}}
+	if err := db.Create(&video).Error; err != nil {
+		t.Fatal(err)
+	}
+	reload := func() database.Video {
+		var v database.Video
+		if err := db.First(&v, video.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	// 前端编辑视频时不提交自定义字段，不应清空
+	edited := database.Video{ID: video.ID, Filename: "a.mp4", FilePath: "/v/a.mp4", Title: "新标题", Tags: []string{"旅行", "vlog"}}
+	if err := svc.UpdateVideo(ctx, &edited); err != nil {
+		t.Fatal(err)
+	}
+	got := reload()
+	if got.Title != "新标题" || len(got.Tags) != 2 || got.CustomFields["集数"] != "3" {
+		t.Errorf("after edit = title %q, tags %v, custom fields %v", got.Title, got.Tags, got.CustomFields)
+	}
+
+	// 标签全部删除后应保存为空
+	edited.Tags = nil
+	if err := svc.UpdateVideo(ctx, &edited); err != nil {
+		t.Fatal(err)
+	}
+	if got := reload(); len(got.Tags) != 0 || got.TagsJSON != "[]" {
+		t.Errorf("tags not cleared: %v (%q)", got.Tags, got.TagsJSON)
+	}
+
+	if err := svc.SetVideoCustomFields(ctx, video.ID, map[string]string{"系列": "长假"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := reload(); len(got.CustomFields) != 1 || got.CustomFields["系列"] != "长假" || got.Title != "新标题" {
+		t.Errorf("after set = %+v", got)
+	}
+	if err := svc.SetVideoCustomFields(ctx, video.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := reload(); len(got.CustomFields) != 0 || got.CustomFieldsJSON != "{}" {
+		t.Errorf("custom fields not cleared: %v (%q)", got.CustomFields, got.CustomFieldsJSON)
+	}
+	if err := svc.SetVideoCustomFields(ctx, video.ID+100, nil); err == nil {
+		t.Error("expected missing video error")
+	}
+}
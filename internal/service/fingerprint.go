@@ -0,0 +1,103 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/browser"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// FingerprintService 账号固定浏览器指纹，实现 browser.FingerprintStore
+type FingerprintService struct {
+	db *gorm.DB
+}
+
+// NewFingerprintService 创建账号指纹服务
+func NewFingerprintService(db *gorm.DB) *FingerprintService {
+	return &FingerprintService{db: db}
+}
+
+// resolveBrowserAccount 通过浏览器池传入的账号ID或 Cookie 路径定位账号
+func resolveBrowserAccount(db *gorm.DB, accountID uint, cookiePath string) (int, error) {
+	var account database.Account
+	query := db.Select("id")
+	if accountID > 0 {
+		query = query.Where("id = ?", accountID)
+	} else {
+		query = query.Where("cookie_path = ?", cookiePath)
+	}
+	if err := query.Limit(1).Find(&account).Error; err != nil {
+		return 0, fmt.Errorf("query account failed: %w", err)
+	}
+	if account.ID == 0 {
+		return 0, browser.ErrNoAccount
+	}
+	return account.ID, nil
+}
+
+// AccountRegion 账号浏览器上下文的地区：按账号平台默认地区，时区和地理位置取自账号时区
+func (s *FingerprintService) AccountRegion(accountID uint, cookiePath string) (config.BrowserRegion, error) {
+	id, err := resolveBrowserAccount(s.db, accountID, cookiePath)
+	if err != nil {
+		return config.BrowserRegion{}, err
+	}
+	var account database.Account
+	if err := s.db.Select("platform", "time_zone").Where("id = ?", id).First(&account).Error; err != nil {
+		return config.BrowserRegion{}, fmt.Errorf("query account failed: %w", err)
+	}
+	return config.AccountBrowserRegion(account.Platform, account.TimeZone), nil
+}
+
+// LoadFingerprint 读取账号指纹，尚未生成时返回 nil
+func (s *FingerprintService) LoadFingerprint(accountID uint, cookiePath string) (*browser.Fingerprint, error) {
+	id, err := resolveBrowserAccount(s.db, accountID, cookiePath)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetFingerprint(context.Background(), id)
+}
+
+// SaveFingerprint 保存账号指纹
+func (s *FingerprintService) SaveFingerprint(accountID uint, cookiePath string, fp *browser.Fingerprint) error {
+	id, err := resolveBrowserAccount(s.db, accountID, cookiePath)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(fp)
+	if err != nil {
+		return err
+	}
+	record := database.AccountFingerprint{AccountID: id, Profile: string(data)}
+	if err := s.db.Save(&record).Error; err != nil {
+		return fmt.Errorf("save fingerprint failed: %w", err)
+	}
+	return nil
+}
+
+// GetFingerprint 获取账号指纹，尚未生成时返回 nil
+func (s *FingerprintService) GetFingerprint(ctx context.Context, accountID int) (*browser.Fingerprint, error) {
+	var record database.AccountFingerprint
+	if err := s.db.Where("account_id = ?", accountID).Limit(1).Find(&record).Error; err != nil {
+		return nil, fmt.Errorf("query fingerprint failed: %w", err)
+	}
+	if record.Profile == "" {
+		return nil, nil
+	}
+	var fp browser.Fingerprint
+	if err := json.Unmarshal([]byte(record.Profile), &fp); err != nil {
+		return nil, fmt.Errorf("parse fingerprint failed: %w", err)
+	}
+	return &fp, nil
+}
+
+// ResetFingerprint 删除账号指纹，下次创建浏览器上下文时重新生成
+func (s *FingerprintService) ResetFingerprint(ctx context.Context, accountID int) error {
+	if err := s.db.Where("account_id = ?", accountID).Delete(&database.AccountFingerprint{}).Error; err != nil {
+		return fmt.Errorf("delete fingerprint failed: %w", err)
+	}
+	return nil
+}
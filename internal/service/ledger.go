@@ -0,0 +1,141 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/utils"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 限流统计的滚动窗口
+const (
+	ledgerDailyWindow  = 24 * time.Hour
+	ledgerHourlyWindow = time.Hour
+)
+
+// PublishLedger 发布台账：持久化记录实际发布，重启后限流计数不丢失
+type PublishLedger struct {
+	db        *gorm.DB
+	schedules *ScheduleService
+}
+
+// NewPublishLedger 创建发布台账
+func NewPublishLedger(db *gorm.DB, schedules *ScheduleService) *PublishLedger {
+	return &PublishLedger{db: db, schedules: schedules}
+}
+
+// Record 记录一次发布
+func (l *PublishLedger) Record(task *database.UploadTask, publishedAt time.Time, scheduled bool) error {
+	record := database.PublishRecord{
+		TaskID:    task.ID,
+		AccountID: task.AccountID,
+		Platform:  task.Platform,
+		Scheduled: scheduled,
+		// 统一以 UTC 存储，保证 SQLite 中按字符串比较时间的结果正确
+		PublishedAt: publishedAt.UTC(),
+	}
+	if err := l.db.Create(&record).Error; err != nil {
+		return fmt.Errorf("record publish failed: %w", err)
+	}
+	return nil
+}
+
+// Count 统计 (from, to] 内的发布次数，accountID 为 0 时统计整个平台
+func (l *PublishLedger) Count(platform string, accountID int, from, to time.Time) (int64, error) {
+	query := l.db.Model(&database.PublishRecord{}).
+		Where("platform = ? AND published_at > ? AND published_at <= ?", platform, from.UTC(), to.UTC())
+	if accountID > 0 {
+		query = query.Where("account_id = ?", accountID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count publishes failed: %w", err)
+	}
+	return count, nil
+}
+
+// PublishTimes 平台在 (from, to] 内的发布时间（升序）
+func (l *PublishLedger) PublishTimes(platform string, from, to time.Time) ([]time.Time, error) {
+	var records []database.PublishRecord
+	if err := l.db.Where("platform = ? AND published_at > ? AND published_at <= ?", platform, from.UTC(), to.UTC()).
+		Order("published_at ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query publishes failed: %w", err)
+	}
+	times := make([]time.Time, 0, len(records))
+	for _, r := range records {
+		times = append(times, r.PublishedAt)
+	}
+	return times, nil
+}
+
+// AccountPublishTimes 账号在 from 之后的发布时间（升序，含已提交平台定时的未来发布）
+func (l *PublishLedger) AccountPublishTimes(accountID int, from time.Time) ([]time.Time, error) {
+	return l.publishTimesSince(l.db.Where("account_id = ?", accountID), from)
+}
+
+// PlatformPublishTimes 平台在 from 之后的发布时间（升序，含已提交平台定时的未来发布）
+func (l *PublishLedger) PlatformPublishTimes(platform string, from time.Time) ([]time.Time, error) {
+	return l.publishTimesSince(l.db.Where("platform = ?", platform), from)
+}
+
+func (l *PublishLedger) publishTimesSince(query *gorm.DB, from time.Time) ([]time.Time, error) {
+	var records []database.PublishRecord
+	if err := query.Where("published_at > ?", from.UTC()).
+		Order("published_at ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query publishes failed: %w", err)
+	}
+	times := make([]time.Time, 0, len(records))
+	for _, r := range records {
+		times = append(times, r.PublishedAt)
+	}
+	return times, nil
+}
+
+// Backfill 台账为空时从已成功的任务导入历史发布记录（升级前的数据）
+// 平台定时的任务按账号时区解析定时时间
+func (l *PublishLedger) Backfill(ctx context.Context) error {
+	var count int64
+	if err := l.db.Model(&database.PublishRecord{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var tasks []database.UploadTask
+	if err := l.db.Preload("Account").
+		Where("status = ? AND is_draft = ?", config.TaskStatusSuccess, false).Find(&tasks).Error; err != nil {
+		return err
+	}
+	locations := make(map[string]*time.Location)
+	for i := range tasks {
+		task := &tasks[i]
+		publishedAt, err := time.Parse(time.RFC3339, task.UpdatedAt)
+		if err != nil {
+			continue
+		}
+		// 平台定时的任务按定时时间发布
+		scheduled := false
+		if task.ScheduleTime != nil && *task.ScheduleTime != "" && task.ScheduleMode != config.ScheduleModeLocal {
+			loc, ok := locations[task.Account.TimeZone]
+			if !ok {
+				loc = l.schedules.AccountLocation(ctx, &task.Account)
+				locations[task.Account.TimeZone] = loc
+			}
+			if t, err := utils.ParseScheduleTimeIn(*task.ScheduleTime, loc); err == nil {
+				publishedAt, scheduled = t, true
+			}
+		}
+		if err := l.Record(task, publishedAt, scheduled); err != nil {
+			return err
+		}
+	}
+	if len(tasks) > 0 {
+		utils.Info(fmt.Sprintf("[+] 已从历史任务导入 %d 条发布记录", len(tasks)))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPublishLedgerCountAndTimes(t *testing.T) {
+	db := newTestDB(t)
+	ledger := NewPublishLedger(db, NewScheduleService(db))
+	shanghai := time.FixedZone("CST", 8*3600)
+	base := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+
+	records := []struct {
+		task database.UploadTask
+		at   time.Time
+	}{
+		{database.UploadTask{ID: 1, AccountID: 1, Platform: "douyin"}, base.Add(-2 * time.Hour)},
+		// 非 UTC 时间：北京时间 19:30 即 UTC 11:30
+		{database.UploadTask{ID: 2, AccountID: 2, Platform: "douyin"}, time.Date(2024, 3, 5, 19, 30, 0, 0, shanghai)},
+		{database.UploadTask{ID: 3, AccountID: 1, Platform: "douyin"}, base.Add(3 * time.Hour)},
+		{database.UploadTask{ID: 4, AccountID: 1, Platform: "bilibili"}, base.Add(-time.Hour)},
+	}
+	for _, r := range records {
+		if err := ledger.Record(&r.task, r.at, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 查询边界使用其他时区也应得到同样结果
+	count, err := ledger.Count("douyin", 0, base.Add(-time.Hour).In(shanghai), base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("platform count in last hour = %d, want 1", count)
+	}
+	if count, _ := ledger.Count("douyin", 1, base.Add(-ledgerDailyWindow), base); count != 1 {
+		t.Errorf("account count = %d, want 1", count)
+	}
+
+	times, err := ledger.PublishTimes("douyin", base.Add(-ledgerDailyWindow), base.Add(ledgerDailyWindow))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{base.Add(-2 * time.Hour), base.Add(-30 * time.Minute), base.Add(3 * time.Hour)}
+	if len(times) != len(want) {
+		t.Fatalf("times = %v, want %v", times, want)
+	}
+	for i := range want {
+		if !times[i].Equal(want[i]) {
+			t.Errorf("times[%d] = %s, want %s", i, times[i], want[i])
+		}
+	}
+
+	future, err := ledger.AccountPublishTimes(1, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(future) != 1 || !future[0].Equal(base.Add(3*time.Hour)) {
+		t.Errorf("account publishes after base = %v", future)
+	}
+}
+
+func TestPublishLedgerBackfill(t *testing.T) {
+	db := newTestDB(t)
+	ledger := NewPublishLedger(db, NewScheduleService(db))
+
+	account := database.Account{Platform: "douyin", Name: "上海账号", TimeZone: "Asia/Shanghai"}
+	if err := db.Create(&account).Error; err != nil {
+		t.Fatal(err)
+	}
+	scheduleTime := "2024-03-05 20:00"
+	tasks := []database.UploadTask{
+		// 平台定时：按账号时区解析，北京时间 20:00 即 UTC 12:00
+		{AccountID: account.ID, Platform: "douyin", Status: config.TaskStatusSuccess, ScheduleTime: &scheduleTime, ScheduleMode: config.ScheduleModeNative},
+		{AccountID: account.ID, Platform: "douyin", Status: config.TaskStatusSuccess},
+		{AccountID: account.ID, Platform: "douyin", Status: config.TaskStatusSuccess, IsDraft: true},
+		{AccountID: account.ID, Platform: "douyin", Status: config.TaskStatusFailed},
+	}
+	for i := range tasks {
+		if err := db.Create(&tasks[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ledger.Backfill(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var records []database.PublishRecord
+	if err := db.Order("task_id ASC").Find(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("records = %+v, want 2 (drafts and failed tasks skipped)", records)
+	}
+	if want := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC); !records[0].Scheduled || !records[0].PublishedAt.Equal(want) {
+		t.Errorf("scheduled record = %+v, want published at %s", records[0], want)
+	}
+	if records[1].Scheduled {
+		t.Errorf("immediate record should not be scheduled: %+v", records[1])
+	}
+
+	// 台账已有记录时不再重复导入
+	if err := ledger.Backfill(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Model(&database.PublishRecord{}).Count(&count)
+	if count != 2 {
+		t.Errorf("records after second backfill = %d, want 2", count)
+	}
+}
+
+func TestRestoreRateLimitState(t *testing.T) {
+	db := newTestDB(t)
+	s := NewUploadService(db, nil)
+
+	// 抖音令牌桶突发为 3，重启前刚上传过 3 次
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		task := database.UploadTask{ID: i, AccountID: 1, Platform: "douyin"}
+		if err := s.ledger.Record(&task, now.Add(-time.Duration(i)*time.Second), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !s.rateLimiter.Check("douyin") {
+		t.Fatal("fresh limiter should allow uploads")
+	}
+
+	s.RestoreRateLimitState()
+
+	if s.rateLimiter.Check("douyin") {
+		t.Error("restored limiter should have no tokens left")
+	}
+	if !s.rateLimiter.Check("bilibili") {
+		t.Error("platforms without history should be unaffected")
+	}
+	if stats, ok := s.rateLimiter.GetStats("douyin"); ok {
+		t.Errorf("restore should not count stats: %+v", stats)
+	}
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/platformutils"
+	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultPlatformGaps 同平台账号之间默认的最小发布间隔
+var defaultPlatformGaps = map[string]time.Duration{
+	config.PlatformDouyin:      20 * time.Minute,
+	config.PlatformKuaishou:    15 * time.Minute,
+	config.PlatformXiaohongshu: 15 * time.Minute,
+	config.PlatformTencent:     10 * time.Minute,
+	config.PlatformBilibili:    10 * time.Minute,
+	config.PlatformBaijiahao:   10 * time.Minute,
+	config.PlatformTiktok:      15 * time.Minute,
+}
+
+// defaultPlatformGap 未配置平台的默认间隔
+const defaultPlatformGap = 10 * time.Minute
+
+// ReleaseSpacing 错峰发布间隔规则
+type ReleaseSpacing struct {
+	MinGap map[string]time.Duration // 同平台账号之间的最小间隔
+	Jitter time.Duration            // 随机抖动范围（±）
+
+	// Blackout 检查账号在指定时间是否处于禁发时段，为 nil 时不检查
+	Blackout func(account *database.Account, t time.Time) *BlackoutHit
+}
+
+// gap 平台最小间隔
+func (sp ReleaseSpacing) gap(platform string) time.Duration {
+	if d, ok := sp.MinGap[platform]; ok && d > 0 {
+		return d
+	}
+	if d, ok := defaultPlatformGaps[platform]; ok {
+		return d
+	}
+	return defaultPlatformGap
+}
+
+// ReleasePlanner 错峰发布计划：为同一视频的多个账号在发布窗口内分配不同的发布时间
+type ReleasePlanner struct {
+	db        *gorm.DB
+	uploads   *UploadService
+	schedules *ScheduleService
+}
+
+// NewReleasePlanner 创建错峰发布计划服务
+func NewReleasePlanner(db *gorm.DB, uploads *UploadService, schedules *ScheduleService) *ReleasePlanner {
+	return &ReleasePlanner{
+		db:        db,
+		uploads:   uploads,
+		schedules: schedules,
+	}
+}
+
+// Plan 生成错峰发布计划（不创建任务）
+func (p *ReleasePlanner) Plan(ctx context.Context, req types.ReleasePlanRequest) (types.ReleasePlan, error) {
+	if len(req.AccountIDs) == 0 {
+		return types.ReleasePlan{}, fmt.Errorf("至少选择一个账号")
+	}
+	var video database.Video
+	if err := p.db.First(&video, req.VideoID).Error; err != nil {
+		return types.ReleasePlan{}, fmt.Errorf("视频不存在 (ID: %d)", req.VideoID)
+	}
+
+	loc := p.schedules.Location(ctx, "")
+	start, err := utils.ParseScheduleTimeIn(req.WindowStart, loc)
+	if err != nil {
+		return types.ReleasePlan{}, fmt.Errorf("发布窗口开始时间无效: %w", err)
+	}
+	end, err := utils.ParseScheduleTimeIn(req.WindowEnd, loc)
+	if err != nil {
+		return types.ReleasePlan{}, fmt.Errorf("发布窗口结束时间无效: %w", err)
+	}
+	if !end.After(start) {
+		return types.ReleasePlan{}, fmt.Errorf("发布窗口结束时间必须晚于开始时间")
+	}
+
+	var accounts []database.Account
+	for _, id := range req.AccountIDs {
+		var account database.Account
+		if err := p.db.First(&account, id).Error; err != nil {
+			return types.ReleasePlan{}, fmt.Errorf("账号不存在 (ID: %d)", id)
+		}
+		accounts = append(accounts, account)
+	}
+
+	spacing := ReleaseSpacing{
+		MinGap: make(map[string]time.Duration, len(req.MinGapMinutes)),
+		Jitter: time.Duration(req.JitterMinutes) * time.Minute,
+	}
+	for platform, minutes := range req.MinGapMinutes {
+		spacing.MinGap[platform] = time.Duration(minutes) * time.Minute
+	}
+	spacing.Blackout = func(account *database.Account, t time.Time) *BlackoutHit {
+		return p.schedules.Blackouts().Check(account, t, p.schedules.AccountLocation(ctx, account))
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return PlanReleaseTimes(accounts, start, end, time.Now(), spacing, rng), nil
+}
+
+// Create 按错峰发布计划为每个账号创建定时上传任务，返回创建的任务和计划
+func (p *ReleasePlanner) Create(ctx context.Context, req types.ReleasePlanRequest, metadata *UploadTaskMetadata) ([]database.UploadTask, types.ReleasePlan, error) {
+	plan, err := p.Plan(ctx, req)
+	if err != nil {
+		return nil, plan, err
+	}
+
+	var tasks []database.UploadTask
+	for i := range plan.Items {
+		item := &plan.Items[i]
+		if item.Error != "" {
+			continue
+		}
+		scheduleTime := item.ScheduleTime
+		created, err := p.uploads.CreateUploadTask(ctx, req.VideoID, []int{item.AccountID}, &scheduleTime, metadata)
+		if err == nil && len(created) == 0 {
+			err = fmt.Errorf("任务未创建")
+		}
+		if err != nil {
+			item.Error = err.Error()
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: %v", item.AccountName, err))
+			continue
+		}
+		tasks = append(tasks, created...)
+	}
+
+	if len(tasks) == 0 {
+		return nil, plan, fmt.Errorf("没有成功创建任何任务: %s", strings.Join(plan.Warnings, "; "))
+	}
+	utils.Info(fmt.Sprintf("[+] 错峰发布计划已创建 %d 个定时任务", len(tasks)))
+	return tasks, plan, nil
+}
+
+// PlanReleaseTimes 在发布窗口内为各账号分配发布时间：
+// 不同平台交替排列并在窗口内均匀分布，叠加随机抖动后按平台粒度取整；
+// 同平台账号之间保证最小间隔且不落在同一分钟，避开禁发时段，同时满足各平台的最少提前量和最远范围
+func PlanReleaseTimes(accounts []database.Account, start, end, now time.Time, spacing ReleaseSpacing, rng *rand.Rand) types.ReleasePlan {
+	plan := types.ReleasePlan{}
+	ordered := interleaveByPlatform(accounts)
+	n := len(ordered)
+	if n == 0 {
+		return plan
+	}
+
+	span := end.Sub(start)
+	lastByPlatform := make(map[string]time.Time)
+	usedMinutes := make(map[string]map[int64]bool)
+
+	for i, account := range ordered {
+		rule := platformutils.GetScheduleRule(account.Platform)
+		item := types.ReleasePlanItem{
+			AccountID:   account.ID,
+			AccountName: account.Name,
+			Platform:    account.Platform,
+		}
+
+		// 平台允许的最早/最晚时间与窗口取交集
+		earliest := start
+		if min := now.Add(rule.MinLead); min.After(earliest) {
+			earliest = min
+		}
+		latest := end
+		if max := now.Add(rule.MaxHorizon); max.Before(latest) {
+			latest = max
+		}
+
+		// 均匀分布 + 抖动
+		t := start.Add(span * time.Duration(2*i+1) / time.Duration(2*n))
+		if spacing.Jitter > 0 && rng != nil {
+			t = t.Add(time.Duration(rng.Int63n(int64(2*spacing.Jitter))) - spacing.Jitter)
+		}
+		if t.Before(earliest) {
+			t = earliest
+		}
+
+		// 同平台最小间隔
+		if last, ok := lastByPlatform[account.Platform]; ok {
+			if min := last.Add(spacing.gap(account.Platform)); t.Before(min) {
+				t = min
+			}
+		}
+		t = platformutils.CeilTime(t, rule.Granularity)
+
+		used := usedMinutes[account.Platform]
+		if used == nil {
+			used = make(map[int64]bool)
+			usedMinutes[account.Platform] = used
+		}
+		// 同平台不落在同一分钟，并顺延出禁发时段
+		var blocked *BlackoutHit
+		for !t.After(latest) {
+			for used[t.Unix()/60] {
+				t = t.Add(rule.Granularity)
+			}
+			if spacing.Blackout == nil {
+				break
+			}
+			hit := spacing.Blackout(&account, t)
+			if hit == nil {
+				break
+			}
+			if hit.Blocked {
+				blocked = hit
+				break
+			}
+			t = platformutils.CeilTime(hit.Until, rule.Granularity)
+		}
+
+		if blocked != nil {
+			item.Error = blocked.Message()
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: %s", account.Name, item.Error))
+			plan.Items = append(plan.Items, item)
+			continue
+		}
+		if t.After(latest) {
+			item.Error = fmt.Sprintf("发布窗口内无法满足间隔要求（最晚 %s）", latest.Format(platformutils.PageTimeFormat))
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: %s", account.Name, item.Error))
+			plan.Items = append(plan.Items, item)
+			continue
+		}
+
+		used[t.Unix()/60] = true
+		lastByPlatform[account.Platform] = t
+		item.ScheduleTime = t.Format(time.RFC3339)
+		plan.Items = append(plan.Items, item)
+	}
+
+	sort.SliceStable(plan.Items, func(i, j int) bool {
+		a, b := plan.Items[i].ScheduleTime, plan.Items[j].ScheduleTime
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		return a < b
+	})
+	return plan
+}
+
+// interleaveByPlatform 按平台交替排列账号，避免同平台账号集中在窗口同一段
+func interleaveByPlatform(accounts []database.Account) []database.Account {
+	var platforms []string
+	groups := make(map[string][]database.Account)
+	for _, account := range accounts {
+		if _, ok := groups[account.Platform]; !ok {
+			platforms = append(platforms, account.Platform)
+		}
+		groups[account.Platform] = append(groups[account.Platform], account)
+	}
+
+	result := make([]database.Account, 0, len(accounts))
+	for len(result) < len(accounts) {
+		for _, platform := range platforms {
+			if group := groups[platform]; len(group) > 0 {
+				result = append(result, group[0])
+				groups[platform] = group[1:]
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPlanReleaseTimes(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	now := time.Date(2024, 3, 5, 9, 0, 0, 0, loc)
+	start := time.Date(2024, 3, 5, 18, 0, 0, 0, loc)
+	end := time.Date(2024, 3, 5, 22, 0, 0, 0, loc)
+
+	var accounts []database.Account
+	for i := 1; i <= 6; i++ {
+		platform := "douyin"
+		if i > 4 {
+			platform = "tiktok"
+		}
+		accounts = append(accounts, database.Account{ID: i, Name: platform, Platform: platform})
+	}
+
+	spacing := ReleaseSpacing{Jitter: 10 * time.Minute}
+	plan := PlanReleaseTimes(accounts, start, end, now, spacing, rand.New(rand.NewSource(1)))
+	if len(plan.Items) != 6 || len(plan.Warnings) != 0 {
+		t.Fatalf("应为所有账号分配时间: %+v", plan)
+	}
+
+	last := make(map[string]time.Time)
+	for _, item := range plan.Items {
+		at, err := time.Parse(time.RFC3339, item.ScheduleTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if at.Before(start) || at.After(end) {
+			t.Errorf("时间超出窗口: %s", item.ScheduleTime)
+		}
+		if item.Platform == "tiktok" && at.Minute()%5 != 0 {
+			t.Errorf("TikTok 应为 5 分钟粒度: %s", item.ScheduleTime)
+		}
+		if prev, ok := last[item.Platform]; ok && at.Sub(prev) < spacing.gap(item.Platform) {
+			t.Errorf("%s 间隔不足: %s → %s", item.Platform, prev, at)
+		}
+		last[item.Platform] = at
+	}
+
+	t.Run("window_too_small", func(t *testing.T) {
+		narrow := start.Add(30 * time.Minute)
+		plan := PlanReleaseTimes(accounts[:4], start, narrow, now, ReleaseSpacing{}, nil)
+		if len(plan.Warnings) == 0 {
+			t.Error("窗口不足时应给出警告")
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"Fuploader/internal/utils"
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// PresetService 发布预设服务
+type PresetService struct {
+	db *gorm.DB
+}
+
+// NewPresetService 创建发布预设服务
+func NewPresetService(db *gorm.DB) *PresetService {
+	return &PresetService{db: db}
+}
+
+// GetPresets 获取发布预设，platform 不为空时只返回该平台及通用预设
+func (s *PresetService) GetPresets(ctx context.Context, platform string) ([]database.PublishPreset, error) {
+	var presets []database.PublishPreset
+	query := s.db.Order("id ASC")
+	if platform != "" {
+		query = query.Where("platform = ? OR platform = ''", platform)
+	}
+	if err := query.Find(&presets).Error; err != nil {
+		return nil, fmt.Errorf("query presets failed: %w", err)
+	}
+	return presets, nil
+}
+
+// GetPresetByID 根据ID获取发布预设
+func (s *PresetService) GetPresetByID(ctx context.Context, id int) (*database.PublishPreset, error) {
+	var preset database.PublishPreset
+	if err := s.db.First(&preset, id).Error; err != nil {
+		return nil, fmt.Errorf("preset not found: %w", err)
+	}
+	return &preset, nil
+}
+
+// CreatePreset 创建发布预设
+func (s *PresetService) CreatePreset(ctx context.Context, preset *database.PublishPreset) error {
+	if err := validatePreset(preset); err != nil {
+		return err
+	}
+	preset.ID = 0
+	if err := s.db.Create(preset).Error; err != nil {
+		return fmt.Errorf("create preset failed: %w", err)
+	}
+	return nil
+}
+
+// UpdatePreset 更新发布预设
+func (s *PresetService) UpdatePreset(ctx context.Context, preset *database.PublishPreset) error {
+	if err := validatePreset(preset); err != nil {
+		return err
+	}
+	existing, err := s.GetPresetByID(ctx, preset.ID)
+	if err != nil {
+		return err
+	}
+	preset.CreatedAt = existing.CreatedAt
+	if err := s.db.Save(preset).Error; err != nil {
+		return fmt.Errorf("update preset failed: %w", err)
+	}
+	return nil
+}
+
+// DeletePreset 删除发布预设，并清除引用它的账号默认预设
+func (s *PresetService) DeletePreset(ctx context.Context, id int) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&database.PublishPreset{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("delete preset failed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("preset not found")
+		}
+		if err := tx.Model(&database.Account{}).Where("default_preset_id = ?", id).
+			UpdateColumn("default_preset_id", 0).Error; err != nil {
+			return fmt.Errorf("clear account default preset failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetAccountDefaultPreset 设置账号默认预设，presetID 为 0 表示取消
+func (s *PresetService) SetAccountDefaultPreset(ctx context.Context, accountID, presetID int) error {
+	var account database.Account
+	if err := s.db.First(&account, accountID).Error; err != nil {
+		return fmt.Errorf("account not found: %w", err)
+	}
+	if presetID > 0 {
+		preset, err := s.GetPresetByID(ctx, presetID)
+		if err != nil {
+			return err
+		}
+		if preset.Platform != "" && preset.Platform != account.Platform {
+			return fmt.Errorf("预设 %s 仅适用于 %s 平台", preset.Name, preset.Platform)
+		}
+	}
+	if err := s.db.Model(&account).UpdateColumn("default_preset_id", presetID).Error; err != nil {
+		return fmt.Errorf("update account default preset failed: %w", err)
+	}
+	return nil
+}
+
+// ResolveMetadata 计算账号实际使用的元数据：任务指定的预设优先，否则使用账号默认预设，
+// 再叠加任务中填写的字段。返回使用的预设ID（未使用预设时为 0）
+func (s *PresetService) ResolveMetadata(account *database.Account, metadata *UploadTaskMetadata) (*UploadTaskMetadata, int) {
+	presetID := account.DefaultPresetID
+	if metadata != nil && metadata.PresetID > 0 {
+		presetID = metadata.PresetID
+	}
+	if presetID == 0 {
+		return metadata, 0
+	}
+
+	preset, err := s.GetPresetByID(context.Background(), presetID)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 发布预设 %d 不存在，忽略预设", presetID))
+		return metadata, 0
+	}
+	if preset.Platform != "" && preset.Platform != account.Platform {
+		utils.Warn(fmt.Sprintf("[-] 发布预设 %s 不适用于 %s 平台，忽略预设", preset.Name, account.Platform))
+		return metadata, 0
+	}
+
+	merged := metadata.MergeOnto(preset.Metadata)
+	return &merged, preset.ID
+}
+
+// validatePreset 校验发布预设
+func validatePreset(preset *database.PublishPreset) error {
+	preset.Name = strings.TrimSpace(preset.Name)
+	if preset.Name == "" {
+		return fmt.Errorf("预设名称不能为空")
+	}
+	// 预设本身不能再引用其他预设
+	preset.Metadata.PresetID = 0
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"Fuploader/internal/types"
+	"encoding/json"
+	"testing"
+)
+
+func TestMetadataMergeOnto(t *testing.T) {
+	yes := true
+	preset := types.UploadTaskMetadata{
+		Common: types.CommonMetadata{Description: "{series} 持续更新"},
+		Platforms: map[string]types.PlatformFields{
+			"douyin":   {IsOriginal: &yes, AllowDownload: &yes, Collection: "周末出发"},
+			"bilibili": {Copyright: "1", Category: "生活"},
+		},
+	}
+
+	t.Run("empty_fields_keep_preset", func(t *testing.T) {
+		// 前端总是提交所有字段，未填写的为空字符串
+		var metadata UploadTaskMetadata
+		data := `{"common":{"title":"第12集","description":""},"scheduleMode":"",` +
+			`"platforms":{"douyin":{"title":"","collection":"","allowDownload":false},"bilibili":{"copyright":"","category":""}}}`
+		if err := json.Unmarshal([]byte(data), &metadata); err != nil {
+			t.Fatal(err)
+		}
+		merged := metadata.MergeOnto(preset)
+		if merged.Common.Title != "第12集" || merged.Common.Description != "{series} 持续更新" {
+			t.Errorf("通用字段合并错误: %+v", merged.Common)
+		}
+		douyin := merged.Platforms["douyin"]
+		if douyin.IsOriginal == nil || !*douyin.IsOriginal || douyin.Collection != "周末出发" {
+			t.Errorf("未填写的字段应保留预设: %+v", douyin)
+		}
+		if douyin.AllowDownload == nil || *douyin.AllowDownload {
+			t.Errorf("显式填写的 false 应覆盖预设: %+v", douyin)
+		}
+		if bilibili := merged.Platforms["bilibili"]; bilibili.Copyright != "1" || bilibili.Category != "生活" {
+			t.Errorf("空字符串不应覆盖预设: %+v", bilibili)
+		}
+		if !*preset.Platforms["douyin"].AllowDownload {
+			t.Errorf("合并不应修改预设本身")
+		}
+	})
+
+	t.Run("code_built_metadata", func(t *testing.T) {
+		metadata := &UploadTaskMetadata{
+			TagSetID:  3,
+			Platforms: map[string]types.PlatformFields{"bilibili": {Category: "旅行"}},
+		}
+		merged := metadata.MergeOnto(preset)
+		if merged.TagSetID != 3 || merged.Platforms["bilibili"].Category != "旅行" || merged.Platforms["bilibili"].Copyright != "1" {
+			t.Errorf("合并结果错误: %+v", merged)
+		}
+	})
+
+	t.Run("nil_metadata_uses_preset", func(t *testing.T) {
+		var metadata *UploadTaskMetadata
+		merged := metadata.MergeOnto(preset)
+		if merged.Platforms["bilibili"].Copyright != "1" {
+			t.Errorf("应使用预设: %+v", merged)
+		}
+	})
+}
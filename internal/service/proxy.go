@@ -0,0 +1,292 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/vault"
+	"Fuploader/internal/types"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"gorm.io/gorm"
+)
+
+// proxyCheckURLs 代理健康检查使用的出口 IP 查询地址，依次尝试
+var proxyCheckURLs = []string{
+	"https://api.ipify.org",
+	"https://myip.ipip.net",
+}
+
+// proxyCheckTimeout 单次代理检查请求超时
+const proxyCheckTimeout = 15 * time.Second
+
+var ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+
+// ProxyService 账号代理：配置、健康检查，并为浏览器池提供账号代理（实现 browser.ProxyProvider）
+type ProxyService struct {
+	db *gorm.DB
+}
+
+// NewProxyService 创建账号代理服务
+func NewProxyService(db *gorm.DB) *ProxyService {
+	return &ProxyService{db: db}
+}
+
+// GetProxies 获取所有账号代理配置（不返回密码）
+func (s *ProxyService) GetProxies(ctx context.Context) ([]database.AccountProxy, error) {
+	var proxies []database.AccountProxy
+	if err := s.db.Order("account_id").Find(&proxies).Error; err != nil {
+		return nil, fmt.Errorf("query account proxies failed: %w", err)
+	}
+	for i := range proxies {
+		maskPassword(&proxies[i])
+	}
+	return proxies, nil
+}
+
+// GetProxy 获取账号代理配置（不返回密码），未配置时返回 nil
+func (s *ProxyService) GetProxy(ctx context.Context, accountID int) (*database.AccountProxy, error) {
+	proxy, err := s.findProxy(accountID)
+	if proxy != nil {
+		maskPassword(proxy)
+	}
+	return proxy, err
+}
+
+// findProxy 读取账号代理配置，Password 为数据库中保存的值，未配置时返回 nil
+func (s *ProxyService) findProxy(accountID int) (*database.AccountProxy, error) {
+	var proxies []database.AccountProxy
+	if err := s.db.Where("account_id = ?", accountID).Limit(1).Find(&proxies).Error; err != nil {
+		return nil, fmt.Errorf("query account proxy failed: %w", err)
+	}
+	if len(proxies) == 0 {
+		return nil, nil
+	}
+	return &proxies[0], nil
+}
+
+// loadProxy 读取账号代理配置并解密密码，供浏览器池和健康检查使用
+func (s *ProxyService) loadProxy(accountID int) (*database.AccountProxy, error) {
+	proxy, err := s.findProxy(accountID)
+	if err != nil || proxy == nil {
+		return proxy, err
+	}
+	if proxy.Password, err = vault.DecryptString(proxy.Password); err != nil {
+		return nil, fmt.Errorf("decrypt proxy password failed: %w", err)
+	}
+	return proxy, nil
+}
+
+// maskPassword 返回给前端前移除密码
+func maskPassword(proxy *database.AccountProxy) {
+	proxy.HasPassword = proxy.Password != ""
+	proxy.Password = ""
+}
+
+// MigratePasswords 一次性加密旧版本保存的明文代理密码，返回迁移的数量
+func (s *ProxyService) MigratePasswords(ctx context.Context) (int, error) {
+	var proxies []database.AccountProxy
+	if err := s.db.Where("password <> ''").Find(&proxies).Error; err != nil {
+		return 0, fmt.Errorf("query account proxies failed: %w", err)
+	}
+	count := 0
+	for _, proxy := range proxies {
+		if vault.IsEncryptedString(proxy.Password) {
+			continue
+		}
+		sealed, err := vault.EncryptString(proxy.Password)
+		if err != nil {
+			return count, err
+		}
+		if err := s.db.Model(&database.AccountProxy{}).Where("account_id = ?", proxy.AccountID).
+			UpdateColumn("password", sealed).Error; err != nil {
+			return count, fmt.Errorf("update account proxy failed: %w", err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// SetProxy 设置账号代理，修改配置后清空上次检查结果
+// 密码为空时保留已保存的密码（ClearPassword 为 true 或未设置用户名时清除），保存前用凭据库加密
+func (s *ProxyService) SetProxy(ctx context.Context, proxy *database.AccountProxy) error {
+	proxy.Type = strings.ToLower(strings.TrimSpace(proxy.Type))
+	proxy.Host = strings.TrimSpace(proxy.Host)
+	if err := validateProxy(proxy); err != nil {
+		return err
+	}
+	var count int64
+	s.db.Model(&database.Account{}).Where("id = ?", proxy.AccountID).Count(&count)
+	if count == 0 {
+		return fmt.Errorf("account not found")
+	}
+
+	switch {
+	case proxy.Username == "" || proxy.ClearPassword:
+		proxy.Password = ""
+	case proxy.Password == "":
+		existing, err := s.findProxy(proxy.AccountID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			proxy.Password = existing.Password
+		}
+	}
+	if !vault.IsEncryptedString(proxy.Password) {
+		sealed, err := vault.EncryptString(proxy.Password)
+		if err != nil {
+			return fmt.Errorf("encrypt proxy password failed: %w", err)
+		}
+		proxy.Password = sealed
+	}
+
+	proxy.LastIP, proxy.LastCheckedAt, proxy.LastError = "", "", ""
+	if err := s.db.Save(proxy).Error; err != nil {
+		return fmt.Errorf("save account proxy failed: %w", err)
+	}
+	maskPassword(proxy)
+	return nil
+}
+
+// DeleteProxy 删除账号代理，之后该账号直连
+func (s *ProxyService) DeleteProxy(ctx context.Context, accountID int) error {
+	if err := s.db.Where("account_id = ?", accountID).Delete(&database.AccountProxy{}).Error; err != nil {
+		return fmt.Errorf("delete account proxy failed: %w", err)
+	}
+	return nil
+}
+
+// AccountProxy 浏览器池创建上下文时获取账号代理，未配置或已停用时返回 nil
+func (s *ProxyService) AccountProxy(accountID uint, cookiePath string) (*playwright.Proxy, error) {
+	id, err := resolveBrowserAccount(s.db, accountID, cookiePath)
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := s.loadProxy(id)
+	if err != nil || proxy == nil || !proxy.Enabled {
+		return nil, err
+	}
+
+	result := &playwright.Proxy{Server: proxy.Server()}
+	if proxy.Username != "" {
+		result.Username = playwright.String(proxy.Username)
+		result.Password = playwright.String(proxy.Password)
+	}
+	return result, nil
+}
+
+// CheckProxy 检查账号代理是否可用并获取出口 IP，结果保存到代理配置
+// 账号未配置代理或已停用时返回 nil
+func (s *ProxyService) CheckProxy(ctx context.Context, accountID int) (*types.ProxyCheckResult, error) {
+	proxy, err := s.loadProxy(accountID)
+	if err != nil || proxy == nil || !proxy.Enabled {
+		return nil, err
+	}
+
+	start := time.Now()
+	ip, checkErr := checkProxyExit(ctx, proxy)
+	result := &types.ProxyCheckResult{
+		AccountID: accountID,
+		Server:    proxy.Server(),
+		OK:        checkErr == nil,
+		IP:        ip,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: time.Now().Format(time.RFC3339),
+	}
+	if checkErr != nil {
+		result.Error = checkErr.Error()
+	}
+
+	s.db.Model(&database.AccountProxy{}).Where("account_id = ?", accountID).UpdateColumns(map[string]interface{}{
+		"last_ip":         result.IP,
+		"last_checked_at": result.CheckedAt,
+		"last_error":      result.Error,
+	})
+	return result, nil
+}
+
+// validateProxy 校验代理配置
+func validateProxy(proxy *database.AccountProxy) error {
+	switch proxy.Type {
+	case database.ProxyTypeHTTP, database.ProxyTypeHTTPS, database.ProxyTypeSOCKS5:
+	default:
+		return fmt.Errorf("不支持的代理类型: %s（支持 http/https/socks5）", proxy.Type)
+	}
+	if proxy.Host == "" || strings.ContainsAny(proxy.Host, "/ @") {
+		return fmt.Errorf("代理主机无效: %q", proxy.Host)
+	}
+	if proxy.Port <= 0 || proxy.Port > 65535 {
+		return fmt.Errorf("代理端口无效: %d", proxy.Port)
+	}
+	if proxy.Username == "" && proxy.Password != "" {
+		return fmt.Errorf("设置了代理密码但未设置用户名")
+	}
+	return nil
+}
+
+// checkProxyExit 经代理请求出口 IP 查询地址，任一地址返回有效 IP 即视为可用
+func checkProxyExit(ctx context.Context, proxy *database.AccountProxy) (string, error) {
+	proxyURL := &url.URL{
+		Scheme: proxy.Type,
+		Host:   net.JoinHostPort(proxy.Host, strconv.Itoa(proxy.Port)),
+	}
+	if proxy.Username != "" {
+		proxyURL.User = url.UserPassword(proxy.Username, proxy.Password)
+	}
+	client := &http.Client{
+		Timeout:   proxyCheckTimeout,
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+	}
+	defer client.CloseIdleConnections()
+
+	var lastErr error
+	for _, checkURL := range proxyCheckURLs {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("%s 返回 HTTP %d", checkURL, resp.StatusCode)
+			continue
+		}
+		if ip := parseExitIP(string(body)); ip != "" {
+			return ip, nil
+		}
+		lastErr = fmt.Errorf("%s 未返回有效 IP", checkURL)
+	}
+	return "", fmt.Errorf("代理不可用: %w", lastErr)
+}
+
+// parseExitIP 从出口 IP 查询结果中提取 IP（纯文本 IP 或包含 IPv4 的说明文字）
+func parseExitIP(body string) string {
+	body = strings.TrimSpace(body)
+	if ip := net.ParseIP(body); ip != nil {
+		return ip.String()
+	}
+	for _, candidate := range ipv4Pattern.FindAllString(body, -1) {
+		if ip := net.ParseIP(candidate); ip != nil {
+			return ip.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/vault"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestParseExitIP(t *testing.T) {
+	cases := map[string]string{
+		"203.0.113.7\n": "203.0.113.7",
+		"当前 IP：198.51.100.23  来自于：中国 北京": "198.51.100.23",
+		"2001:db8::1":          "2001:db8::1",
+		"999.1.1.1":            "",
+		"<html>blocked</html>": "",
+	}
+	for body, want := range cases {
+		if got := parseExitIP(body); got != want {
+			t.Errorf("parseExitIP(%q) = %q, want %q", body, got, want)
+		}
+	}
+}
+
+func TestValidateProxy(t *testing.T) {
+	valid := database.AccountProxy{Type: database.ProxyTypeSOCKS5, Host: "127.0.0.1", Port: 1080, Username: "u", Password: "p"}
+	if err := validateProxy(&valid); err != nil {
+		t.Fatalf("valid proxy rejected: %v", err)
+	}
+	if got := valid.Server(); got != "socks5://127.0.0.1:1080" {
+		t.Errorf("Server() = %q", got)
+	}
+
+	invalid := []database.AccountProxy{
+		{Type: "ftp", Host: "127.0.0.1", Port: 21},
+		{Type: database.ProxyTypeHTTP, Host: "http://127.0.0.1", Port: 8080},
+		{Type: database.ProxyTypeHTTP, Host: "127.0.0.1", Port: 70000},
+		{Type: database.ProxyTypeHTTP, Host: "127.0.0.1", Port: 8080, Password: "p"},
+	}
+	for _, p := range invalid {
+		if err := validateProxy(&p); err == nil {
+			t.Errorf("expected error for %+v", p)
+		}
+	}
+}
+
+func TestCheckProxyExit(t *testing.T) {
+	// 模拟 HTTP 代理：校验代理认证后直接返回出口 IP
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		w.Write([]byte("当前 IP：203.0.113.9 来自于：测试"))
+	}))
+	defer proxy.Close()
+
+	saved := proxyCheckURLs
+	proxyCheckURLs = []string{"http://ip.example.test/"}
+	defer func() { proxyCheckURLs = saved }()
+
+	u, _ := url.Parse(proxy.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+	cfg := &database.AccountProxy{Type: database.ProxyTypeHTTP, Host: host, Port: port, Username: "user", Password: "secret"}
+
+	ip, err := checkProxyExit(context.Background(), cfg)
+	if err != nil || ip != "203.0.113.9" {
+		t.Fatalf("got %q, %v", ip, err)
+	}
+
+	cfg.Username, cfg.Password = "", ""
+	if _, err := checkProxyExit(context.Background(), cfg); err == nil {
+		t.Error("expected error without proxy credentials")
+	}
+}
+
+func TestProxyPasswordEncrypted(t *testing.T) {
+	db := newTestDB(t)
+	t.Setenv(vault.PassphraseEnv, "test-passphrase")
+	if err := vault.Init(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewProxyService(db)
+	ctx := context.Background()
+
+	account := database.Account{Platform: "douyin", Name: "代理账号"}
+	if err := db.Create(&account).Error; err != nil {
+		t.Fatal(err)
+	}
+	stored := func() string {
+		var proxy database.AccountProxy
+		if err := db.First(&proxy, "account_id = ?", account.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return proxy.Password
+	}
+
+	proxy := database.AccountProxy{AccountID: account.ID, Enabled: true, Type: "http", Host: "127.0.0.1", Port: 8080, Username: "u", Password: "secret"}
+	if err := svc.SetProxy(ctx, &proxy); err != nil {
+		t.Fatal(err)
+	}
+	if raw := stored(); raw == "secret" || !vault.IsEncryptedString(raw) {
+		t.Fatalf("数据库中的密码未加密: %q", raw)
+	}
+
+	// 读取配置时不返回密码
+	proxies, err := svc.GetProxies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proxies) != 1 || proxies[0].Password != "" || !proxies[0].HasPassword {
+		t.Fatalf("proxies = %+v", proxies)
+	}
+
+	// 编辑其他字段时密码为空，保留原密码
+	edited := proxies[0]
+	edited.Port = 8081
+	if err := svc.SetProxy(ctx, &edited); err != nil {
+		t.Fatal(err)
+	}
+	pw, err := svc.AccountProxy(uint(account.ID), "")
+	if err != nil || pw == nil || pw.Password == nil || *pw.Password != "secret" || pw.Server != "http://127.0.0.1:8081" {
+		t.Fatalf("browser proxy = %+v, err = %v", pw, err)
+	}
+
+	edited.ClearPassword = true
+	if err := svc.SetProxy(ctx, &edited); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := svc.GetProxy(ctx, account.ID); got.HasPassword || stored() != "" {
+		t.Errorf("密码未清除: %+v", got)
+	}
+
+	// 旧版本保存的明文密码迁移后加密
+	if err := db.Model(&database.AccountProxy{}).Where("account_id = ?", account.ID).UpdateColumn("password", "legacy").Error; err != nil {
+		t.Fatal(err)
+	}
+	if count, err := svc.MigratePasswords(ctx); err != nil || count != 1 {
+		t.Fatalf("migrated %d, err = %v", count, err)
+	}
+	if raw := stored(); !vault.IsEncryptedString(raw) {
+		t.Errorf("迁移后密码未加密: %q", raw)
+	}
+	if pw, _ := svc.AccountProxy(uint(account.ID), ""); pw == nil || *pw.Password != "legacy" {
+		t.Errorf("迁移后的密码 = %+v", pw)
+	}
+}
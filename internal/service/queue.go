@@ -0,0 +1,412 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QueueService 内容队列服务
+type QueueService struct {
+	db        *gorm.DB
+	uploads   *UploadService
+	schedules *ScheduleService
+}
+
+// NewQueueService 创建内容队列服务
+func NewQueueService(db *gorm.DB, uploads *UploadService, schedules *ScheduleService) *QueueService {
+	return &QueueService{
+		db:        db,
+		uploads:   uploads,
+		schedules: schedules,
+	}
+}
+
+// GetQueues 获取所有内容队列
+func (s *QueueService) GetQueues(ctx context.Context) ([]database.ContentQueue, error) {
+	var queues []database.ContentQueue
+	if err := s.db.Order("id ASC").Find(&queues).Error; err != nil {
+		return nil, fmt.Errorf("query queues failed: %w", err)
+	}
+	return queues, nil
+}
+
+// GetQueueByID 根据ID获取内容队列
+func (s *QueueService) GetQueueByID(ctx context.Context, id int) (*database.ContentQueue, error) {
+	var queue database.ContentQueue
+	if err := s.db.First(&queue, id).Error; err != nil {
+		return nil, fmt.Errorf("queue not found: %w", err)
+	}
+	return &queue, nil
+}
+
+// CreateQueue 创建内容队列
+func (s *QueueService) CreateQueue(ctx context.Context, queue *database.ContentQueue) error {
+	if err := validateQueue(queue); err != nil {
+		return err
+	}
+	queue.ID = 0
+	if err := s.db.Create(queue).Error; err != nil {
+		return fmt.Errorf("create queue failed: %w", err)
+	}
+	return nil
+}
+
+// UpdateQueue 更新内容队列配置（不影响条目）
+func (s *QueueService) UpdateQueue(ctx context.Context, queue *database.ContentQueue) error {
+	if err := validateQueue(queue); err != nil {
+		return err
+	}
+	existing, err := s.GetQueueByID(ctx, queue.ID)
+	if err != nil {
+		return err
+	}
+	queue.CreatedAt = existing.CreatedAt
+	if err := s.db.Save(queue).Error; err != nil {
+		return fmt.Errorf("update queue failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteQueue 删除内容队列及其条目
+func (s *QueueService) DeleteQueue(ctx context.Context, id int) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&database.ContentQueue{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("delete queue failed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("queue not found")
+		}
+		return tx.Where("queue_id = ?", id).Delete(&database.QueueItem{}).Error
+	})
+}
+
+// SetQueuePaused 暂停/恢复内容队列
+func (s *QueueService) SetQueuePaused(ctx context.Context, id int, paused bool) error {
+	result := s.db.Model(&database.ContentQueue{}).Where("id = ?", id).Update("paused", paused)
+	if result.Error != nil {
+		return fmt.Errorf("update queue failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("queue not found")
+	}
+	return nil
+}
+
+// GetQueueItems 获取队列条目，pendingOnly 为 true 时只返回待发布条目
+func (s *QueueService) GetQueueItems(ctx context.Context, queueID int, pendingOnly bool) ([]database.QueueItem, error) {
+	var items []database.QueueItem
+	query := s.db.Preload("Video").Where("queue_id = ?", queueID)
+	if pendingOnly {
+		query = query.Where("status = ?", database.QueueItemPending)
+	}
+	if err := query.Order("position ASC, id ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("query queue items failed: %w", err)
+	}
+	return items, nil
+}
+
+// AddVideos 向队列添加视频，atFront 为 true 时插入到队首（用于紧急内容）
+func (s *QueueService) AddVideos(ctx context.Context, queueID int, videoIDs []int, atFront bool) ([]database.QueueItem, error) {
+	if _, err := s.GetQueueByID(ctx, queueID); err != nil {
+		return nil, err
+	}
+	if len(videoIDs) == 0 {
+		return nil, fmt.Errorf("请选择要加入队列的视频")
+	}
+
+	var items []database.QueueItem
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var bound struct{ Min, Max int }
+		if err := tx.Model(&database.QueueItem{}).Where("queue_id = ?", queueID).
+			Select("COALESCE(MIN(position), 0) AS min, COALESCE(MAX(position), 0) AS max").
+			Scan(&bound).Error; err != nil {
+			return err
+		}
+
+		for i, videoID := range videoIDs {
+			var count int64
+			if err := tx.Model(&database.Video{}).Where("id = ?", videoID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return fmt.Errorf("视频不存在 (ID: %d)", videoID)
+			}
+
+			// 插入队首时保持传入顺序：第一个视频位置最小
+			position := bound.Max + i + 1
+			if atFront {
+				position = bound.Min - len(videoIDs) + i
+			}
+			item := database.QueueItem{
+				QueueID:  queueID,
+				VideoID:  videoID,
+				Position: position,
+				Status:   database.QueueItemPending,
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("add queue items failed: %w", err)
+	}
+	return items, nil
+}
+
+// RemoveItem 从队列移除条目
+func (s *QueueService) RemoveItem(ctx context.Context, itemID int) error {
+	result := s.db.Delete(&database.QueueItem{}, itemID)
+	if result.Error != nil {
+		return fmt.Errorf("delete queue item failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("queue item not found")
+	}
+	return nil
+}
+
+// ReorderItems 按给定顺序重排待发布条目，未列出的待发布条目保持原有相对顺序排在后面
+func (s *QueueService) ReorderItems(ctx context.Context, queueID int, itemIDs []int) error {
+	pending, err := s.GetQueueItems(ctx, queueID, true)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int]bool, len(pending))
+	for _, item := range pending {
+		byID[item.ID] = true
+	}
+	ordered := make([]int, 0, len(pending))
+	listed := make(map[int]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		if !byID[id] {
+			return fmt.Errorf("条目 %d 不在队列中或已发布", id)
+		}
+		if !listed[id] {
+			listed[id] = true
+			ordered = append(ordered, id)
+		}
+	}
+	for _, item := range pending {
+		if !listed[item.ID] {
+			ordered = append(ordered, item.ID)
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// 新位置排在所有已消费条目之后
+		var maxConsumed int
+		if err := tx.Model(&database.QueueItem{}).
+			Where("queue_id = ? AND status <> ?", queueID, database.QueueItemPending).
+			Select("COALESCE(MAX(position), 0)").Scan(&maxConsumed).Error; err != nil {
+			return fmt.Errorf("query queue items failed: %w", err)
+		}
+
+		for i, id := range ordered {
+			if err := tx.Model(&database.QueueItem{}).Where("id = ?", id).
+				UpdateColumn("position", maxConsumed+i+1).Error; err != nil {
+				return fmt.Errorf("reorder queue items failed: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// NextItem 获取队列中下一个待发布条目，队列暂停或已清空时返回 nil
+func (s *QueueService) NextItem(ctx context.Context, queueID int) (*database.ContentQueue, *database.QueueItem, error) {
+	queue, err := s.GetQueueByID(ctx, queueID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if queue.Paused {
+		return queue, nil, nil
+	}
+	var item database.QueueItem
+	err = s.db.Where("queue_id = ? AND status = ?", queueID, database.QueueItemPending).
+		Order("position ASC, id ASC").First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return queue, nil, nil
+	}
+	if err != nil {
+		return queue, nil, fmt.Errorf("query queue item failed: %w", err)
+	}
+	return queue, &item, nil
+}
+
+// ErrQueueItemClaimed 条目已被其他发布流程取出
+var ErrQueueItemClaimed = errors.New("队列条目已被取出发布")
+
+// claimItem 将待发布条目原子地标记为已取出，条目已不是待发布状态时返回 ErrQueueItemClaimed
+func (s *QueueService) claimItem(itemID int) error {
+	result := s.db.Model(&database.QueueItem{}).
+		Where("id = ? AND status = ?", itemID, database.QueueItemPending).
+		UpdateColumns(map[string]interface{}{
+			"status":      database.QueueItemClaimed,
+			"consumed_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("claim queue item failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrQueueItemClaimed
+	}
+	return nil
+}
+
+// RetryItem 将创建上传任务失败的条目重新放回队列，保持原有位置
+func (s *QueueService) RetryItem(ctx context.Context, itemID int) error {
+	result := s.db.Model(&database.QueueItem{}).
+		Where("id = ? AND status = ?", itemID, database.QueueItemFailed).
+		UpdateColumns(map[string]interface{}{
+			"status":      database.QueueItemPending,
+			"task_ids":    "[]",
+			"message":     "",
+			"consumed_at": nil,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("retry queue item failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("条目 %d 不存在或不是失败状态", itemID)
+	}
+	return nil
+}
+
+// RecoverClaimedItems 将应用中断时停留在已取出状态的条目标记为失败，由用户确认后重试
+func (s *QueueService) RecoverClaimedItems(ctx context.Context) (int, error) {
+	result := s.db.Model(&database.QueueItem{}).
+		Where("status = ?", database.QueueItemClaimed).
+		UpdateColumns(map[string]interface{}{
+			"status":  database.QueueItemFailed,
+			"message": "发布时应用被中断，请确认上传任务后手动重试",
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("recover queue items failed: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// MarkItem 记录条目消费结果
+func (s *QueueService) MarkItem(itemID int, status string, taskIDs []int, msg string) error {
+	data, err := json.Marshal(taskIDs)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(&database.QueueItem{}).Where("id = ?", itemID).UpdateColumns(map[string]interface{}{
+		"status":      status,
+		"task_ids":    string(data),
+		"message":     msg,
+		"consumed_at": time.Now(),
+	}).Error
+}
+
+// PublishItem 取出条目并发布到队列的目标账号，scheduleTime 为空时立即上传；
+// 条目已被其他流程取出时返回 ErrQueueItemClaimed
+func (s *QueueService) PublishItem(ctx context.Context, queue *database.ContentQueue, item *database.QueueItem, accountIDs []int, presetID int, scheduleTime *string) ([]database.UploadTask, error) {
+	if err := s.claimItem(item.ID); err != nil {
+		return nil, err
+	}
+	if len(accountIDs) == 0 {
+		accountIDs = queue.AccountIDs
+	}
+	if presetID == 0 {
+		presetID = queue.PresetID
+	}
+
+	status := database.QueueItemPublished
+	if scheduleTime != nil {
+		status = database.QueueItemScheduled
+	}
+
+	tasks, err := s.uploads.CreateUploadTask(ctx, item.VideoID, accountIDs, scheduleTime, &UploadTaskMetadata{PresetID: presetID})
+	if err == nil && len(tasks) == 0 {
+		err = fmt.Errorf("没有创建任何上传任务")
+	}
+	if err != nil {
+		s.MarkItem(item.ID, database.QueueItemFailed, nil, err.Error())
+		return nil, err
+	}
+
+	taskIDs := make([]int, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	msg := ""
+	if len(tasks) < len(accountIDs) {
+		msg = fmt.Sprintf("%d/%d 个账号创建成功", len(tasks), len(accountIDs))
+	}
+	if err := s.MarkItem(item.ID, status, taskIDs, msg); err != nil {
+		return tasks, fmt.Errorf("update queue item failed: %w", err)
+	}
+	return tasks, nil
+}
+
+// ScheduleQueue 按全局发布配置（每日发布时间）为队列中所有待发布条目生成定时时间并创建定时上传任务
+func (s *QueueService) ScheduleQueue(ctx context.Context, queueID int) ([]database.UploadTask, error) {
+	queue, err := s.GetQueueByID(ctx, queueID)
+	if err != nil {
+		return nil, err
+	}
+	if queue.Paused {
+		return nil, fmt.Errorf("队列 %s 已暂停", queue.Name)
+	}
+	if len(queue.AccountIDs) == 0 {
+		return nil, fmt.Errorf("队列 %s 未设置目标账号", queue.Name)
+	}
+
+	items, err := s.GetQueueItems(ctx, queueID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("队列 %s 没有待发布的视频", queue.Name)
+	}
+
+	times, err := s.schedules.GenerateScheduleTimes(ctx, len(items))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []database.UploadTask
+	var errs []string
+	for i := range items {
+		if i >= len(times) {
+			break
+		}
+		scheduleTime := times[i].Format(time.RFC3339)
+		created, err := s.PublishItem(ctx, queue, &items[i], nil, 0, &scheduleTime)
+		if errors.Is(err, ErrQueueItemClaimed) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("视频 %d: %v", items[i].VideoID, err))
+			continue
+		}
+		tasks = append(tasks, created...)
+	}
+
+	if len(tasks) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("创建任务失败: %s", strings.Join(errs, "; "))
+	}
+	return tasks, nil
+}
+
+// validateQueue 校验内容队列
+func validateQueue(queue *database.ContentQueue) error {
+	queue.Name = strings.TrimSpace(queue.Name)
+	if queue.Name == "" {
+		return fmt.Errorf("队列名称不能为空")
+	}
+	return nil
+}
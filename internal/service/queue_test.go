@@ -0,0 +1,158 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// queueVideoIDs 按发布顺序返回队列中待发布条目的视频ID
+func queueVideoIDs(t *testing.T, svc *QueueService, queueID int) []int {
+	t.Helper()
+	items, err := svc.GetQueueItems(context.Background(), queueID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.VideoID)
+	}
+	return ids
+}
+
+func TestQueueOrdering(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewQueueService(db, nil, nil)
+	ctx := context.Background()
+
+	videos := make([]database.Video, 6)
+	for i := range videos {
+		videos[i] = database.Video{Filename: "v.mp4", FilePath: "/v.mp4"}
+	}
+	if err := db.Create(&videos).Error; err != nil {
+		t.Fatal(err)
+	}
+	v := func(i int) int { return videos[i].ID }
+
+	queue := &database.ContentQueue{Name: "日更"}
+	if err := svc.CreateQueue(ctx, queue); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.AddVideos(ctx, queue.ID, []int{v(0), v(1), v(2)}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddVideos(ctx, queue.ID, []int{v(3), v(4)}, false); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := queueVideoIDs(t, svc, queue.ID), []int{v(0), v(1), v(2), v(3), v(4)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("appended order = %v, want %v", got, want)
+	}
+
+	// 插入队首的视频保持传入顺序，排在所有已有条目之前
+	if _, err := svc.AddVideos(ctx, queue.ID, []int{v(5), v(2)}, true); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := queueVideoIDs(t, svc, queue.ID), []int{v(5), v(2), v(0), v(1), v(2), v(3), v(4)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("front order = %v, want %v", got, want)
+	}
+	if _, err := svc.AddVideos(ctx, queue.ID, []int{v(0), 9999}, false); err == nil {
+		t.Error("expected missing video error")
+	}
+	if got := queueVideoIDs(t, svc, queue.ID); len(got) != 7 {
+		t.Errorf("failed add should roll back, items = %v", got)
+	}
+
+	// 消费队首条目
+	_, first, err := svc.NextItem(ctx, queue.ID)
+	if err != nil || first == nil || first.VideoID != v(5) {
+		t.Fatalf("next = %+v, %v", first, err)
+	}
+	if err := svc.MarkItem(first.ID, database.QueueItemPublished, []int{1}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重排：列出的条目在前，未列出的保持原有相对顺序，且都排在已消费条目之后
+	pending, _ := svc.GetQueueItems(ctx, queue.ID, true)
+	if err := svc.ReorderItems(ctx, queue.ID, []int{pending[4].ID, pending[2].ID, pending[4].ID}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := queueVideoIDs(t, svc, queue.ID), []int{v(3), v(1), v(2), v(0), v(2), v(4)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reordered = %v, want %v", got, want)
+	}
+	reordered, _ := svc.GetQueueItems(ctx, queue.ID, true)
+	var consumed database.QueueItem
+	db.First(&consumed, first.ID)
+	if reordered[0].Position <= consumed.Position {
+		t.Errorf("pending position %d should be after consumed %d", reordered[0].Position, consumed.Position)
+	}
+	if err := svc.ReorderItems(ctx, queue.ID, []int{first.ID}); err == nil {
+		t.Error("expected error when reordering a consumed item")
+	}
+
+	if err := svc.SetQueuePaused(ctx, queue.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, item, err := svc.NextItem(ctx, queue.ID); err != nil || item != nil {
+		t.Errorf("paused queue next = %+v, %v", item, err)
+	}
+}
+
+func TestQueueItemClaimAndRetry(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewQueueService(db, nil, nil)
+	ctx := context.Background()
+
+	videos := []database.Video{{Filename: "a.mp4", FilePath: "/a.mp4"}, {Filename: "b.mp4", FilePath: "/b.mp4"}}
+	if err := db.Create(&videos).Error; err != nil {
+		t.Fatal(err)
+	}
+	queue := &database.ContentQueue{Name: "日更"}
+	if err := svc.CreateQueue(ctx, queue); err != nil {
+		t.Fatal(err)
+	}
+	items, err := svc.AddVideos(ctx, queue.ID, []int{videos[0].ID, videos[1].ID}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := func(id int) string {
+		var item database.QueueItem
+		if err := db.First(&item, id).Error; err != nil {
+			t.Fatal(err)
+		}
+		return item.Status
+	}
+
+	// 同一条目只能被取出一次，下一次取到的是后面的条目
+	if err := svc.claimItem(items[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.claimItem(items[0].ID); !errors.Is(err, ErrQueueItemClaimed) {
+		t.Fatalf("second claim err = %v", err)
+	}
+	if _, next, err := svc.NextItem(ctx, queue.ID); err != nil || next == nil || next.ID != items[1].ID {
+		t.Fatalf("next = %+v, %v", next, err)
+	}
+
+	// 只有失败的条目可以重新入队
+	if err := svc.RetryItem(ctx, items[0].ID); err == nil {
+		t.Error("expected error when retrying a claimed item")
+	}
+	if count, err := svc.RecoverClaimedItems(ctx); err != nil || count != 1 {
+		t.Fatalf("recovered %d, err = %v", count, err)
+	}
+	if got := status(items[0].ID); got != database.QueueItemFailed {
+		t.Fatalf("interrupted item status = %s", got)
+	}
+	if err := svc.RetryItem(ctx, items[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(items[0].ID); got != database.QueueItemPending {
+		t.Errorf("retried item status = %s", got)
+	}
+	if _, next, err := svc.NextItem(ctx, queue.ID); err != nil || next == nil || next.ID != items[0].ID || next.Message != "" || next.ConsumedAt != nil {
+		t.Errorf("retried item should be next: %+v, %v", next, err)
+	}
+}
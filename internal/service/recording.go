@@ -0,0 +1,206 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecordingService 上传会话录屏：配置、为任务创建录屏会话，并按保留天数/总大小清理录像
+type RecordingService struct {
+	db         *gorm.DB
+	config     *types.RecordingConfig
+	configPath string
+	mu         sync.RWMutex
+}
+
+// NewRecordingService 创建录屏服务
+func NewRecordingService(db *gorm.DB) *RecordingService {
+	service := &RecordingService{
+		db:         db,
+		configPath: "./config/recording.json",
+	}
+
+	// 加载配置
+	service.loadConfig()
+
+	return service
+}
+
+// loadConfig 加载录屏配置
+func (s *RecordingService) loadConfig() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config = types.DefaultRecordingConfig()
+
+	data, err := os.ReadFile(s.configPath)
+	if err != nil {
+		return
+	}
+
+	var loadedConfig types.RecordingConfig
+	if err := json.Unmarshal(data, &loadedConfig); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 加载录屏配置失败: %v, 使用默认配置", err))
+		return
+	}
+	s.config = &loadedConfig
+}
+
+// saveConfig 保存录屏配置
+func (s *RecordingService) saveConfig() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.configPath), 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %w", err)
+	}
+	data, err := json.MarshalIndent(s.config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %w", err)
+	}
+	if err := os.WriteFile(s.configPath, data, 0644); err != nil {
+		return fmt.Errorf("保存配置失败: %w", err)
+	}
+	return nil
+}
+
+// GetConfig 获取录屏配置
+func (s *RecordingService) GetConfig() *types.RecordingConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	config := *s.config
+	config.Platforms = make(map[string]bool, len(s.config.Platforms))
+	for platform, enabled := range s.config.Platforms {
+		config.Platforms[platform] = enabled
+	}
+	return &config
+}
+
+// UpdateConfig 更新录屏配置
+func (s *RecordingService) UpdateConfig(cfg *types.RecordingConfig) error {
+	if cfg.Width < 0 || cfg.Height < 0 || (cfg.Width == 0) != (cfg.Height == 0) {
+		return fmt.Errorf("录屏尺寸无效: %dx%d（宽高需同时设置，或都为 0）", cfg.Width, cfg.Height)
+	}
+	if cfg.MaxAgeDays < 0 || cfg.MaxSizeMB < 0 {
+		return fmt.Errorf("保留天数和总大小不能为负数")
+	}
+	if cfg.Platforms == nil {
+		cfg.Platforms = map[string]bool{}
+	}
+
+	s.mu.Lock()
+	s.config = cfg
+	s.mu.Unlock()
+
+	return s.saveConfig()
+}
+
+// GetDir 获取录屏目录
+func (s *RecordingService) GetDir() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.config.Dir != "" {
+		return s.config.Dir
+	}
+	return config.Config.RecordingPath
+}
+
+// NewRecording 为上传任务创建录屏会话，平台未开启录屏时返回 nil
+func (s *RecordingService) NewRecording(taskID int, platform string) *browser.Recording {
+	cfg := s.GetConfig()
+	if !cfg.EnabledFor(platform) {
+		return nil
+	}
+	name := fmt.Sprintf("task_%d_%s_%s", taskID, platform, time.Now().Format("20060102_150405"))
+	return browser.NewRecording(s.GetDir(), name, cfg.Width, cfg.Height)
+}
+
+// ListRecordings 获取录屏文件列表（按时间倒序）
+func (s *RecordingService) ListRecordings() ([]types.RecordingInfo, error) {
+	dir := s.GetDir()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取录屏目录失败: %w", err)
+	}
+
+	var recordings []types.RecordingInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".webm") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		recordings = append(recordings, types.RecordingInfo{
+			Filename:  entry.Name(),
+			Path:      filepath.Join(dir, entry.Name()),
+			Size:      info.Size(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].CreatedAt.After(recordings[j].CreatedAt)
+	})
+	return recordings, nil
+}
+
+// CleanRecordings 按保留天数和总大小清理录像（超出总大小时从最旧的开始删除），并解除任务与已删除录像的关联
+func (s *RecordingService) CleanRecordings() (int, error) {
+	recordings, err := s.ListRecordings()
+	if err != nil {
+		return 0, err
+	}
+	cfg := s.GetConfig()
+
+	expired := recordingsToClean(recordings, cfg.MaxAgeDays, cfg.MaxSizeMB, time.Now())
+	deleted := 0
+	for _, rec := range expired {
+		if err := os.Remove(rec.Path); err != nil {
+			utils.Warn(fmt.Sprintf("[-] 清理录屏失败 %s: %v", rec.Path, err))
+			continue
+		}
+		s.db.Model(&database.UploadTask{}).Where("recording_path = ?", rec.Path).UpdateColumn("recording_path", "")
+		deleted++
+	}
+	if deleted > 0 {
+		utils.Info(fmt.Sprintf("[-] 已清理 %d 个录屏", deleted))
+	}
+	return deleted, nil
+}
+
+// recordingsToClean 超出保留天数或总大小限制的录像，recordings 需按时间倒序
+func recordingsToClean(recordings []types.RecordingInfo, maxAgeDays, maxSizeMB int, now time.Time) []types.RecordingInfo {
+	var result []types.RecordingInfo
+	cutoff := now.AddDate(0, 0, -maxAgeDays)
+	limit := int64(maxSizeMB) * 1024 * 1024
+	var total int64
+	for _, rec := range recordings {
+		if maxAgeDays > 0 && rec.CreatedAt.Before(cutoff) {
+			result = append(result, rec)
+			continue
+		}
+		total += rec.Size
+		if maxSizeMB > 0 && total > limit {
+			result = append(result, rec)
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"Fuploader/internal/types"
+	"testing"
+	"time"
+)
+
+func TestRecordingsToClean(t *testing.T) {
+	now := time.Date(2025, 6, 20, 12, 0, 0, 0, time.UTC)
+	mb := int64(1024 * 1024)
+	// 按时间倒序
+	recordings := []types.RecordingInfo{
+		{Filename: "new.webm", Size: 40 * mb, CreatedAt: now.Add(-time.Hour)},
+		{Filename: "mid.webm", Size: 40 * mb, CreatedAt: now.AddDate(0, 0, -2)},
+		{Filename: "old.webm", Size: 40 * mb, CreatedAt: now.AddDate(0, 0, -3)},
+		{Filename: "expired.webm", Size: 1 * mb, CreatedAt: now.AddDate(0, 0, -10)},
+	}
+
+	names := func(list []types.RecordingInfo) []string {
+		var out []string
+		for _, r := range list {
+			out = append(out, r.Filename)
+		}
+		return out
+	}
+
+	got := names(recordingsToClean(recordings, 7, 100, now))
+	if len(got) != 2 || got[0] != "old.webm" || got[1] != "expired.webm" {
+		t.Errorf("age+size limits: %v", got)
+	}
+	if got := recordingsToClean(recordings, 0, 0, now); len(got) != 0 {
+		t.Errorf("no limits: %v", names(got))
+	}
+}
+
+func TestRecordingConfigEnabledFor(t *testing.T) {
+	cfg := types.DefaultRecordingConfig()
+	if cfg.EnabledFor("douyin") {
+		t.Error("recording enabled by default")
+	}
+	cfg.Enabled = true
+	cfg.Platforms["tiktok"] = false
+	if !cfg.EnabledFor("douyin") || cfg.EnabledFor("tiktok") {
+		t.Errorf("platform switches not applied: %+v", cfg.Platforms)
+	}
+}
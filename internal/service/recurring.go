@@ -0,0 +1,646 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"Fuploader/internal/scheduler"
+	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// recurringCheckInterval 检查到期时段的间隔
+	recurringCheckInterval = 30 * time.Second
+	// slotMissedGrace 时段到期后超过该时间仍未执行（如应用未运行）视为错过
+	slotMissedGrace = 30 * time.Minute
+	// maxMissedSlotRecords 一次最多记录的错过时段数，避免长时间未运行后写入大量记录
+	maxMissedSlotRecords = 100
+	// maxUpcomingSlots 单次查询的最大时段数
+	maxUpcomingSlots = 100
+)
+
+// RecurringScheduleService 周期性发布计划服务
+type RecurringScheduleService struct {
+	db        *gorm.DB
+	uploads   *UploadService
+	schedules *ScheduleService
+	queues    *QueueService
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	running  bool
+}
+
+// NewRecurringScheduleService 创建周期性发布计划服务
+func NewRecurringScheduleService(db *gorm.DB, uploads *UploadService, schedules *ScheduleService, queues *QueueService) *RecurringScheduleService {
+	return &RecurringScheduleService{
+		db:        db,
+		uploads:   uploads,
+		schedules: schedules,
+		queues:    queues,
+	}
+}
+
+// Start 启动后台检查，到期的时段自动取出视频并创建上传任务
+func (s *RecurringScheduleService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return
+	}
+	s.running = true
+	s.stopChan = make(chan struct{})
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(recurringCheckInterval)
+		defer ticker.Stop()
+
+		s.checkDueSchedules(time.Now())
+		for {
+			select {
+			case <-s.stopChan:
+				return
+			case now := <-ticker.C:
+				s.checkDueSchedules(now)
+			}
+		}
+	}()
+	utils.Info("[+] 周期性发布计划已启动")
+}
+
+// Stop 停止后台检查
+func (s *RecurringScheduleService) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	close(s.stopChan)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// GetSchedules 获取所有周期性发布计划
+func (s *RecurringScheduleService) GetSchedules(ctx context.Context) ([]database.RecurringSchedule, error) {
+	var schedules []database.RecurringSchedule
+	if err := s.db.Order("id ASC").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("query recurring schedules failed: %w", err)
+	}
+	return schedules, nil
+}
+
+// GetScheduleByID 根据ID获取周期性发布计划
+func (s *RecurringScheduleService) GetScheduleByID(ctx context.Context, id int) (*database.RecurringSchedule, error) {
+	var schedule database.RecurringSchedule
+	if err := s.db.First(&schedule, id).Error; err != nil {
+		return nil, fmt.Errorf("recurring schedule not found: %w", err)
+	}
+	return &schedule, nil
+}
+
+// CreateSchedule 创建周期性发布计划
+func (s *RecurringScheduleService) CreateSchedule(ctx context.Context, schedule *database.RecurringSchedule) error {
+	if err := s.validateSchedule(ctx, schedule); err != nil {
+		return err
+	}
+	schedule.ID = 0
+	schedule.LastRunAt = nil
+	schedule.NextRunAt = s.nextRunAt(ctx, schedule, time.Now())
+	if err := s.db.Create(schedule).Error; err != nil {
+		return fmt.Errorf("create recurring schedule failed: %w", err)
+	}
+	return nil
+}
+
+// UpdateSchedule 更新周期性发布计划，重新计算下一个时段
+func (s *RecurringScheduleService) UpdateSchedule(ctx context.Context, schedule *database.RecurringSchedule) error {
+	if err := s.validateSchedule(ctx, schedule); err != nil {
+		return err
+	}
+	existing, err := s.GetScheduleByID(ctx, schedule.ID)
+	if err != nil {
+		return err
+	}
+	schedule.LastRunAt = existing.LastRunAt
+	schedule.CreatedAt = existing.CreatedAt
+	if schedule.NextVideoIndex < 0 || schedule.NextVideoIndex > len(schedule.VideoIDs) {
+		schedule.NextVideoIndex = 0
+	}
+	schedule.NextRunAt = s.nextRunAt(ctx, schedule, time.Now())
+	if err := s.db.Save(schedule).Error; err != nil {
+		return fmt.Errorf("update recurring schedule failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteSchedule 删除周期性发布计划及其时段记录
+func (s *RecurringScheduleService) DeleteSchedule(ctx context.Context, id int) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&database.RecurringSchedule{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("delete recurring schedule failed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("recurring schedule not found")
+		}
+		return tx.Where("schedule_id = ?", id).Delete(&database.ScheduleSlot{}).Error
+	})
+}
+
+// SetScheduleEnabled 启用/停用周期性发布计划
+func (s *RecurringScheduleService) SetScheduleEnabled(ctx context.Context, id int, enabled bool) error {
+	schedule, err := s.GetScheduleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 重新启用时从当前时间开始计算，不补发停用期间的时段；停用时清空下一个时段
+	schedule.Enabled = enabled
+	return s.db.Model(schedule).Updates(map[string]interface{}{
+		"enabled":     enabled,
+		"next_run_at": s.nextRunAt(ctx, schedule, time.Now()),
+	}).Error
+}
+
+// GetUpcomingSlots 预览即将到来的发布时段及对应视频，id 为 0 时返回所有启用的计划
+func (s *RecurringScheduleService) GetUpcomingSlots(ctx context.Context, id int, count int) ([]types.UpcomingSlot, error) {
+	if count <= 0 || count > maxUpcomingSlots {
+		count = maxUpcomingSlots
+	}
+
+	var schedules []database.RecurringSchedule
+	if id > 0 {
+		schedule, err := s.GetScheduleByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	} else if err := s.db.Where("enabled = ?", true).Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("query recurring schedules failed: %w", err)
+	}
+
+	videoTitles := make(map[int]string)
+	var slots []types.UpcomingSlot
+	for i := range schedules {
+		schedule := &schedules[i]
+		loc := s.location(ctx, schedule)
+		index := schedule.NextVideoIndex
+		accountIDs := schedule.AccountIDs
+
+		// 使用内容队列时按队列中待发布条目的顺序预览
+		var queueVideos []int
+		if schedule.QueueID > 0 {
+			queue, err := s.queues.GetQueueByID(ctx, schedule.QueueID)
+			if err != nil {
+				continue
+			}
+			if len(accountIDs) == 0 {
+				accountIDs = queue.AccountIDs
+			}
+			if !queue.Paused {
+				items, err := s.queues.GetQueueItems(ctx, queue.ID, true)
+				if err != nil {
+					continue
+				}
+				for _, item := range items {
+					queueVideos = append(queueVideos, item.VideoID)
+				}
+			}
+			index = 0
+		}
+		after := time.Now().In(loc)
+		if schedule.NextRunAt != nil && schedule.NextRunAt.After(after) {
+			after = schedule.NextRunAt.In(loc).Add(-time.Minute)
+		}
+
+		for n := 0; n < count; n++ {
+			slotTime, err := NextScheduleSlot(schedule, after, loc)
+			if err != nil || slotTime.IsZero() {
+				break
+			}
+			after = slotTime
+
+			var videoID int
+			if schedule.QueueID > 0 {
+				if index < len(queueVideos) {
+					videoID = queueVideos[index]
+				}
+				index++
+			} else {
+				videoID, index = pickPlaylistVideo(schedule, index)
+			}
+			slot := types.UpcomingSlot{
+				ScheduleID:   schedule.ID,
+				ScheduleName: schedule.Name,
+				SlotTime:     slotTime.Format(time.RFC3339),
+				VideoID:      videoID,
+				VideoTitle:   s.videoTitle(videoTitles, videoID),
+				AccountIDs:   accountIDs,
+			}
+			// 落在全局禁发时段内的时段到期后按策略顺延或阻止
+			if hit := s.schedules.Blackouts().Check(nil, slotTime, loc); hit != nil {
+				slot.Blackout = hit.Message()
+			}
+			slots = append(slots, slot)
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].SlotTime < slots[j].SlotTime })
+	if len(slots) > count {
+		slots = slots[:count]
+	}
+	return slots, nil
+}
+
+// GetSlotHistory 获取计划的时段消费记录（最新在前）
+func (s *RecurringScheduleService) GetSlotHistory(ctx context.Context, id int, limit int) ([]database.ScheduleSlot, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	var slots []database.ScheduleSlot
+	if err := s.db.Where("schedule_id = ?", id).Order("slot_time DESC").Limit(limit).Find(&slots).Error; err != nil {
+		return nil, fmt.Errorf("query schedule slots failed: %w", err)
+	}
+	return slots, nil
+}
+
+// checkDueSchedules 处理所有到期的时段
+func (s *RecurringScheduleService) checkDueSchedules(now time.Time) {
+	var schedules []database.RecurringSchedule
+	if err := s.db.Where("enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now.UTC()).
+		Find(&schedules).Error; err != nil {
+		utils.Warn(fmt.Sprintf("[-] 查询到期的发布计划失败: %v", err))
+		return
+	}
+
+	ctx := context.Background()
+	for i := range schedules {
+		schedule := &schedules[i]
+		slotTime := *schedule.NextRunAt
+
+		if now.Sub(slotTime) > slotMissedGrace {
+			s.recordSlot(schedule, slotTime, 0, database.SlotStatusMissed, nil, "应用未运行，时段已错过")
+			utils.Warn(fmt.Sprintf("[-] 发布计划 %s 错过时段 %s", schedule.Name, slotTime.Format("2006-01-02 15:04")))
+		} else {
+			s.fireSlot(ctx, schedule, slotTime)
+			schedule.LastRunAt = &now
+		}
+		// 每次只处理最早的一个时段，之后到期的时段记录为错过
+		s.recordMissedSlots(ctx, schedule, slotTime, now)
+
+		schedule.NextRunAt = s.nextRunAt(ctx, schedule, now)
+		if err := s.db.Model(schedule).Updates(map[string]interface{}{
+			"next_video_index": schedule.NextVideoIndex,
+			"next_run_at":      schedule.NextRunAt,
+			"last_run_at":      schedule.LastRunAt,
+		}).Error; err != nil {
+			utils.Warn(fmt.Sprintf("[-] 更新发布计划 %s 失败: %v", schedule.Name, err))
+		}
+	}
+}
+
+// fireSlot 取出播放列表中的下一个视频并发布到计划中的账号
+func (s *RecurringScheduleService) fireSlot(ctx context.Context, schedule *database.RecurringSchedule, slotTime time.Time) {
+	if schedule.QueueID > 0 {
+		s.fireQueueSlot(ctx, schedule, slotTime)
+		return
+	}
+
+	videoID, next := pickPlaylistVideo(schedule, schedule.NextVideoIndex)
+	if videoID == 0 {
+		s.recordSlot(schedule, slotTime, 0, database.SlotStatusEmpty, nil, "播放列表已用完")
+		utils.Warn(fmt.Sprintf("[-] 发布计划 %s 的播放列表已用完", schedule.Name))
+		return
+	}
+
+	metadata := &UploadTaskMetadata{PresetID: schedule.PresetID}
+	tasks, err := s.uploads.CreateUploadTask(ctx, videoID, schedule.AccountIDs, nil, metadata)
+	if err != nil || len(tasks) == 0 {
+		msg := "没有创建任何上传任务"
+		if err != nil {
+			msg = err.Error()
+		}
+		// 与队列中发布失败的条目一样跳过该视频（如视频已被删除），避免之后每个时段都卡在同一个视频上
+		s.saveSlotProgress(schedule, next, newScheduleSlot(schedule, slotTime, videoID, database.SlotStatusFailed, nil, msg+"，已跳过该视频"))
+		utils.Warn(fmt.Sprintf("[-] 发布计划 %s 创建上传任务失败，跳过视频 %d: %s", schedule.Name, videoID, msg))
+		return
+	}
+
+	taskIDs := make([]int, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	msg := ""
+	if len(tasks) < len(schedule.AccountIDs) {
+		msg = fmt.Sprintf("%d/%d 个账号创建成功", len(tasks), len(schedule.AccountIDs))
+	}
+
+	s.saveSlotProgress(schedule, next, newScheduleSlot(schedule, slotTime, videoID, database.SlotStatusPublished, taskIDs, msg))
+	utils.Info(fmt.Sprintf("[+] 发布计划 %s 已发布视频 %d 到 %d 个账号", schedule.Name, videoID, len(tasks)))
+}
+
+// saveSlotProgress 推进播放列表并记录时段结果，两者在同一事务中保存
+func (s *RecurringScheduleService) saveSlotProgress(schedule *database.RecurringSchedule, next int, slot database.ScheduleSlot) {
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(schedule).UpdateColumn("next_video_index", next).Error; err != nil {
+			return err
+		}
+		return tx.Create(&slot).Error
+	}); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 发布计划 %s 保存播放进度失败: %v", schedule.Name, err))
+		return
+	}
+	schedule.NextVideoIndex = next
+}
+
+// fireQueueSlot 从内容队列取出下一个条目发布
+func (s *RecurringScheduleService) fireQueueSlot(ctx context.Context, schedule *database.RecurringSchedule, slotTime time.Time) {
+	for {
+		queue, item, err := s.queues.NextItem(ctx, schedule.QueueID)
+		if err != nil {
+			s.recordSlot(schedule, slotTime, 0, database.SlotStatusFailed, nil, err.Error())
+			utils.Warn(fmt.Sprintf("[-] 发布计划 %s 读取内容队列失败: %v", schedule.Name, err))
+			return
+		}
+		if queue.Paused {
+			s.recordSlot(schedule, slotTime, 0, database.SlotStatusPaused, nil, fmt.Sprintf("队列 %s 已暂停", queue.Name))
+			return
+		}
+		if item == nil {
+			s.recordSlot(schedule, slotTime, 0, database.SlotStatusEmpty, nil, fmt.Sprintf("队列 %s 已清空", queue.Name))
+			utils.Warn(fmt.Sprintf("[-] 发布计划 %s 的内容队列 %s 已清空", schedule.Name, queue.Name))
+			return
+		}
+
+		tasks, err := s.queues.PublishItem(ctx, queue, item, schedule.AccountIDs, schedule.PresetID, nil)
+		if errors.Is(err, ErrQueueItemClaimed) {
+			// 条目刚被其他发布计划取走，取下一个
+			continue
+		}
+		s.recordQueueSlot(schedule, slotTime, queue, item, tasks, err)
+		return
+	}
+}
+
+// recordQueueSlot 记录从内容队列发布的时段结果
+func (s *RecurringScheduleService) recordQueueSlot(schedule *database.RecurringSchedule, slotTime time.Time, queue *database.ContentQueue, item *database.QueueItem, tasks []database.UploadTask, err error) {
+	if err != nil {
+		s.recordSlot(schedule, slotTime, item.VideoID, database.SlotStatusFailed, nil, err.Error())
+		utils.Warn(fmt.Sprintf("[-] 发布计划 %s 创建上传任务失败: %v", schedule.Name, err))
+		return
+	}
+
+	taskIDs := make([]int, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	s.recordSlot(schedule, slotTime, item.VideoID, database.SlotStatusPublished, taskIDs, "")
+	utils.Info(fmt.Sprintf("[+] 发布计划 %s 已从队列 %s 发布视频 %d 到 %d 个账号", schedule.Name, queue.Name, item.VideoID, len(tasks)))
+}
+
+// recordMissedSlots 记录 after（不含）到 now 之间被跳过的时段，超过 maxMissedSlotRecords 个时只记录最早的部分
+func (s *RecurringScheduleService) recordMissedSlots(ctx context.Context, schedule *database.RecurringSchedule, after, now time.Time) {
+	loc := s.location(ctx, schedule)
+	missed := 0
+	var last time.Time
+	for {
+		slotTime, err := NextScheduleSlot(schedule, after, loc)
+		if err != nil || slotTime.IsZero() || slotTime.After(now) {
+			break
+		}
+		if missed < maxMissedSlotRecords {
+			s.recordSlot(schedule, slotTime.UTC(), 0, database.SlotStatusMissed, nil, "应用未运行，时段已错过")
+		}
+		missed++
+		after, last = slotTime, slotTime
+	}
+	if missed > 0 {
+		utils.Warn(fmt.Sprintf("[-] 发布计划 %s 错过 %d 个时段（截至 %s）", schedule.Name, missed, last.In(loc).Format("2006-01-02 15:04")))
+	}
+}
+
+// recordSlot 记录时段消费结果
+func (s *RecurringScheduleService) recordSlot(schedule *database.RecurringSchedule, slotTime time.Time, videoID int, status string, taskIDs []int, msg string) {
+	slot := newScheduleSlot(schedule, slotTime, videoID, status, taskIDs, msg)
+	if err := s.db.Create(&slot).Error; err != nil {
+		utils.Warn(fmt.Sprintf("[-] 记录发布时段失败: %v", err))
+	}
+}
+
+// newScheduleSlot 构造时段消费记录
+func newScheduleSlot(schedule *database.RecurringSchedule, slotTime time.Time, videoID int, status string, taskIDs []int, msg string) database.ScheduleSlot {
+	return database.ScheduleSlot{
+		ScheduleID: schedule.ID,
+		SlotTime:   slotTime,
+		VideoID:    videoID,
+		Status:     status,
+		TaskIDs:    taskIDs,
+		Message:    msg,
+	}
+}
+
+// nextRunAt 计算 after 之后的下一个时段，计划停用或无可用时段时返回 nil
+func (s *RecurringScheduleService) nextRunAt(ctx context.Context, schedule *database.RecurringSchedule, after time.Time) *time.Time {
+	if !schedule.Enabled {
+		return nil
+	}
+	next, err := NextScheduleSlot(schedule, after, s.location(ctx, schedule))
+	if err != nil || next.IsZero() {
+		return nil
+	}
+	// 统一以 UTC 存储，保证 SQLite 中按字符串比较时间的结果正确
+	next = next.UTC()
+	return &next
+}
+
+// location 计划使用的时区，未设置时使用全局发布配置的时区
+func (s *RecurringScheduleService) location(ctx context.Context, schedule *database.RecurringSchedule) *time.Location {
+	return s.schedules.Location(ctx, schedule.TimeZone)
+}
+
+// videoTitle 获取视频标题（带缓存）
+func (s *RecurringScheduleService) videoTitle(cache map[int]string, videoID int) string {
+	if videoID == 0 {
+		return ""
+	}
+	if title, ok := cache[videoID]; ok {
+		return title
+	}
+	var video database.Video
+	title := ""
+	if err := s.db.First(&video, videoID).Error; err == nil {
+		title = video.Title
+		if title == "" {
+			title = video.Filename
+		}
+	}
+	cache[videoID] = title
+	return title
+}
+
+// validateSchedule 校验周期性发布计划
+func (s *RecurringScheduleService) validateSchedule(ctx context.Context, schedule *database.RecurringSchedule) error {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if schedule.Name == "" {
+		return fmt.Errorf("计划名称不能为空")
+	}
+	if schedule.QueueID > 0 {
+		queue, err := s.queues.GetQueueByID(ctx, schedule.QueueID)
+		if err != nil {
+			return err
+		}
+		if len(schedule.AccountIDs) == 0 && len(queue.AccountIDs) == 0 {
+			return fmt.Errorf("计划和队列 %s 均未设置目标账号", queue.Name)
+		}
+	} else if len(schedule.AccountIDs) == 0 {
+		return fmt.Errorf("至少选择一个账号")
+	}
+	if schedule.TimeZone != "" {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			return fmt.Errorf("无效时区: %s", schedule.TimeZone)
+		}
+	}
+
+	schedule.CronExpr = strings.TrimSpace(schedule.CronExpr)
+	if schedule.CronExpr != "" {
+		if _, err := scheduler.ParseCron(schedule.CronExpr); err != nil {
+			return err
+		}
+	} else {
+		if len(schedule.Rules) == 0 {
+			return fmt.Errorf("请填写 cron 表达式或发布规则")
+		}
+		for _, rule := range schedule.Rules {
+			if len(rule.Times) == 0 {
+				return fmt.Errorf("发布规则缺少时间")
+			}
+			for _, t := range rule.Times {
+				if _, err := time.Parse("15:04", t); err != nil {
+					return fmt.Errorf("无效时间: %s", t)
+				}
+			}
+			for _, d := range rule.Weekdays {
+				if d < 0 || d > 6 {
+					return fmt.Errorf("无效星期: %d", d)
+				}
+			}
+		}
+	}
+
+	for _, d := range schedule.ExcludeDates {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return fmt.Errorf("无效排除日期: %s", d)
+		}
+	}
+
+	next, err := NextScheduleSlot(schedule, time.Now(), s.location(ctx, schedule))
+	if err != nil {
+		return err
+	}
+	if next.IsZero() {
+		return fmt.Errorf("计划没有可用的发布时段")
+	}
+	return nil
+}
+
+// pickPlaylistVideo 从播放列表取出 index 处的视频，返回视频ID和下一个位置
+// 播放列表用完且不循环时返回 0
+func pickPlaylistVideo(schedule *database.RecurringSchedule, index int) (int, int) {
+	if len(schedule.VideoIDs) == 0 {
+		return 0, index
+	}
+	if index >= len(schedule.VideoIDs) {
+		if !schedule.Loop {
+			return 0, index
+		}
+		index = 0
+	}
+	return schedule.VideoIDs[index], index + 1
+}
+
+// NextScheduleSlot 计算计划在 after 之后（不含）的下一个发布时段，跳过排除日期
+// 找不到时返回零值
+func NextScheduleSlot(schedule *database.RecurringSchedule, after time.Time, loc *time.Location) (time.Time, error) {
+	excluded := make(map[string]bool, len(schedule.ExcludeDates))
+	for _, d := range schedule.ExcludeDates {
+		excluded[d] = true
+	}
+
+	var next func(time.Time) time.Time
+	if schedule.CronExpr != "" {
+		spec, err := scheduler.ParseCron(schedule.CronExpr)
+		if err != nil {
+			return time.Time{}, err
+		}
+		next = spec.Next
+	} else {
+		next = func(t time.Time) time.Time { return nextRuleSlot(schedule.Rules, t) }
+	}
+
+	t := after.In(loc)
+	for i := 0; i < 1000; i++ {
+		t = next(t)
+		if t.IsZero() {
+			return t, nil
+		}
+		if !excluded[t.Format("2006-01-02")] {
+			return t, nil
+		}
+		// 跳到排除日期的最后一分钟，下一次从次日开始查找
+		t = time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 0, 0, loc)
+	}
+	return time.Time{}, nil
+}
+
+// nextRuleSlot 按星期/时间规则计算 after 之后的下一个时段
+func nextRuleSlot(rules []database.WeekdayRule, after time.Time) time.Time {
+	loc := after.Location()
+	for day := 0; day <= 7; day++ {
+		date := time.Date(after.Year(), after.Month(), after.Day()+day, 0, 0, 0, 0, loc)
+		var best time.Time
+		for _, rule := range rules {
+			if !weekdayMatches(rule.Weekdays, date.Weekday()) {
+				continue
+			}
+			for _, ts := range rule.Times {
+				hm, err := time.Parse("15:04", ts)
+				if err != nil {
+					continue
+				}
+				candidate := time.Date(date.Year(), date.Month(), date.Day(), hm.Hour(), hm.Minute(), 0, 0, loc)
+				if candidate.After(after) && (best.IsZero() || candidate.Before(best)) {
+					best = candidate
+				}
+			}
+		}
+		if !best.IsZero() {
+			return best
+		}
+	}
+	return time.Time{}
+}
+
+// weekdayMatches 星期列表为空表示每天
+func weekdayMatches(weekdays []int, wd time.Weekday) bool {
+	if len(weekdays) == 0 {
+		return true
+	}
+	for _, d := range weekdays {
+		if d == int(wd) {
+			return true
+		}
+	}
+	return false
+}
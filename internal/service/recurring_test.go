@@ -0,0 +1,190 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNextScheduleSlot(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-03-05 是周二
+	from := time.Date(2024, 3, 5, 20, 30, 0, 0, loc)
+
+	t.Run("weekday_rules", func(t *testing.T) {
+		schedule := &database.RecurringSchedule{
+			Rules: []database.WeekdayRule{
+				{Weekdays: []int{1, 3, 5}, Times: []string{"19:00"}},
+				{Weekdays: []int{6}, Times: []string{"10:00", "21:00"}},
+			},
+		}
+		got, err := NextScheduleSlot(schedule, from, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := time.Date(2024, 3, 6, 19, 0, 0, 0, loc); !got.Equal(want) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("exclude_dates", func(t *testing.T) {
+		schedule := &database.RecurringSchedule{
+			CronExpr:     "0 9,18 * * *",
+			ExcludeDates: []string{"2024-03-06", "2024-03-07"},
+		}
+		got, err := NextScheduleSlot(schedule, from, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := time.Date(2024, 3, 8, 9, 0, 0, 0, loc); !got.Equal(want) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("playlist", func(t *testing.T) {
+		schedule := &database.RecurringSchedule{VideoIDs: []int{7, 8}}
+		if id, next := pickPlaylistVideo(schedule, 1); id != 8 || next != 2 {
+			t.Errorf("got %d/%d", id, next)
+		}
+		if id, _ := pickPlaylistVideo(schedule, 2); id != 0 {
+			t.Errorf("播放列表用完应返回 0: %d", id)
+		}
+		schedule.Loop = true
+		if id, next := pickPlaylistVideo(schedule, 2); id != 7 || next != 1 {
+			t.Errorf("循环播放错误: %d/%d", id, next)
+		}
+	})
+}
+
+func TestSetScheduleEnabled(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewRecurringScheduleService(db, nil, NewScheduleService(db), nil)
+	ctx := context.Background()
+
+	schedule := &database.RecurringSchedule{
+		Name:       "每日",
+		Enabled:    true,
+		TimeZone:   "Asia/Shanghai",
+		CronExpr:   "0 9 * * *",
+		AccountIDs: []int{1},
+		VideoIDs:   []int{1},
+	}
+	if err := svc.CreateSchedule(ctx, schedule); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.SetScheduleEnabled(ctx, schedule.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.GetScheduleByID(ctx, schedule.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Enabled || got.NextRunAt != nil {
+		t.Errorf("disabled: enabled=%v nextRunAt=%v, want false/nil", got.Enabled, got.NextRunAt)
+	}
+
+	if err := svc.SetScheduleEnabled(ctx, schedule.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	got, err = svc.GetScheduleByID(ctx, schedule.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Enabled || got.NextRunAt == nil {
+		t.Fatalf("re-enabled: enabled=%v nextRunAt=%v, want true/non-nil", got.Enabled, got.NextRunAt)
+	}
+	if !got.NextRunAt.After(time.Now()) {
+		t.Errorf("nextRunAt %s should be in the future", got.NextRunAt)
+	}
+}
+
+func TestFireSlotSkipsFailedVideo(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewRecurringScheduleService(db, NewUploadService(db, nil), NewScheduleService(db), nil)
+	ctx := context.Background()
+
+	// 视频不存在，创建上传任务失败
+	schedule := &database.RecurringSchedule{
+		Name:       "失败",
+		Enabled:    true,
+		CronExpr:   "0 9 * * *",
+		AccountIDs: []int{1},
+		VideoIDs:   []int{404, 405},
+	}
+	if err := svc.CreateSchedule(ctx, schedule); err != nil {
+		t.Fatal(err)
+	}
+	slotTime := time.Now().UTC()
+	svc.fireSlot(ctx, schedule, slotTime)
+
+	// 与队列中失败的条目一样，失败的视频被跳过，下一个时段发布下一个视频
+	got, err := svc.GetScheduleByID(ctx, schedule.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.NextVideoIndex != 1 || got.NextVideoIndex != 1 {
+		t.Errorf("NextVideoIndex = %d (stored %d), want 1 after failed slot", schedule.NextVideoIndex, got.NextVideoIndex)
+	}
+	svc.fireSlot(ctx, schedule, slotTime.Add(time.Hour))
+
+	slots, err := svc.GetSlotHistory(ctx, schedule.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 2 || slots[0].VideoID != 405 || slots[1].VideoID != 404 {
+		t.Fatalf("slots = %+v, want failed slots for videos 404 and 405", slots)
+	}
+	for _, slot := range slots {
+		if slot.Status != database.SlotStatusFailed {
+			t.Errorf("slot %d status = %s, want failed", slot.VideoID, slot.Status)
+		}
+	}
+}
+
+func TestCheckDueSchedulesRecordsMissedSlots(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewRecurringScheduleService(db, nil, NewScheduleService(db), nil)
+	ctx := context.Background()
+
+	schedule := &database.RecurringSchedule{
+		Name:       "每小时",
+		Enabled:    true,
+		TimeZone:   "UTC",
+		CronExpr:   "0 * * * *",
+		AccountIDs: []int{1},
+		VideoIDs:   []int{1},
+	}
+	if err := svc.CreateSchedule(ctx, schedule); err != nil {
+		t.Fatal(err)
+	}
+	// 应用停止运行 5 个小时：最早的时段和之后到期的 5 个时段都应记录为错过
+	now := time.Now().UTC()
+	first := now.Truncate(time.Hour).Add(-5 * time.Hour)
+	if err := db.Model(schedule).UpdateColumn("next_run_at", first).Error; err != nil {
+		t.Fatal(err)
+	}
+	svc.checkDueSchedules(now)
+
+	slots, err := svc.GetSlotHistory(ctx, schedule.ID, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 6 {
+		t.Fatalf("recorded %d slots, want 6", len(slots))
+	}
+	for i, slot := range slots {
+		want := first.Add(time.Duration(5-i) * time.Hour)
+		if slot.Status != database.SlotStatusMissed || !slot.SlotTime.Equal(want) {
+			t.Errorf("slots[%d] = %s at %s, want missed at %s", i, slot.Status, slot.SlotTime, want)
+		}
+	}
+	got, err := svc.GetScheduleByID(ctx, schedule.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NextRunAt == nil || !got.NextRunAt.After(now) {
+		t.Errorf("NextRunAt = %v, want after %s", got.NextRunAt, now)
+	}
+}
@@ -9,12 +9,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// maxEmptyScheduleDays 连续多少天没有可用时间点时停止生成（避免禁发时段覆盖全部时间点时死循环）
+const maxEmptyScheduleDays = 366
+
 type ScheduleService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	blackouts *BlackoutService
 }
 
 func NewScheduleService(db *gorm.DB) *ScheduleService {
-	return &ScheduleService{db: db}
+	return &ScheduleService{db: db, blackouts: NewBlackoutService(db)}
+}
+
+// Blackouts 禁发时段服务
+func (s *ScheduleService) Blackouts() *BlackoutService {
+	return s.blackouts
 }
 
 func (s *ScheduleService) GetScheduleConfig(ctx context.Context) (*database.ScheduleConfig, error) {
@@ -41,6 +50,43 @@ func (s *ScheduleService) UpdateScheduleConfig(ctx context.Context, cfg *databas
 	return nil
 }
 
+// Location 解析时区，tz 为空或无效时使用发布配置的时区，再退回本地时区
+func (s *ScheduleService) Location(ctx context.Context, tz string) *time.Location {
+	if tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	if cfg, err := s.GetScheduleConfig(ctx); err == nil {
+		if loc, err := time.LoadLocation(cfg.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// SetAccountTimeZone 设置账号时区，tz 为空表示使用发布配置的时区
+func (s *ScheduleService) SetAccountTimeZone(ctx context.Context, accountID int, tz string) error {
+	if tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("无效时区: %s", tz)
+		}
+	}
+	result := s.db.Model(&database.Account{}).Where("id = ?", accountID).UpdateColumn("time_zone", tz)
+	if result.Error != nil {
+		return fmt.Errorf("update account time zone failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("account not found")
+	}
+	return nil
+}
+
+// AccountLocation 账号时区
+func (s *ScheduleService) AccountLocation(ctx context.Context, account *database.Account) *time.Location {
+	return s.Location(ctx, account.TimeZone)
+}
+
 func (s *ScheduleService) GenerateScheduleTimes(ctx context.Context, videoCount int) ([]time.Time, error) {
 	cfg, err := s.GetScheduleConfig(ctx)
 	if err != nil {
@@ -63,8 +109,13 @@ func (s *ScheduleService) GenerateScheduleTimes(ctx context.Context, videoCount
 	var scheduleTimes []time.Time
 	currentDay := startDate
 	count := 0
+	emptyDays := 0
 
 	for count < videoCount {
+		if emptyDays > maxEmptyScheduleDays {
+			return scheduleTimes, fmt.Errorf("未来 %d 天内没有可用的发布时间点，请检查每日发布时间和禁发时段", maxEmptyScheduleDays)
+		}
+		dayCount := count
 		for _, timeStr := range dailyTimes {
 			if count >= videoCount {
 				break
@@ -90,9 +141,19 @@ func (s *ScheduleService) GenerateScheduleTimes(ctx context.Context, videoCount
 				continue
 			}
 
+			// 落在全局禁发时段内的时间点跳过，顺延到下一个可用时间点
+			if hit := s.blackouts.Check(nil, scheduleTime, loc); hit != nil {
+				continue
+			}
+
 			scheduleTimes = append(scheduleTimes, scheduleTime)
 			count++
 		}
+		if count == dayCount {
+			emptyDays++
+		} else {
+			emptyDays = 0
+		}
 		currentDay = currentDay.AddDate(0, 0, 1)
 	}
 
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/platformutils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TagSetService 标签组服务
+type TagSetService struct {
+	db *gorm.DB
+}
+
+// NewTagSetService 创建标签组服务
+func NewTagSetService(db *gorm.DB) *TagSetService {
+	return &TagSetService{db: db}
+}
+
+// GetTagSets 获取所有标签组，按使用次数排序
+func (s *TagSetService) GetTagSets(ctx context.Context) ([]database.TagSet, error) {
+	var sets []database.TagSet
+	if err := s.db.Order("usage_count DESC, id ASC").Find(&sets).Error; err != nil {
+		return nil, fmt.Errorf("query tag sets failed: %w", err)
+	}
+	return sets, nil
+}
+
+// GetTagSetByID 根据ID获取标签组
+func (s *TagSetService) GetTagSetByID(ctx context.Context, id int) (*database.TagSet, error) {
+	var set database.TagSet
+	if err := s.db.First(&set, id).Error; err != nil {
+		return nil, fmt.Errorf("tag set not found: %w", err)
+	}
+	return &set, nil
+}
+
+// CreateTagSet 创建标签组
+func (s *TagSetService) CreateTagSet(ctx context.Context, set *database.TagSet) error {
+	if err := validateTagSet(set); err != nil {
+		return err
+	}
+	set.ID = 0
+	set.UsageCount = 0
+	set.LastUsedAt = nil
+	if err := s.db.Create(set).Error; err != nil {
+		return fmt.Errorf("create tag set failed: %w", err)
+	}
+	return nil
+}
+
+// UpdateTagSet 更新标签组（不修改使用统计）
+func (s *TagSetService) UpdateTagSet(ctx context.Context, set *database.TagSet) error {
+	if err := validateTagSet(set); err != nil {
+		return err
+	}
+	existing, err := s.GetTagSetByID(ctx, set.ID)
+	if err != nil {
+		return err
+	}
+	set.UsageCount = existing.UsageCount
+	set.LastUsedAt = existing.LastUsedAt
+	set.CreatedAt = existing.CreatedAt
+	if err := s.db.Save(set).Error; err != nil {
+		return fmt.Errorf("update tag set failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteTagSet 删除标签组
+func (s *TagSetService) DeleteTagSet(ctx context.Context, id int) error {
+	result := s.db.Delete(&database.TagSet{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("delete tag set failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("tag set not found")
+	}
+	return nil
+}
+
+// ExpandTagSet 将标签组展开为指定平台的标签列表（已应用别名和平台数量上限）
+func (s *TagSetService) ExpandTagSet(ctx context.Context, id int, platform string) ([]string, error) {
+	set, err := s.GetTagSetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tags, _ := platformutils.LimitTags(platform, ExpandTagEntries(set.Entries, platform))
+	return tags, nil
+}
+
+// RecordUsage 记录标签组使用次数
+func (s *TagSetService) RecordUsage(id int) error {
+	return s.db.Model(&database.TagSet{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"usage_count":  gorm.Expr("usage_count + ?", 1),
+		"last_used_at": time.Now(),
+	}).Error
+}
+
+// ExpandTagEntries 按平台别名展开标签条目
+func ExpandTagEntries(entries []database.TagEntry, platform string) []string {
+	tags := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		tag := entry.Name
+		if alias, ok := entry.Aliases[platform]; ok && strings.TrimSpace(alias) != "" {
+			tag = alias
+		}
+		// "-" 表示该平台不使用此标签
+		if strings.TrimSpace(tag) == "-" {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// validateTagSet 校验标签组
+func validateTagSet(set *database.TagSet) error {
+	set.Name = strings.TrimSpace(set.Name)
+	if set.Name == "" {
+		return fmt.Errorf("标签组名称不能为空")
+	}
+
+	entries := set.Entries[:0]
+	for _, entry := range set.Entries {
+		entry.Name = strings.TrimSpace(strings.ReplaceAll(entry.Name, "#", ""))
+		if entry.Name == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	set.Entries = entries
+	return nil
+}
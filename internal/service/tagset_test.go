@@ -0,0 +1,72 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExpandTagEntries(t *testing.T) {
+	entries := []database.TagEntry{
+		{Name: "旅行", Aliases: map[string]string{"tiktok": "travel", "bilibili": " "}},
+		{Name: "美食", Aliases: map[string]string{"tiktok": "-"}},
+		{Name: "vlog"},
+	}
+	cases := map[string][]string{
+		"douyin":   {"旅行", "美食", "vlog"},
+		"bilibili": {"旅行", "美食", "vlog"}, // 空白别名回退到名称
+		"tiktok":   {"travel", "vlog"},
+	}
+	for platform, want := range cases {
+		if got := ExpandTagEntries(entries, platform); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, want %v", platform, got, want)
+		}
+	}
+}
+
+func TestExpandTagSet(t *testing.T) {
+	db := newTestDB(t)
+	svc := NewTagSetService(db)
+	ctx := context.Background()
+
+	// 重复条目、别名与其他条目重名、带 # 的名称都应在展开后去重
+	set := &database.TagSet{Name: " 出游 ", Entries: []database.TagEntry{
+		{Name: "#旅行#"},
+		{Name: "旅行"},
+		{Name: "假期", Aliases: map[string]string{"douyin": "#旅行"}},
+		{Name: "  "},
+		{Name: "风景"},
+		{Name: "日落"},
+		{Name: "海边"},
+		{Name: "周末"},
+		{Name: "自驾"},
+	}}
+	if err := svc.CreateTagSet(ctx, set); err != nil {
+		t.Fatal(err)
+	}
+	if set.Name != "出游" || len(set.Entries) != 8 || set.Entries[0].Name != "旅行" {
+		t.Errorf("validated set = %+v", set)
+	}
+
+	douyin, err := svc.ExpandTagSet(ctx, set.ID, "douyin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 抖音最多 5 个标签
+	if want := []string{"旅行", "风景", "日落", "海边", "周末"}; !reflect.DeepEqual(douyin, want) {
+		t.Errorf("douyin = %v, want %v", douyin, want)
+	}
+	bilibili, _ := svc.ExpandTagSet(ctx, set.ID, "bilibili")
+	if want := []string{"旅行", "假期", "风景", "日落", "海边", "周末", "自驾"}; !reflect.DeepEqual(bilibili, want) {
+		t.Errorf("bilibili = %v, want %v", bilibili, want)
+	}
+
+	// 同名标签组不能重复创建
+	if err := svc.CreateTagSet(ctx, &database.TagSet{Name: "出游"}); err == nil {
+		t.Error("expected duplicate tag set name error")
+	}
+	if _, err := svc.ExpandTagSet(ctx, set.ID+100, "douyin"); err == nil {
+		t.Error("expected missing tag set error")
+	}
+}
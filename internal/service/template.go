@@ -0,0 +1,196 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templateVarPattern 模板变量：{name} 或 {name:format}
+// 变量名支持中文，便于直接使用视频自定义字段（如 {系列}）
+var templateVarPattern = regexp.MustCompile(`\{([\p{L}\p{N}_]+)(?::([^{}]*))?\}`)
+
+// hashtagStyle 平台话题格式
+type hashtagStyle struct {
+	prefix    string
+	suffix    string
+	noSpace   bool // 话题内不允许空格（TikTok 英文话题）
+	separator string
+}
+
+// platformHashtagStyles 各平台在正文中的话题写法
+var platformHashtagStyles = map[string]hashtagStyle{
+	config.PlatformDouyin:      {prefix: "#", separator: " "},
+	config.PlatformKuaishou:    {prefix: "#", separator: " "},
+	config.PlatformTencent:     {prefix: "#", separator: " "},
+	config.PlatformXiaohongshu: {prefix: "#", separator: " "},
+	config.PlatformTiktok:      {prefix: "#", noSpace: true, separator: " "},
+	config.PlatformBilibili:    {prefix: "#", suffix: "#", separator: " "},
+	config.PlatformBaijiahao:   {prefix: "#", suffix: "#", separator: " "},
+}
+
+// TemplateData 模板渲染数据
+type TemplateData struct {
+	Video        *database.Video
+	Account      *database.Account
+	Platform     string
+	Tags         []string   // 为空时使用视频标签
+	ScheduleTime *time.Time // 为空时日期变量取当前时间
+}
+
+// FormatHashtags 按平台格式拼接话题
+func FormatHashtags(platform string, tags []string) string {
+	style, ok := platformHashtagStyles[platform]
+	if !ok {
+		style = hashtagStyle{prefix: "#", separator: " "}
+	}
+
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.Trim(tag, "#"))
+		if style.noSpace {
+			tag = strings.Join(strings.Fields(tag), "")
+		}
+		if tag == "" {
+			continue
+		}
+		parts = append(parts, style.prefix+tag+style.suffix)
+	}
+	return strings.Join(parts, style.separator)
+}
+
+// formatVideoDuration 将秒数格式化为 mm:ss 或 hh:mm:ss
+func formatVideoDuration(seconds float64) string {
+	total := int(seconds + 0.5)
+	h, m, s := total/3600, (total%3600)/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// BuildTemplateVars 构建模板变量表
+// 内置变量：filename、title、description、duration、date、time、weekday、
+// platform、account、username、tags，以及视频的自定义字段
+func BuildTemplateVars(data TemplateData) map[string]string {
+	vars := make(map[string]string)
+
+	at := time.Now()
+	if data.ScheduleTime != nil {
+		at = *data.ScheduleTime
+	}
+	vars["date"] = at.Format("2006-01-02")
+	vars["time"] = at.Format("15:04")
+	vars["weekday"] = []string{"日", "一", "二", "三", "四", "五", "六"}[at.Weekday()]
+	vars["platform"] = data.Platform
+
+	tags := data.Tags
+	if data.Video != nil {
+		name := data.Video.Filename
+		vars["filename"] = strings.TrimSuffix(name, filepath.Ext(name))
+		vars["title"] = data.Video.Title
+		vars["description"] = data.Video.Description
+		vars["duration"] = formatVideoDuration(data.Video.Duration)
+		vars["seconds"] = strconv.Itoa(int(data.Video.Duration + 0.5))
+		if len(tags) == 0 {
+			tags = data.Video.Tags
+		}
+	}
+	vars["tags"] = FormatHashtags(data.Platform, tags)
+
+	if data.Account != nil {
+		vars["account"] = data.Account.Name
+		vars["username"] = data.Account.Username
+	}
+
+	// 自定义字段不覆盖内置变量
+	if data.Video != nil {
+		for key, value := range data.Video.CustomFields {
+			if _, exists := vars[key]; !exists {
+				vars[key] = value
+			}
+		}
+	}
+
+	return vars
+}
+
+// RenderTemplate 渲染模板，返回结果和未定义的变量名
+// 未定义的变量保留原样，避免误删正文中的花括号内容
+func RenderTemplate(tpl string, vars map[string]string, at time.Time) (string, []string) {
+	if !strings.Contains(tpl, "{") {
+		return tpl, nil
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+
+	result := templateVarPattern.ReplaceAllStringFunc(tpl, func(match string) string {
+		sub := templateVarPattern.FindStringSubmatch(match)
+		name, format := sub[1], sub[2]
+
+		// 日期时间变量支持自定义 Go 时间格式，如 {date:01月02日}
+		if format != "" && (name == "date" || name == "time") {
+			return at.Format(format)
+		}
+
+		value, ok := vars[name]
+		if !ok {
+			if !seen[name] {
+				seen[name] = true
+				missing = append(missing, name)
+			}
+			return match
+		}
+		return value
+	})
+
+	return strings.TrimSpace(result), missing
+}
+
+// TemplateRenderer 绑定一组模板数据的渲染器，变量表只构建一次
+type TemplateRenderer struct {
+	vars    map[string]string
+	at      time.Time
+	missing []string
+}
+
+// NewTemplateRenderer 创建模板渲染器
+func NewTemplateRenderer(data TemplateData) *TemplateRenderer {
+	at := time.Now()
+	if data.ScheduleTime != nil {
+		at = *data.ScheduleTime
+	}
+	return &TemplateRenderer{
+		vars: BuildTemplateVars(data),
+		at:   at,
+	}
+}
+
+// Render 渲染模板，并累计未定义的变量
+func (r *TemplateRenderer) Render(tpl string) string {
+	result, missing := RenderTemplate(tpl, r.vars, r.at)
+	for _, name := range missing {
+		found := false
+		for _, m := range r.missing {
+			if m == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.missing = append(r.missing, name)
+		}
+	}
+	return result
+}
+
+// Missing 返回渲染过程中遇到的未定义变量
+func (r *TemplateRenderer) Missing() []string {
+	return r.missing
+}
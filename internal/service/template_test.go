@@ -0,0 +1,61 @@
+package service
+
+import (
+	"Fuploader/internal/database"
+	"testing"
+	"time"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	at := time.Date(2024, 3, 5, 20, 30, 0, 0, time.Local)
+	video := &database.Video{
+		Filename:     "ep12.mp4",
+		Duration:     95,
+		Tags:         []string{"vlog", "#旅行"},
+		CustomFields: map[string]string{"series": "周末出发", "episode": "12"},
+	}
+
+	t.Run("custom_fields_and_date", func(t *testing.T) {
+		r := NewTemplateRenderer(TemplateData{Video: video, Platform: "douyin", ScheduleTime: &at})
+		got := r.Render("{series} 第{episode}集 | {date}")
+		if got != "周末出发 第12集 | 2024-03-05" {
+			t.Errorf("渲染结果错误: %s", got)
+		}
+		if len(r.Missing()) != 0 {
+			t.Errorf("不应有未定义变量: %v", r.Missing())
+		}
+	})
+
+	t.Run("date_format_and_duration", func(t *testing.T) {
+		r := NewTemplateRenderer(TemplateData{Video: video, ScheduleTime: &at})
+		if got := r.Render("{date:01月02日} {time:15:04} {duration} {filename}"); got != "03月05日 20:30 01:35 ep12" {
+			t.Errorf("渲染结果错误: %s", got)
+		}
+	})
+
+	t.Run("platform_hashtags", func(t *testing.T) {
+		cases := map[string]string{
+			"douyin":   "#vlog #旅行",
+			"bilibili": "#vlog# #旅行#",
+		}
+		for platform, want := range cases {
+			r := NewTemplateRenderer(TemplateData{Video: video, Platform: platform, ScheduleTime: &at})
+			if got := r.Render("{tags}"); got != want {
+				t.Errorf("%s 话题格式错误: %s", platform, got)
+			}
+		}
+		if got := FormatHashtags("tiktok", []string{"day in my life"}); got != "#dayinmylife" {
+			t.Errorf("tiktok 话题不应包含空格: %s", got)
+		}
+	})
+
+	t.Run("missing_vars_kept", func(t *testing.T) {
+		r := NewTemplateRenderer(TemplateData{Video: video, ScheduleTime: &at})
+		if got := r.Render("{season} {series}"); got != "{season} 周末出发" {
+			t.Errorf("未定义变量应保留原文: %s", got)
+		}
+		if missing := r.Missing(); len(missing) != 1 || missing[0] != "season" {
+			t.Errorf("未定义变量错误: %v", missing)
+		}
+	})
+}
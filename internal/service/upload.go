@@ -4,18 +4,15 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"Fuploader/internal/config"
 	"Fuploader/internal/database"
-	"Fuploader/internal/platform/baijiahao"
-	"Fuploader/internal/platform/bilibili"
-	"Fuploader/internal/platform/douyin"
-	"Fuploader/internal/platform/kuaishou"
+	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/platformutils"
 	"Fuploader/internal/platform/ratelimit"
-	"Fuploader/internal/platform/tencent"
-	"Fuploader/internal/platform/tiktok"
-	"Fuploader/internal/platform/xiaohongshu"
+	"Fuploader/internal/scheduler"
 	"Fuploader/internal/types"
 	"Fuploader/internal/utils"
 
@@ -26,8 +23,22 @@ type UploadService struct {
 	db          *gorm.DB
 	eventBus    *EventBus
 	rateLimiter *ratelimit.LimiterWithStats
+	tagSets     *TagSetService
+	presets     *PresetService
+	schedules   *ScheduleService
+	jobs        *scheduler.EnhancedScheduler
+
+	ledger        *PublishLedger
+	accountLimits *AccountLimitService
+	proxies       *ProxyService
+	recordings    *RecordingService
+
+	pool *browser.Pool
 }
 
+// localScheduleGrace 本地定时任务晚于定时时间超过该值执行时视为应用未运行
+const localScheduleGrace = 5 * time.Minute
+
 // EventHandler 事件处理器函数类型
 type EventHandler func(event types.Event)
 
@@ -54,11 +65,21 @@ func (eb *EventBus) Publish(event string, data types.Event) {
 	}
 }
 
-func NewUploadService(db *gorm.DB) *UploadService {
+func NewUploadService(db *gorm.DB, pool *browser.Pool) *UploadService {
+	schedules := NewScheduleService(db)
+	ledger := NewPublishLedger(db, schedules)
 	return &UploadService{
-		db:          db,
-		eventBus:    NewEventBus(),
-		rateLimiter: ratelimit.NewLimiterWithStats(),
+		db:            db,
+		eventBus:      NewEventBus(),
+		rateLimiter:   ratelimit.NewLimiterWithStats(),
+		tagSets:       NewTagSetService(db),
+		presets:       NewPresetService(db),
+		schedules:     schedules,
+		ledger:        ledger,
+		accountLimits: NewAccountLimitService(db, ledger, schedules),
+		proxies:       NewProxyService(db),
+		recordings:    NewRecordingService(db),
+		pool:          pool,
 	}
 }
 
@@ -66,125 +87,783 @@ func (s *UploadService) GetEventBus() *EventBus {
 	return s.eventBus
 }
 
-// checkRateLimit 检查平台限流
-func (s *UploadService) checkRateLimit(platform string) error {
-	// 检查令牌桶限流
-	if !s.rateLimiter.Allow(platform) {
-		return fmt.Errorf("platform %s rate limit exceeded, please try again later", platform)
+// platformEarliestAllowed 按平台每日/每小时上传限制计算不早于 at 的最早发布时间，返回推迟原因
+// 已发布（发布台账）和尚未完成的任务都计入，excludeTaskID 为正在排期的任务自身
+func (s *UploadService) platformEarliestAllowed(ctx context.Context, platform string, at time.Time, excludeTaskID int) (time.Time, string) {
+	limit, ok := s.rateLimiter.GetLimit(platform)
+	if !ok || (limit.DailyLimit == 0 && limit.HourlyLimit == 0) {
+		return at, ""
+	}
+	publishes, err := s.accountLimits.PlatformPlannedPublishes(ctx, platform, at.Add(-ledgerDailyWindow), excludeTaskID)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 查询平台 %s 发布记录失败: %v", platform, err))
+		return at, ""
 	}
 
-	// 检查每日/每小时上传限制
-	var dailyCount, hourlyCount int64
-	today := time.Now().Format("2006-01-02")
-	hour := time.Now().Format("2006-01-02 15")
-
-	s.db.Model(&database.UploadTask{}).
-		Where("platform = ? AND DATE(created_at) = ? AND status = ?", platform, today, config.TaskStatusSuccess).
-		Count(&dailyCount)
-
-	s.db.Model(&database.UploadTask{}).
-		Where("platform = ? AND DATE_FORMAT(created_at, '%Y-%m-%d %H') = ? AND status = ?", platform, hour, config.TaskStatusSuccess).
-		Count(&hourlyCount)
+	next, _ := EarliestAllowedTime(database.AccountRateLimit{
+		DailyLimit:  limit.DailyLimit,
+		HourlyLimit: limit.HourlyLimit,
+	}, publishes, at)
+	if !next.After(at) {
+		return at, ""
+	}
+	daily := publishesIn(publishes, at.Add(-ledgerDailyWindow), at)
+	hourly := publishesIn(publishes, at.Add(-ledgerHourlyWindow), at)
+	reason := fmt.Sprintf("平台 %s 已达到上传限制", platform)
+	if err := s.rateLimiter.CheckUploadLimit(platform, len(daily), len(hourly)); err != nil {
+		reason = err.Error()
+	}
+	return next, reason
+}
 
-	if err := s.rateLimiter.CheckUploadLimit(platform, int(dailyCount), int(hourlyCount)); err != nil {
-		return err
+// RestoreRateLimitState 从发布台账恢复限流状态（应用启动时调用）
+func (s *UploadService) RestoreRateLimitState() {
+	if err := s.ledger.Backfill(context.Background()); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 导入历史发布记录失败: %v", err))
+	}
+	now := time.Now()
+	for platform := range ratelimit.DefaultLimits {
+		history, err := s.ledger.PublishTimes(platform, now.Add(-ledgerDailyWindow), now)
+		if err != nil {
+			utils.Warn(fmt.Sprintf("[-] 恢复平台 %s 限流状态失败: %v", platform, err))
+			continue
+		}
+		s.rateLimiter.Restore(platform, history)
 	}
+}
 
-	return nil
+// recordPublish 将上传成功的任务记入发布台账，草稿不计入
+func (s *UploadService) recordPublish(ctx context.Context, task *database.UploadTask) {
+	if task.IsDraft {
+		return
+	}
+	now := time.Now()
+	publishedAt, scheduled := now, false
+	if task.ScheduleMode != config.ScheduleModeLocal {
+		if t := parseTaskScheduleTime(task.ScheduleTime, s.schedules.AccountLocation(ctx, &task.Account)); t != nil {
+			publishedAt, scheduled = *t, true
+		}
+	}
+	if err := s.ledger.Record(task, publishedAt, scheduled); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 记录发布台账失败: %v", err))
+	}
+	s.rateLimiter.Record(task.Platform, now)
 }
 
+// CreateUploadTask 为各账号创建上传任务
 func (s *UploadService) CreateUploadTask(ctx context.Context, videoID int, accountIDs []int, scheduleTime *string, metadata *UploadTaskMetadata) ([]database.UploadTask, error) {
+	tasks, _, err := s.CreateUploadTaskWithReport(ctx, videoID, accountIDs, scheduleTime, metadata)
+	return tasks, err
+}
+
+// CreateUploadTaskWithReport 为各账号创建上传任务，并返回每个账号的创建结果
+// 触发限流的账号不会被跳过，而是创建排队任务（rate_limited），到最早可发布时间后再上传
+func (s *UploadService) CreateUploadTaskWithReport(ctx context.Context, videoID int, accountIDs []int, scheduleTime *string, metadata *UploadTaskMetadata) ([]database.UploadTask, []types.UploadTaskReport, error) {
 	var video database.Video
 	if result := s.db.First(&video, videoID); result.Error != nil {
-		return nil, fmt.Errorf("video not found")
+		return nil, nil, fmt.Errorf("video not found")
 	}
 
 	var tasks []database.UploadTask
+	var errs []string
+	reports := make([]types.UploadTaskReport, 0, len(accountIDs))
 	for _, accountID := range accountIDs {
+		report := types.UploadTaskReport{AccountID: accountID, Result: types.TaskReportFailed}
+		fail := func(err error) {
+			report.Reason = err.Error()
+			reports = append(reports, report)
+			errs = append(errs, fmt.Sprintf("%s: %v", report.AccountName, err))
+		}
+
 		var account database.Account
 		if result := s.db.First(&account, accountID); result.Error != nil {
+			report.AccountName = fmt.Sprintf("#%d", accountID)
+			fail(fmt.Errorf("账号不存在"))
 			continue
 		}
-
-		// 检查限流
-		if err := s.checkRateLimit(account.Platform); err != nil {
-			utils.Warn(fmt.Sprintf("[-] 平台 %s 限流检查失败: %v", account.Platform, err))
+		report.AccountName = account.Name
+		report.Platform = account.Platform
+
+		// 平台限流、账号限额和禁发时段：定时时间推迟到最早可发布时间，立即上传改为届时本地定时执行
+		deferral, err := s.applyPublishConstraints(ctx, &account, scheduleTime, 0)
+		if err != nil {
+			utils.Warn(fmt.Sprintf("[-] 账号 %s %v", account.Name, err))
+			fail(err)
 			continue
 		}
+		accountScheduleTime := deferral.ScheduleTime
 
-		// 无论是否有定时时间，都立即执行任务（由平台处理定时发布）
-		task := database.UploadTask{
-			VideoID:      videoID,
-			AccountID:    accountID,
-			Platform:     account.Platform,
-			Status:       config.TaskStatusUploading,
-			Progress:     0,
-			ScheduleTime: scheduleTime,
+		task, missing := s.buildUploadTask(&video, &account, accountScheduleTime, metadata)
+		if len(missing) > 0 {
+			utils.Warn(fmt.Sprintf("[-] 账号 %s 的模板变量未定义，已保留原文: %s", account.Name, strings.Join(missing, ", ")))
 		}
-
-		// 应用通用标题（如果用户填写了）
-		if metadata != nil && metadata.Common.Title != "" {
-			task.Title = metadata.Common.Title
+		// 被限流的任务在本地排队，到时间后再上传；立即上传被推迟时同样改为本地定时
+		if deferral.RateLimited || (deferral.Immediate && deferral.Note != "") {
+			task.ScheduleMode = config.ScheduleModeLocal
+		}
+		task.DeferReason = deferral.Note
+
+		// 按账号时区和平台规则校验定时时间，并确定定时方式
+		var fireAt time.Time
+		if accountScheduleTime != nil && *accountScheduleTime != "" {
+			mode, at, err := s.resolveScheduleMode(ctx, &account, *accountScheduleTime, task.IsDraft, task.ScheduleMode)
+			if err != nil {
+				utils.Warn(fmt.Sprintf("[-] 账号 %s 定时时间无效: %v", account.Name, err))
+				fail(err)
+				continue
+			}
+			task.ScheduleMode = mode
+			fireAt = at
+		} else {
+			task.ScheduleMode = config.ScheduleModeAuto
 		}
 
-		// 应用平台特定字段
-		if metadata != nil && metadata.Platforms != nil {
-			if platformFields, ok := metadata.Platforms[account.Platform]; ok {
-				// 应用平台特定字段到任务
-				s.applyPlatformFields(&task, platformFields)
+		// 平台定时或无定时：立即上传；本地定时：保存为待执行任务，到时间后再上传
+		local := task.ScheduleMode == config.ScheduleModeLocal
+		task.Status = config.TaskStatusUploading
+		report.Result = types.TaskReportStarted
+		if local {
+			task.Status = config.TaskStatusPending
+			report.Result = types.TaskReportScheduled
+			if deferral.Note != "" {
+				report.Result = types.TaskReportDeferred
+			}
+			if deferral.RateLimited {
+				task.Status = config.TaskStatusRateLimited
+				report.Result = types.TaskReportRateLimited
 			}
 		}
 
 		result := s.db.Create(&task)
 		if result.Error != nil {
 			utils.Error(fmt.Sprintf("Create task failed: %v", result.Error))
+			report.Result = types.TaskReportFailed
+			fail(result.Error)
 			continue
 		}
+		if deferral.Note != "" {
+			s.createUploadLog(task.ID, "defer", deferral.Note)
+		}
+		if deferral.RateLimited {
+			s.rateLimiter.Reject(account.Platform)
+		}
+
+		if local {
+			if err := s.scheduleLocalTask(&task, &video, fireAt); err != nil {
+				s.updateTaskFailed(task.ID, err.Error())
+				report.Result = types.TaskReportFailed
+				report.TaskID = task.ID
+				fail(err)
+				continue
+			}
+		}
 
 		tasks = append(tasks, task)
+		report.TaskID = task.ID
+		report.Reason = deferral.Note
+		if accountScheduleTime != nil {
+			report.ScheduleTime = *accountScheduleTime
+		}
+		reports = append(reports, report)
+
+		if task.TagSetID > 0 {
+			if err := s.tagSets.RecordUsage(task.TagSetID); err != nil {
+				utils.Warn(fmt.Sprintf("[-] 更新标签组使用次数失败: %v", err))
+			}
+		}
 
-		// 立即执行上传任务（平台会处理定时发布逻辑）
+		if !local {
+			// 立即执行上传任务（平台会处理定时发布逻辑）
+			go s.executeTask(context.Background(), task.ID)
+		}
+	}
+
+	if len(tasks) == 0 && len(errs) > 0 {
+		return nil, reports, fmt.Errorf("创建任务失败: %s", strings.Join(errs, "; "))
+	}
+	return tasks, reports, nil
+}
+
+// SetJobScheduler 设置执行本地定时任务的调度器
+func (s *UploadService) SetJobScheduler(jobs *scheduler.EnhancedScheduler) {
+	s.jobs = jobs
+}
+
+// resolveScheduleMode 确定定时方式并校验定时时间
+// 自动模式下优先使用平台定时，草稿或超出平台定时范围时改用本地定时；
+// 返回本地定时的执行时间
+func (s *UploadService) resolveScheduleMode(ctx context.Context, account *database.Account, scheduleTime string, isDraft bool, requested string) (string, time.Time, error) {
+	_, checkErr := s.CheckScheduleTime(ctx, account, scheduleTime)
+
+	switch requested {
+	case config.ScheduleModeNative:
+		if checkErr != nil {
+			return "", time.Time{}, checkErr
+		}
+		if isDraft {
+			return "", time.Time{}, fmt.Errorf("草稿不支持平台定时，请使用本地定时")
+		}
+		return config.ScheduleModeNative, time.Time{}, nil
+	case config.ScheduleModeAuto, config.ScheduleModeLocal:
+		if requested == config.ScheduleModeAuto && checkErr == nil && !isDraft {
+			return config.ScheduleModeNative, time.Time{}, nil
+		}
+		at, err := utils.ParseScheduleTimeIn(scheduleTime, s.schedules.AccountLocation(ctx, account))
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if !at.After(time.Now()) {
+			return "", time.Time{}, fmt.Errorf("定时时间必须在未来")
+		}
+		if requested == config.ScheduleModeAuto {
+			reason := "草稿不支持平台定时"
+			if checkErr != nil {
+				reason = checkErr.Error()
+			}
+			utils.Warn(fmt.Sprintf("[-] 账号 %s 无法使用平台定时（%s），改用本地定时", account.Name, reason))
+		}
+		return config.ScheduleModeLocal, at, nil
+	default:
+		return "", time.Time{}, fmt.Errorf("未知的定时方式: %s", requested)
+	}
+}
+
+// publishDeferral 发布推迟结果
+type publishDeferral struct {
+	ScheduleTime *string // 推迟后的定时时间（未推迟时为原值）
+	Note         string  // 推迟说明，未推迟时为空
+	Immediate    bool    // 原本为立即上传
+	RateLimited  bool    // 因平台/账号限流推迟
+}
+
+// applyPublishConstraints 按平台限流、账号限额和禁发时段调整定时时间（无定时时间时为当前时间）
+// 命中阻止策略的禁发时段时返回错误。excludeTaskID 为正在排期的任务自身
+func (s *UploadService) applyPublishConstraints(ctx context.Context, account *database.Account, scheduleTime *string, excludeTaskID int) (publishDeferral, error) {
+	loc := s.schedules.AccountLocation(ctx, account)
+	result := publishDeferral{
+		ScheduleTime: scheduleTime,
+		Immediate:    scheduleTime == nil || *scheduleTime == "",
+	}
+
+	at := time.Now()
+	if !result.Immediate {
+		t, err := utils.ParseScheduleTimeIn(*scheduleTime, loc)
+		if err != nil {
+			// 格式错误交由定时校验报告
+			return result, nil
+		}
+		at = t
+	}
+
+	t, reasons, rateLimited, err := s.earliestPublishTime(ctx, account, at, loc, excludeTaskID)
+	if err != nil {
+		if result.Immediate {
+			return result, fmt.Errorf("当前%v", err)
+		}
+		return result, fmt.Errorf("定时时间%v", err)
+	}
+	if !t.After(at) {
+		return result, nil
+	}
+
+	// 定时时间精确到分钟，向上取整以免早于限流和禁发时段允许的时间
+	t = platformutils.CeilTime(t, time.Minute)
+	shifted := utils.FormatScheduleTime(t.In(loc))
+	result.ScheduleTime = &shifted
+	result.RateLimited = rateLimited
+	result.Note = fmt.Sprintf("定时时间 %s 推迟至 %s：%s", utils.FormatScheduleTime(at.In(loc)), shifted, strings.Join(reasons, "；"))
+	if result.Immediate {
+		result.Note = fmt.Sprintf("推迟至 %s 上传：%s", shifted, strings.Join(reasons, "；"))
+	}
+	utils.Info(fmt.Sprintf("[-] 账号 %s %s", account.Name, result.Note))
+	return result, nil
+}
+
+// earliestPublishTime 计算不早于 at、满足平台限流和账号限额且不在禁发时段内的最早发布时间
+// 返回推迟原因，以及是否因限流推迟
+func (s *UploadService) earliestPublishTime(ctx context.Context, account *database.Account, at time.Time, loc *time.Location, excludeTaskID int) (time.Time, []string, bool, error) {
+	t := at
+	var reasons []string
+	rateLimited := false
+
+	// 令牌桶限制的是上传请求频率，与发布时间无关，只需在上传时刻检查
+	if !s.rateLimiter.Check(account.Platform) {
+		if min := time.Now().Add(s.rateLimiter.Delay(account.Platform)); min.After(t) {
+			t = min
+			reasons = append(reasons, fmt.Sprintf("平台 %s 请求过于频繁", account.Platform))
+			rateLimited = true
+		}
+	}
+
+	for i := 0; i < maxBlackoutChain; i++ {
+		next, reason := s.platformEarliestAllowed(ctx, account.Platform, t, excludeTaskID)
+		if next.After(t) {
+			reasons = appendReason(reasons, reason)
+			rateLimited = true
+		}
+		if n, reason := s.accountLimits.EarliestAllowed(ctx, account, next, excludeTaskID); n.After(next) {
+			reasons = appendReason(reasons, reason)
+			rateLimited = true
+			next = n
+		}
+		if hit := s.schedules.Blackouts().Check(account, next, loc); hit != nil {
+			if hit.Blocked {
+				return time.Time{}, nil, false, fmt.Errorf("%s", hit.Message())
+			}
+			reasons = appendReason(reasons, fmt.Sprintf("处于禁发时段「%s」", hit.Window.Name))
+			next = hit.Until
+		}
+		if !next.After(t) {
+			break
+		}
+		t = next
+	}
+	return t, reasons, rateLimited, nil
+}
+
+// appendReason 追加不重复的推迟原因
+func appendReason(reasons []string, reason string) []string {
+	for _, r := range reasons {
+		if r == reason {
+			return reasons
+		}
+	}
+	return append(reasons, reason)
+}
+
+// scheduleLocalTask 将本地定时任务交给调度器，到时间后执行上传
+func (s *UploadService) scheduleLocalTask(task *database.UploadTask, video *database.Video, fireAt time.Time) error {
+	if s.jobs == nil {
+		return fmt.Errorf("任务调度器未启动，无法使用本地定时")
+	}
+
+	job := &database.ScheduledTask{
+		ID:           fmt.Sprintf("upload_%d_%d", task.ID, time.Now().UnixNano()),
+		AccountID:    uint(task.AccountID),
+		Platform:     task.Platform,
+		VideoPath:    video.FilePath,
+		Title:        task.Title,
+		Description:  task.Description,
+		ScheduleTime: fireAt,
+		Priority:     database.PriorityNormal,
+		UploadTaskID: task.ID,
+	}
+	if err := s.jobs.AddTask(job); err != nil {
+		return fmt.Errorf("添加本地定时任务失败: %w", err)
+	}
+
+	s.createUploadLog(task.ID, "schedule_local", fmt.Sprintf("本地定时：将于 %s 上传并发布，届时需保持 Fuploader 运行",
+		fireAt.Local().Format("2006-01-02 15:04")))
+	return nil
+}
+
+// deferTask 将任务推迟到指定时间（向上取整到分钟）本地执行，因限流推迟时状态为 rate_limited
+func (s *UploadService) deferTask(task *database.UploadTask, at time.Time, loc *time.Location, rateLimited bool, note string) error {
+	oldStatus := task.Status
+	at = platformutils.CeilTime(at, time.Minute)
+	shifted := utils.FormatScheduleTime(at.In(loc))
+	task.ScheduleTime = &shifted
+	task.ScheduleMode = config.ScheduleModeLocal
+	task.DeferReason = note
+	task.Status = config.TaskStatusPending
+	if rateLimited {
+		task.Status = config.TaskStatusRateLimited
+	}
+	if err := s.db.Model(task).Updates(map[string]interface{}{
+		"schedule_time": shifted,
+		"schedule_mode": config.ScheduleModeLocal,
+		"status":        task.Status,
+		"defer_reason":  note,
+		"error_msg":     "",
+	}).Error; err != nil {
+		return fmt.Errorf("更新定时时间失败: %w", err)
+	}
+
+	var video database.Video
+	if err := s.db.First(&video, task.VideoID).Error; err != nil {
+		return fmt.Errorf("视频不存在: %w", err)
+	}
+	s.createUploadLog(task.ID, "defer", note)
+	if rateLimited {
+		s.rateLimiter.Reject(task.Platform)
+	}
+	if err := s.scheduleLocalTask(task, &video, at); err != nil {
+		s.updateTaskFailed(task.ID, err.Error())
+		return err
+	}
+
+	if oldStatus != task.Status {
+		s.eventBus.Publish(config.EventTaskStatusChanged, types.TaskStatusChangedEvent{
+			TaskID:    task.ID,
+			OldStatus: oldStatus,
+			NewStatus: task.Status,
+		})
+	}
+	return nil
+}
+
+// ExecuteLocalScheduledTask 执行到期的本地定时任务（由调度器调用）
+func (s *UploadService) ExecuteLocalScheduledTask(ctx context.Context, job *database.ScheduledTask) error {
+	var task database.UploadTask
+	if err := s.db.First(&task, job.UploadTaskID).Error; err != nil {
+		return fmt.Errorf("上传任务不存在: %w", err)
+	}
+	if task.Status != config.TaskStatusPending && task.Status != config.TaskStatusRateLimited {
+		utils.Warn(fmt.Sprintf("[-] 上传任务 %d 状态为 %s，跳过本地定时执行", task.ID, task.Status))
+		return nil
+	}
+	oldStatus := task.Status
+
+	// 到期时再次检查限流、账号限额和禁发时段（排期后可能发生变化），推迟或阻止
+	var account database.Account
+	if err := s.db.First(&account, task.AccountID).Error; err == nil {
+		loc := s.schedules.AccountLocation(ctx, &account)
+		now := time.Now()
+		next, reasons, rateLimited, err := s.earliestPublishTime(ctx, &account, now, loc, task.ID)
+		if err != nil {
+			msg := fmt.Sprintf("执行时%v", err)
+			s.updateTaskFailed(task.ID, msg)
+			return fmt.Errorf("%s", msg)
+		}
+		if next.After(now) {
+			next = platformutils.CeilTime(next, time.Minute)
+			if err := s.deferTask(&task, next, loc, rateLimited,
+				fmt.Sprintf("执行时推迟至 %s：%s", utils.FormatScheduleTime(next.In(loc)), strings.Join(reasons, "；"))); err != nil {
+				return err
+			}
+			return nil
+		}
+	}
+
+	// 应用在定时时间未运行时，启动后补发并提示
+	if delay := time.Since(job.ScheduleTime); delay > localScheduleGrace {
+		msg := fmt.Sprintf("定时时间 %s 时应用未运行，已延迟 %s 执行",
+			job.ScheduleTime.Local().Format("2006-01-02 15:04"), utils.FormatDuration(delay))
+		utils.Warn(fmt.Sprintf("[-] 上传任务 %d %s", task.ID, msg))
+		s.createUploadLog(task.ID, "schedule_late", msg)
+	}
+
+	if err := s.db.Model(&task).Update("status", config.TaskStatusUploading).Error; err != nil {
+		return fmt.Errorf("更新任务状态失败: %w", err)
+	}
+	s.eventBus.Publish(config.EventTaskStatusChanged, types.TaskStatusChangedEvent{
+		TaskID:    task.ID,
+		OldStatus: oldStatus,
+		NewStatus: config.TaskStatusUploading,
+	})
+
+	s.executeTask(ctx, task.ID)
+
+	if err := s.db.First(&task, task.ID).Error; err != nil {
+		return err
+	}
+	if task.Status == config.TaskStatusFailed {
+		return fmt.Errorf("%s", task.ErrorMsg)
+	}
+	return nil
+}
+
+// RescheduleUploadTask 修改任务的定时时间（日历拖拽改期）
+// 本地定时任务直接改期；失败的任务按新时间重新排期；已提交到平台定时的任务无法自动修改
+func (s *UploadService) RescheduleUploadTask(ctx context.Context, id int, scheduleTime string) (*database.UploadTask, error) {
+	var task database.UploadTask
+	if err := s.db.Preload("Video").Preload("Account").First(&task, id).Error; err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	oldStatus := task.Status
+	switch task.Status {
+	case config.TaskStatusPending, config.TaskStatusRateLimited, config.TaskStatusFailed:
+	case config.TaskStatusUploading:
+		return nil, fmt.Errorf("任务正在上传，无法改期")
+	case config.TaskStatusSuccess:
+		if task.ScheduleMode == config.ScheduleModeNative {
+			return nil, fmt.Errorf("任务已提交到平台定时发布，无法自动修改，请在平台后台调整发布时间")
+		}
+		return nil, fmt.Errorf("任务已发布，无法改期")
+	default:
+		return nil, fmt.Errorf("任务状态为 %s，无法改期", task.Status)
+	}
+
+	requested := task.ScheduleMode
+	if task.Status == config.TaskStatusFailed && requested == config.ScheduleModeNative {
+		// 失败的任务尚未提交到平台，允许按新时间重新选择定时方式
+		requested = config.ScheduleModeAuto
+	}
+	deferral, err := s.applyPublishConstraints(ctx, &task.Account, &scheduleTime, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	scheduleTime = *deferral.ScheduleTime
+	if deferral.RateLimited {
+		requested = config.ScheduleModeLocal
+	}
+	mode, fireAt, err := s.resolveScheduleMode(ctx, &task.Account, scheduleTime, task.IsDraft, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.jobs != nil {
+		if err := s.jobs.CancelUploadTask(task.ID); err != nil {
+			return nil, fmt.Errorf("取消原定时任务失败: %w", err)
+		}
+	}
+
+	task.ScheduleTime = &scheduleTime
+	task.ScheduleMode = mode
+	task.ErrorMsg = ""
+	task.DeferReason = deferral.Note
+	task.Status = config.TaskStatusUploading
+	if mode == config.ScheduleModeLocal {
+		task.Status = config.TaskStatusPending
+		if deferral.RateLimited {
+			task.Status = config.TaskStatusRateLimited
+		}
+	}
+	if err := s.db.Model(&task).Updates(map[string]interface{}{
+		"schedule_time": scheduleTime,
+		"schedule_mode": mode,
+		"status":        task.Status,
+		"error_msg":     "",
+		"defer_reason":  deferral.Note,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("reschedule task failed: %w", err)
+	}
+	s.createUploadLog(task.ID, "reschedule", fmt.Sprintf("改期为 %s", scheduleTime))
+	if deferral.Note != "" {
+		s.createUploadLog(task.ID, "defer", deferral.Note)
+	}
+
+	if mode == config.ScheduleModeLocal {
+		if err := s.scheduleLocalTask(&task, &task.Video, fireAt); err != nil {
+			s.updateTaskFailed(task.ID, err.Error())
+			return nil, err
+		}
+	} else {
+		// 平台定时需要重新上传并在平台设置新的定时时间
 		go s.executeTask(context.Background(), task.ID)
 	}
 
-	return tasks, nil
+	if oldStatus != task.Status {
+		s.eventBus.Publish(config.EventTaskStatusChanged, types.TaskStatusChangedEvent{
+			TaskID:    task.ID,
+			OldStatus: oldStatus,
+			NewStatus: task.Status,
+		})
+	}
+	return &task, nil
 }
 
-// applyPlatformFields 应用平台特定字段到任务
-func (s *UploadService) applyPlatformFields(task *database.UploadTask, fields types.PlatformFields) {
-	if fields.Title != "" {
-		task.Title = fields.Title
+// CountPendingLocalTasks 统计等待执行的本地定时任务数量
+func (s *UploadService) CountPendingLocalTasks() int64 {
+	var count int64
+	s.db.Model(&database.UploadTask{}).
+		Where("schedule_mode = ? AND status IN ?", config.ScheduleModeLocal, []string{config.TaskStatusPending, config.TaskStatusRateLimited}).
+		Count(&count)
+	return count
+}
+
+// CheckScheduleTime 按账号时区和平台定时规则校验定时时间，并转换为平台发布页时间
+func (s *UploadService) CheckScheduleTime(ctx context.Context, account *database.Account, scheduleTime string) (types.ScheduleTimeCheck, error) {
+	return platformutils.CheckScheduleTime(account.Platform, scheduleTime, s.schedules.AccountLocation(ctx, account),
+		platformutils.PageLocation(account.Platform, account.TimeZone), time.Now())
+}
+
+// buildUploadTask 根据元数据构建上传任务，并渲染标题/描述模板
+// 返回渲染过程中未定义的模板变量
+func (s *UploadService) buildUploadTask(video *database.Video, account *database.Account, scheduleTime *string, metadata *UploadTaskMetadata) (database.UploadTask, []string) {
+	task := database.UploadTask{
+		VideoID:      video.ID,
+		AccountID:    account.ID,
+		Platform:     account.Platform,
+		Progress:     0,
+		ScheduleTime: scheduleTime,
+	}
+
+	// 叠加发布预设（任务指定或账号默认），任务中填写的字段优先
+	metadata, task.PresetID = s.presets.ResolveMetadata(account, metadata)
+	if metadata != nil {
+		task.ScheduleMode = metadata.ScheduleMode
+	}
+
+	// 应用通用标题/描述（如果用户填写了）
+	if metadata != nil {
+		task.Title = metadata.Common.Title
+		task.Description = metadata.Common.Description
+	}
+
+	// 应用平台特定字段
+	if metadata != nil && metadata.Platforms != nil {
+		if platformFields, ok := metadata.Platforms[account.Platform]; ok {
+			// 应用平台特定字段到任务
+			s.applyPlatformFields(&task, platformFields)
+		}
+	}
+
+	// 标签：指定了标签组时按平台别名展开，否则使用视频标签，并按平台数量上限截取
+	tags := video.Tags
+	if metadata != nil && metadata.TagSetID > 0 {
+		if set, err := s.tagSets.GetTagSetByID(context.Background(), metadata.TagSetID); err == nil {
+			tags = ExpandTagEntries(set.Entries, account.Platform)
+			task.TagSetID = set.ID
+		} else {
+			utils.Warn(fmt.Sprintf("[-] 标签组 %d 不存在，使用视频标签", metadata.TagSetID))
+		}
+	}
+	tags, dropped := platformutils.LimitTags(account.Platform, tags)
+	if len(dropped) > 0 {
+		utils.Warn(fmt.Sprintf("[-] 平台 %s 最多 %d 个标签，已忽略: %s",
+			account.Platform, platformutils.GetTagLimit(account.Platform), strings.Join(dropped, ", ")))
+	}
+	task.Tags = tags
+
+	renderer := NewTemplateRenderer(TemplateData{
+		Video:        video,
+		Account:      account,
+		Platform:     account.Platform,
+		Tags:         tags,
+		ScheduleTime: parseTaskScheduleTime(scheduleTime, s.schedules.AccountLocation(context.Background(), account)),
+	})
+	task.Title = renderer.Render(task.Title)
+	task.ShortTitle = renderer.Render(task.ShortTitle)
+	task.Description = renderer.Render(task.Description)
+
+	return task, renderer.Missing()
+}
+
+// PreviewUploadTask 预览模板渲染后的任务标题与描述（不创建任务）
+func (s *UploadService) PreviewUploadTask(ctx context.Context, videoID int, accountIDs []int, scheduleTime *string, metadata *UploadTaskMetadata) ([]types.TaskTextPreview, error) {
+	var video database.Video
+	if result := s.db.First(&video, videoID); result.Error != nil {
+		return nil, fmt.Errorf("video not found")
+	}
+
+	previews := make([]types.TaskTextPreview, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		var account database.Account
+		if result := s.db.First(&account, accountID); result.Error != nil {
+			continue
+		}
+
+		task, missing := s.buildUploadTask(&video, &account, scheduleTime, metadata)
+		title := resolveTaskTitle(&task, &video)
+		description := resolveTaskDescription(&task, &video)
+		shortTitle := platformutils.ShortTitleFor(account.Platform, task.ShortTitle, title)
+		previews = append(previews, types.TaskTextPreview{
+			AccountID:      account.ID,
+			AccountName:    account.Name,
+			Platform:       account.Platform,
+			Title:          title,
+			ShortTitle:     shortTitle,
+			Description:    description,
+			Tags:           task.Tags,
+			MissingVars:    missing,
+			Normalizations: normalizeTaskText(account.Platform, title, shortTitle, description),
+		})
+	}
+	return previews, nil
+}
+
+// normalizeTaskText 按平台规则检查标题/短标题/描述，返回发生变化的字段
+func normalizeTaskText(platform, title, shortTitle, description string) []types.TextNormalization {
+	var result []types.TextNormalization
+	titleResult, descResult := platformutils.NormalizeCaption(platform, title, description)
+	fields := []struct {
+		text string
+		n    types.TextNormalization
+	}{
+		{title, titleResult},
+		{shortTitle, platformutils.NormalizeText(platform, platformutils.FieldShortTitle, shortTitle)},
+		{description, descResult},
+	}
+	for _, f := range fields {
+		if f.text != "" && len(f.n.Changes) > 0 {
+			result = append(result, f.n)
+		}
+	}
+	return result
+}
+
+// parseTaskScheduleTime 按账号时区解析任务定时时间，无法解析时返回 nil
+func parseTaskScheduleTime(scheduleTime *string, loc *time.Location) *time.Time {
+	if scheduleTime == nil || *scheduleTime == "" {
+		return nil
 	}
-	if fields.Collection != "" {
-		task.Collection = fields.Collection
+	t, err := utils.ParseScheduleTimeIn(*scheduleTime, loc)
+	if err != nil {
+		return nil
 	}
-	if fields.ShortTitle != "" {
-		task.ShortTitle = fields.ShortTitle
+	t = t.In(loc)
+	return &t
+}
+
+// resolveTaskTitle 使用用户自定义标题（如果有），否则使用视频标题，最后使用文件名作为默认
+func resolveTaskTitle(task *database.UploadTask, video *database.Video) string {
+	title := video.Title
+	if task.Title != "" {
+		title = task.Title
 	}
-	task.IsOriginal = fields.IsOriginal
-	task.OriginalType = fields.OriginalType
-	if fields.Location != "" {
-		task.Location = fields.Location
+	// 如果标题仍为空，使用文件名（去掉扩展名）
+	if title == "" {
+		title = filepath.Base(video.FilePath)
+		// 去掉扩展名
+		if ext := filepath.Ext(title); ext != "" {
+			title = title[:len(title)-len(ext)]
+		}
+	}
+	return title
+}
+
+// resolveTaskTags 使用任务中按平台展开的标签，否则使用视频标签
+func resolveTaskTags(task *database.UploadTask, video *database.Video) []string {
+	if len(task.Tags) > 0 {
+		return task.Tags
+	}
+	return video.Tags
+}
+
+// resolveTaskDescription 使用用户自定义描述（如果有），否则使用视频描述
+func resolveTaskDescription(task *database.UploadTask, video *database.Video) string {
+	if task.Description != "" {
+		return task.Description
 	}
-	if fields.Thumbnail != "" {
-		task.Thumbnail = fields.Thumbnail
+	return video.Description
+}
+
+// applyPlatformFields 应用平台特定字段到任务，未填写的字段保持不变
+func (s *UploadService) applyPlatformFields(task *database.UploadTask, fields types.PlatformFields) {
+	setString := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
 	}
-	task.SyncToutiao = fields.SyncToutiao
-	task.SyncXigua = fields.SyncXigua
-	task.IsDraft = fields.IsDraft
-	if fields.Copyright != "" {
-		task.Copyright = fields.Copyright
+	setBool := func(dst *bool, v *bool) {
+		if v != nil {
+			*dst = *v
+		}
 	}
-	task.AllowDownload = fields.AllowDownload
-	task.AllowComment = fields.AllowComment
-	task.AllowDuet = fields.AllowDuet
-	task.AIDeclaration = fields.AIDeclaration
-	task.AutoGenerateAudio = fields.AutoGenerateAudio
-	task.CoverType = fields.CoverType
-	task.Category = fields.Category
-	task.UseIframe = fields.UseIframe
-	task.UseFileChooser = fields.UseFileChooser
-	task.SkipNewFeatureGuide = fields.SkipNewFeatureGuide
+	setString(&task.Title, fields.Title)
+	setString(&task.Description, fields.Description)
+	setString(&task.Collection, fields.Collection)
+	setString(&task.ShortTitle, fields.ShortTitle)
+	setBool(&task.IsOriginal, fields.IsOriginal)
+	setString(&task.OriginalType, fields.OriginalType)
+	setString(&task.Location, fields.Location)
+	setString(&task.Thumbnail, fields.Thumbnail)
+	setBool(&task.SyncToutiao, fields.SyncToutiao)
+	setBool(&task.SyncXigua, fields.SyncXigua)
+	setBool(&task.IsDraft, fields.IsDraft)
+	setString(&task.Copyright, fields.Copyright)
+	setBool(&task.AllowDownload, fields.AllowDownload)
+	setBool(&task.AllowComment, fields.AllowComment)
+	setBool(&task.AllowDuet, fields.AllowDuet)
+	setBool(&task.AIDeclaration, fields.AIDeclaration)
+	setBool(&task.AutoGenerateAudio, fields.AutoGenerateAudio)
+	setString(&task.CoverType, fields.CoverType)
+	setString(&task.Category, fields.Category)
+	setBool(&task.UseIframe, fields.UseIframe)
+	setBool(&task.UseFileChooser, fields.UseFileChooser)
+	setBool(&task.SkipNewFeatureGuide, fields.SkipNewFeatureGuide)
 }
 
 func (s *UploadService) GetUploadTasks(ctx context.Context, status string) ([]database.UploadTask, error) {
@@ -211,7 +890,7 @@ func (s *UploadService) CancelUploadTask(ctx context.Context, id int) error {
 
 	oldStatus := task.Status
 
-	if task.Status != config.TaskStatusPending && task.Status != config.TaskStatusUploading {
+	if task.Status != config.TaskStatusPending && task.Status != config.TaskStatusRateLimited && task.Status != config.TaskStatusUploading {
 		return fmt.Errorf("task cannot be cancelled")
 	}
 
@@ -221,6 +900,12 @@ func (s *UploadService) CancelUploadTask(ctx context.Context, id int) error {
 		return fmt.Errorf("cancel task failed: %w", result.Error)
 	}
 
+	if task.ScheduleMode == config.ScheduleModeLocal && s.jobs != nil {
+		if err := s.jobs.CancelUploadTask(id); err != nil {
+			utils.Warn(fmt.Sprintf("[-] 取消本地定时任务失败: %v", err))
+		}
+	}
+
 	s.eventBus.Publish(config.EventTaskStatusChanged, types.TaskStatusChangedEvent{
 		TaskID:    id,
 		OldStatus: oldStatus,
@@ -241,9 +926,52 @@ func (s *UploadService) RetryUploadTask(ctx context.Context, id int) error {
 		return fmt.Errorf("only failed tasks can be retried")
 	}
 
-	// 检查限流
-	if err := s.checkRateLimit(task.Platform); err != nil {
-		return fmt.Errorf("rate limit check failed: %w", err)
+	// 触发限流或处于禁发时段时排队到最早可发布时间重试
+	var account database.Account
+	if err := s.db.First(&account, task.AccountID).Error; err != nil {
+		return fmt.Errorf("account not found")
+	}
+	loc := s.schedules.AccountLocation(ctx, &account)
+	now := time.Now()
+
+	// 重新确定定时方式：定时时间已过时改为立即发布，仍在未来时按当前平台规则选择平台定时或本地定时
+	if task.ScheduleTime != nil && *task.ScheduleTime != "" {
+		at, err := utils.ParseScheduleTimeIn(*task.ScheduleTime, loc)
+		if err != nil {
+			return fmt.Errorf("定时时间无效: %w", err)
+		}
+		if !at.After(now) {
+			s.createUploadLog(task.ID, "retry", fmt.Sprintf("定时时间 %s 已过，重试改为立即发布", *task.ScheduleTime))
+			task.ScheduleTime = nil
+			task.ScheduleMode = config.ScheduleModeAuto
+		} else {
+			requested := task.ScheduleMode
+			if requested == config.ScheduleModeNative {
+				requested = config.ScheduleModeAuto
+			}
+			mode, fireAt, err := s.resolveScheduleMode(ctx, &account, *task.ScheduleTime, task.IsDraft, requested)
+			if err != nil {
+				return err
+			}
+			task.ScheduleMode = mode
+			if mode == config.ScheduleModeLocal {
+				return s.retryAsLocal(&task, fireAt)
+			}
+		}
+	}
+
+	next, reasons, rateLimited, err := s.earliestPublishTime(ctx, &account, now, loc, task.ID)
+	if err != nil {
+		return err
+	}
+	if next.After(now) {
+		next = platformutils.CeilTime(next, time.Minute)
+		task.RetryCount++
+		if err := s.db.Model(&task).UpdateColumn("retry_count", task.RetryCount).Error; err != nil {
+			return fmt.Errorf("retry task failed: %w", err)
+		}
+		return s.deferTask(&task, next, loc, rateLimited,
+			fmt.Sprintf("重试推迟至 %s：%s", utils.FormatScheduleTime(next.In(loc)), strings.Join(reasons, "；")))
 	}
 
 	task.Status = config.TaskStatusUploading
@@ -259,7 +987,38 @@ func (s *UploadService) RetryUploadTask(ctx context.Context, id int) error {
 	return nil
 }
 
+// retryAsLocal 重试的任务改为本地定时，到定时时间后再上传
+func (s *UploadService) retryAsLocal(task *database.UploadTask, fireAt time.Time) error {
+	var video database.Video
+	if err := s.db.First(&video, task.VideoID).Error; err != nil {
+		return fmt.Errorf("视频不存在: %w", err)
+	}
+
+	task.Status = config.TaskStatusPending
+	task.RetryCount++
+	task.ErrorMsg = ""
+	if err := s.db.Save(task).Error; err != nil {
+		return fmt.Errorf("retry task failed: %w", err)
+	}
+	if err := s.scheduleLocalTask(task, &video, fireAt); err != nil {
+		s.updateTaskFailed(task.ID, err.Error())
+		return err
+	}
+
+	s.eventBus.Publish(config.EventTaskStatusChanged, types.TaskStatusChangedEvent{
+		TaskID:    task.ID,
+		OldStatus: config.TaskStatusFailed,
+		NewStatus: task.Status,
+	})
+	return nil
+}
+
 func (s *UploadService) DeleteUploadTask(ctx context.Context, id int) error {
+	if s.jobs != nil {
+		if err := s.jobs.CancelUploadTask(id); err != nil {
+			utils.Warn(fmt.Sprintf("[-] 取消本地定时任务失败: %v", err))
+		}
+	}
 	result := s.db.Delete(&database.UploadTask{}, id)
 	if result.Error != nil {
 		return fmt.Errorf("delete task failed: %w", result.Error)
@@ -280,6 +1039,26 @@ func (s *UploadService) GetAllRateLimitStats() map[string]ratelimit.Stats {
 	return s.rateLimiter.GetAllStats()
 }
 
+// GetAccountRateLimitStats 获取账号级限额统计
+func (s *UploadService) GetAccountRateLimitStats(ctx context.Context) ([]types.AccountRateLimitStats, error) {
+	return s.accountLimits.GetStats(ctx)
+}
+
+// AccountLimits 账号级限额服务
+func (s *UploadService) AccountLimits() *AccountLimitService {
+	return s.accountLimits
+}
+
+// Proxies 账号代理服务
+func (s *UploadService) Proxies() *ProxyService {
+	return s.proxies
+}
+
+// Recordings 上传录屏服务
+func (s *UploadService) Recordings() *RecordingService {
+	return s.recordings
+}
+
 func (s *UploadService) executeTask(ctx context.Context, taskID int) {
 	var task database.UploadTask
 	if result := s.db.Preload("Video").Preload("Account").First(&task, taskID); result.Error != nil {
@@ -297,19 +1076,7 @@ func (s *UploadService) executeTask(ctx context.Context, taskID int) {
 		Message:  "Starting upload...",
 	})
 
-	// 使用用户自定义标题（如果有），否则使用视频标题，最后使用文件名作为默认
-	title := task.Video.Title
-	if task.Title != "" {
-		title = task.Title
-	}
-	// 如果标题仍为空，使用文件名（去掉扩展名）
-	if title == "" {
-		title = filepath.Base(task.Video.FilePath)
-		// 去掉扩展名
-		if ext := filepath.Ext(title); ext != "" {
-			title = title[:len(title)-len(ext)]
-		}
-	}
+	title := resolveTaskTitle(&task, &task.Video)
 
 	// 封面优先级：发布页面设置的封面 > 视频默认封面
 	thumbnail := task.Thumbnail
@@ -320,14 +1087,38 @@ func (s *UploadService) executeTask(ctx context.Context, taskID int) {
 	// 将 URL 路径转换为本地文件系统路径
 	thumbnail = convertThumbnailURLToPath(thumbnail)
 
+	// 定时时间统一在此按账号时区和平台规则校验，并转换为平台发布页时区的时间
+	scheduleTime := task.ScheduleTime
+	if task.ScheduleMode == config.ScheduleModeLocal {
+		// 本地定时到时间后立即发布
+		scheduleTime = nil
+	}
+	if scheduleTime != nil && *scheduleTime != "" {
+		check, err := s.CheckScheduleTime(ctx, &task.Account, *scheduleTime)
+		if err != nil {
+			s.updateTaskFailed(taskID, err.Error())
+			s.createUploadLog(taskID, "upload_error", "定时时间无效: "+err.Error())
+			s.eventBus.Publish(config.EventUploadError, types.UploadErrorEvent{
+				TaskID:   task.ID,
+				Platform: task.Platform,
+				Error:    err.Error(),
+				CanRetry: false,
+			})
+			return
+		}
+		s.createUploadLog(taskID, "schedule_time", fmt.Sprintf("定时 %s（%s）→ 平台页面时间 %s（%s）",
+			check.Input, check.AccountZone, check.PageTime, check.PageZone))
+		scheduleTime = &check.PageTime
+	}
+
 	videoTask := &types.VideoTask{
 		Platform:            task.Platform,
 		VideoPath:           task.Video.FilePath,
 		Title:               title,
-		Description:         task.Video.Description,
-		Tags:                task.Video.Tags,
+		Description:         resolveTaskDescription(&task, &task.Video),
+		Tags:                resolveTaskTags(&task, &task.Video),
 		Thumbnail:           thumbnail,
-		ScheduleTime:        task.ScheduleTime,
+		ScheduleTime:        scheduleTime,
 		IsDraft:             task.IsDraft,
 		Location:            task.Location,
 		SyncToutiao:         task.SyncToutiao,
@@ -349,23 +1140,14 @@ func (s *UploadService) executeTask(ctx context.Context, taskID int) {
 		SkipNewFeatureGuide: task.SkipNewFeatureGuide,
 	}
 
-	var uploader types.Uploader
-	switch task.Platform {
-	case config.PlatformDouyin:
-		uploader = douyin.NewUploader(task.Account.CookiePath)
-	case config.PlatformTencent:
-		uploader = tencent.NewUploaderWithAccount(uint(task.AccountID))
-	case config.PlatformKuaishou:
-		uploader = kuaishou.NewUploader(task.Account.CookiePath)
-	case config.PlatformTiktok:
-		uploader = tiktok.NewUploader(task.Account.CookiePath)
-	case config.PlatformXiaohongshu:
-		uploader = xiaohongshu.NewUploader(task.Account.CookiePath)
-	case config.PlatformBaijiahao:
-		uploader = baijiahao.NewUploader(task.Account.CookiePath)
-	case config.PlatformBilibili:
-		uploader = bilibili.NewUploader(task.Account.CookiePath)
-	default:
+	// 记录平台文本规范化结果，便于用户核对实际发布的文案
+	shortTitle := platformutils.ShortTitleFor(task.Platform, videoTask.ShortTitle, videoTask.Title)
+	for _, n := range normalizeTaskText(task.Platform, videoTask.Title, shortTitle, videoTask.Description) {
+		s.createUploadLog(taskID, "text_normalize", fmt.Sprintf("%s: %s → %s", n.Field, strings.Join(n.Changes, "; "), n.Result))
+	}
+
+	uploader := NewUploader(task.Platform, uint(task.AccountID), task.Account.CookiePath, s.pool)
+	if uploader == nil {
 		s.updateTaskFailed(taskID, "unsupported platform")
 		s.createUploadLog(taskID, "upload_error", "不支持的平台")
 		s.eventBus.Publish(config.EventUploadError, types.UploadErrorEvent{
@@ -377,6 +1159,29 @@ func (s *UploadService) executeTask(ctx context.Context, taskID int) {
 		return
 	}
 
+	// 账号绑定了代理时，上传前检查代理可用并记录出口 IP
+	exitIP := ""
+	proxyCheck, checkErr := s.proxies.CheckProxy(ctx, task.AccountID)
+	if checkErr == nil && proxyCheck != nil && !proxyCheck.OK {
+		checkErr = fmt.Errorf("代理 %s 检查失败: %s", proxyCheck.Server, proxyCheck.Error)
+	}
+	if checkErr != nil {
+		s.updateTaskFailed(taskID, checkErr.Error())
+		s.createUploadLog(taskID, "proxy_check", checkErr.Error())
+		s.eventBus.Publish(config.EventUploadError, types.UploadErrorEvent{
+			TaskID:   task.ID,
+			Platform: task.Platform,
+			Error:    checkErr.Error(),
+			CanRetry: true,
+		})
+		return
+	}
+	if proxyCheck != nil {
+		exitIP = proxyCheck.IP
+		s.createUploadLogWithIP(taskID, "proxy_check",
+			fmt.Sprintf("代理 %s 可用，出口 IP %s（%dms）", proxyCheck.Server, proxyCheck.IP, proxyCheck.LatencyMs), exitIP)
+	}
+
 	s.eventBus.Publish(config.EventUploadProgress, types.UploadProgressEvent{
 		TaskID:   task.ID,
 		Platform: task.Platform,
@@ -384,10 +1189,51 @@ func (s *UploadService) executeTask(ctx context.Context, taskID int) {
 		Message:  "Uploading video...",
 	})
 
+	// 开启失败诊断时记录整个上传会话的 trace / HAR，仅在失败时保留
+	var diag *browser.Diagnostics
+	if poolCfg := browser.LoadPoolConfig(); poolCfg.FailureTrace || poolCfg.FailureHar {
+		diag = browser.NewDiagnostics(config.Config.DiagnosticsPath,
+			fmt.Sprintf("task_%d_%s", taskID, time.Now().Format("20060102_150405")), poolCfg.FailureTrace, poolCfg.FailureHar)
+		ctx = browser.WithDiagnostics(ctx, diag)
+	}
+
+	// 浏览器池记录占用上下文的任务（池状态查看）
+	ctx = browser.WithTaskID(ctx, taskID)
+
+	// 平台开启录屏时录制整个上传会话，录像关联到任务
+	recording := s.recordings.NewRecording(taskID, task.Platform)
+	if recording != nil {
+		ctx = browser.WithRecording(ctx, recording)
+	}
+
 	err := uploader.Upload(ctx, videoTask)
+
+	if recording != nil {
+		s.saveRecording(&task, recording)
+	}
+
+	var diagFiles browser.DiagnosticsFiles
+	if diag != nil {
+		var diagErr error
+		if diagFiles, diagErr = diag.Finish(err != nil); diagErr != nil {
+			utils.Warn(fmt.Sprintf("[-] 任务 %d 保存诊断记录失败: %v", taskID, diagErr))
+		}
+		if err != nil {
+			s.cleanDiagnostics()
+		}
+	}
+
 	if err != nil {
 		s.updateTaskFailed(taskID, err.Error())
-		s.createUploadLog(taskID, "upload_error", "上传失败: "+err.Error())
+		s.saveUploadLog(&database.UploadLog{
+			TaskID:    uint(taskID),
+			Step:      "upload_error",
+			Message:   "上传失败: " + err.Error(),
+			Status:    "processing",
+			IPAddress: exitIP,
+			TracePath: diagFiles.TracePath,
+			HarPath:   diagFiles.HarPath,
+		})
 		s.eventBus.Publish(config.EventUploadError, types.UploadErrorEvent{
 			TaskID:   task.ID,
 			Platform: task.Platform,
@@ -425,7 +1271,8 @@ func (s *UploadService) executeTask(ctx context.Context, taskID int) {
 		return
 	}
 
-	s.createUploadLog(taskID, "upload_success", "上传成功")
+	s.createUploadLogWithIP(taskID, "upload_success", "上传成功", exitIP)
+	s.recordPublish(ctx, &task)
 
 	s.eventBus.Publish(config.EventUploadComplete, types.UploadCompleteEvent{
 		TaskID:      task.ID,
@@ -441,15 +1288,67 @@ func (s *UploadService) executeTask(ctx context.Context, taskID int) {
 	})
 }
 
+// saveRecording 保存上传会话录屏并关联到任务，随后按保留限制清理旧录像
+func (s *UploadService) saveRecording(task *database.UploadTask, recording *browser.Recording) {
+	path, err := recording.Finish()
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 任务 %d 保存录屏失败: %v", task.ID, err))
+	}
+	if path != "" {
+		task.RecordingPath = path
+		s.db.Model(&database.UploadTask{}).Where("id = ?", task.ID).UpdateColumn("recording_path", path)
+		s.createUploadLog(task.ID, "recording", "录屏已保存: "+path)
+	}
+	if _, err := s.recordings.CleanRecordings(); err != nil {
+		utils.Warn(fmt.Sprintf("[-] 清理录屏失败: %v", err))
+	}
+}
+
+// cleanDiagnostics 按浏览器池配置的保留天数和总大小清理诊断记录，并清除日志中已删除记录的路径
+func (s *UploadService) cleanDiagnostics() {
+	poolCfg := browser.LoadPoolConfig()
+	deleted, err := browser.CleanDiagnostics(config.Config.DiagnosticsPath, poolCfg.DiagnosticsMaxAgeDays, poolCfg.DiagnosticsMaxSizeMB, time.Now())
+	if err != nil {
+		utils.Warn(fmt.Sprintf("[-] 清理诊断记录失败: %v", err))
+		return
+	}
+	for _, path := range deleted {
+		s.db.Model(&database.UploadLog{}).Where("trace_path = ?", path).UpdateColumn("trace_path", "")
+		s.db.Model(&database.UploadLog{}).Where("har_path = ?", path).UpdateColumn("har_path", "")
+	}
+	if len(deleted) > 0 {
+		utils.Info(fmt.Sprintf("[-] 已清理 %d 个诊断记录", len(deleted)))
+	}
+}
+
+// GetTaskLogs 获取任务的上传日志（按时间顺序）
+func (s *UploadService) GetTaskLogs(ctx context.Context, taskID int) ([]database.UploadLog, error) {
+	logs, err := database.NewUploadLogService(s.db).GetTaskLogs(uint(taskID))
+	if err != nil {
+		return nil, fmt.Errorf("query upload logs failed: %w", err)
+	}
+	return logs, nil
+}
+
 // createUploadLog 创建上传日志
 func (s *UploadService) createUploadLog(taskID int, step, message string) {
-	log := database.UploadLog{
-		TaskID:  uint(taskID),
-		Step:    step,
-		Message: message,
-		Status:  "processing",
-	}
-	if err := s.db.Create(&log).Error; err != nil {
+	s.createUploadLogWithIP(taskID, step, message, "")
+}
+
+// createUploadLogWithIP 创建上传日志并记录出口 IP（账号使用代理时）
+func (s *UploadService) createUploadLogWithIP(taskID int, step, message, ip string) {
+	s.saveUploadLog(&database.UploadLog{
+		TaskID:    uint(taskID),
+		Step:      step,
+		Message:   message,
+		Status:    "processing",
+		IPAddress: ip,
+	})
+}
+
+// saveUploadLog 保存上传日志
+func (s *UploadService) saveUploadLog(log *database.UploadLog) {
+	if err := s.db.Create(log).Error; err != nil {
 		utils.Warn(fmt.Sprintf("[-] 创建上传日志失败: %v", err))
 	}
 }
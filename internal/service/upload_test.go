@@ -0,0 +1,83 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/database"
+	"Fuploader/internal/platform/platformutils"
+	"Fuploader/internal/scheduler"
+	"Fuploader/internal/types"
+	"Fuploader/internal/utils"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCreateUploadTaskRateLimited(t *testing.T) {
+	db := newTestDB(t)
+	s := NewUploadService(db, nil)
+	s.SetJobScheduler(scheduler.NewEnhancedScheduler(db, 1))
+	ctx := context.Background()
+
+	account := database.Account{Platform: "douyin", Name: "抖音号", TimeZone: "Asia/Shanghai"}
+	video := database.Video{Filename: "a.mp4", FilePath: "/v/a.mp4", Title: "标题"}
+	if err := db.Create(&account).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&video).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := s.accountLimits.SetLimit(ctx, &database.AccountRateLimit{AccountID: account.ID, HourlyLimit: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// 20 分钟前刚发布过，立即上传应排队到上次发布一小时后
+	published := time.Now().Add(-20 * time.Minute)
+	if err := s.ledger.Record(&database.UploadTask{ID: 100, AccountID: account.ID, Platform: "douyin"}, published, false); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, reports, err := s.CreateUploadTaskWithReport(ctx, video.ID, []int{account.ID, 9999}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || len(reports) != 2 {
+		t.Fatalf("tasks = %d, reports = %+v", len(tasks), reports)
+	}
+
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	// 推迟时间向上取整到分钟，不早于最早可发布时间
+	earliest := utils.FormatScheduleTime(platformutils.CeilTime(published.Add(time.Hour), time.Minute).In(loc))
+	report := reports[0]
+	if report.Result != types.TaskReportRateLimited || report.TaskID != tasks[0].ID || report.ScheduleTime != earliest || report.Reason == "" {
+		t.Errorf("report = %+v, want rate_limited at %s", report, earliest)
+	}
+	if reports[1].Result != types.TaskReportFailed || reports[1].AccountName != "#9999" {
+		t.Errorf("missing account report = %+v", reports[1])
+	}
+
+	var task database.UploadTask
+	if err := db.First(&task, tasks[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != config.TaskStatusRateLimited || task.ScheduleMode != config.ScheduleModeLocal || task.DeferReason == "" {
+		t.Errorf("task = status %s, mode %s, reason %q", task.Status, task.ScheduleMode, task.DeferReason)
+	}
+	if task.ScheduleTime == nil || *task.ScheduleTime != earliest {
+		t.Errorf("task schedule time = %v, want %s", task.ScheduleTime, earliest)
+	}
+
+	// 到最早可发布时间由本地调度器执行
+	var job database.ScheduledTask
+	if err := db.Where("upload_task_id = ?", task.ID).First(&job).Error; err != nil {
+		t.Fatal(err)
+	}
+	fireAt, _ := utils.ParseScheduleTimeIn(earliest, loc)
+	if !job.ScheduleTime.Equal(fireAt) {
+		t.Errorf("job fires at %s, want %s", job.ScheduleTime, fireAt)
+	}
+	if fireAt.Before(published.Add(time.Hour)) {
+		t.Errorf("deferred time %s is earlier than the rate limit allows", fireAt)
+	}
+	if stats, ok := s.rateLimiter.GetStats("douyin"); !ok || stats.Rejected != 1 {
+		t.Errorf("rate limiter stats = %+v", stats)
+	}
+}
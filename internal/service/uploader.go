@@ -0,0 +1,36 @@
+package service
+
+import (
+	"Fuploader/internal/config"
+	"Fuploader/internal/platform/baijiahao"
+	"Fuploader/internal/platform/bilibili"
+	"Fuploader/internal/platform/browser"
+	"Fuploader/internal/platform/douyin"
+	"Fuploader/internal/platform/kuaishou"
+	"Fuploader/internal/platform/tencent"
+	"Fuploader/internal/platform/tiktok"
+	"Fuploader/internal/platform/xiaohongshu"
+	"Fuploader/internal/types"
+)
+
+// NewUploader 创建平台上传器，所有平台共用同一个浏览器池；cookiePath 为空时使用账号的默认路径，不支持的平台返回 nil
+func NewUploader(platform string, accountID uint, cookiePath string, pool *browser.Pool) types.Uploader {
+	switch platform {
+	case config.PlatformDouyin:
+		return douyin.NewUploaderWithPool(accountID, cookiePath, pool)
+	case config.PlatformTencent:
+		return tencent.NewUploaderWithAccountPool(accountID, cookiePath, pool)
+	case config.PlatformKuaishou:
+		return kuaishou.NewUploaderWithPool(accountID, cookiePath, pool)
+	case config.PlatformTiktok:
+		return tiktok.NewUploaderWithPool(accountID, cookiePath, pool)
+	case config.PlatformXiaohongshu:
+		return xiaohongshu.NewUploaderWithPool(accountID, cookiePath, pool)
+	case config.PlatformBaijiahao:
+		return baijiahao.NewUploaderWithPool(accountID, cookiePath, pool)
+	case config.PlatformBilibili:
+		return bilibili.NewUploaderWithPool(accountID, cookiePath, pool)
+	default:
+		return nil
+	}
+}
@@ -15,6 +15,13 @@ type AppStatus struct {
 	Version     string `json:"version"`
 }
 
+// VaultStatus 凭据库状态
+type VaultStatus struct {
+	Source string `json:"source"` // 密钥来源：keyring/passphrase/file，未解锁时为空
+	Locked bool   `json:"locked"`
+	Error  string `json:"error,omitempty"`
+}
+
 // ProductLinkValidationResult 商品链接验证结果
 type ProductLinkValidationResult struct {
 	Valid bool   `json:"valid"`
@@ -53,4 +60,36 @@ type BrowserPoolConfig struct {
 	EnableHealthCheck     bool   `json:"enableHealthCheck"`
 	HealthCheckInterval   int    `json:"healthCheckInterval"`
 	ContextReuseMode      string `json:"contextReuseMode"`
+
+	PlatformStorageModes  map[string]string `json:"platformStorageModes"`  // 平台 → storage_state/persistent
+	MaxPersistentContexts int               `json:"maxPersistentContexts"` // 持久化模式同时打开的账号浏览器上限
+	FailureTrace          bool              `json:"failureTrace"`          // 上传失败时保留 Playwright trace
+	FailureHar            bool              `json:"failureHar"`            // 上传失败时保留 HAR
+	DiagnosticsMaxAgeDays int               `json:"diagnosticsMaxAgeDays"` // 诊断记录保留天数，0 表示不限制
+	DiagnosticsMaxSizeMB  int               `json:"diagnosticsMaxSizeMB"`  // 诊断记录总大小上限（MB），0 表示不限制
+
+	Remote          *RemoteBrowserConfig           `json:"remote"`          // 远程浏览器，为空时使用本地 Chrome
+	PlatformRemotes map[string]RemoteBrowserConfig `json:"platformRemotes"` // 平台 → 远程浏览器，优先于 remote
+
+	HumanPreset          string            `json:"humanPreset"`          // 人类交互预设 off/light/standard/careful
+	PlatformHumanPresets map[string]string `json:"platformHumanPresets"` // 平台 → 人类交互预设，优先于 humanPreset
+}
+
+// RemoteBrowserConfig 远程浏览器端点
+type RemoteBrowserConfig struct {
+	Protocol string            `json:"protocol"` // cdp / playwright
+	Endpoint string            `json:"endpoint"` // CDP: http(s)/ws(s) 地址；Playwright 浏览器服务: ws(s) 地址
+	Headers  map[string]string `json:"headers"`  // 连接时附带的请求头
+	Timeout  int               `json:"timeout"`  // 连接超时（秒），0 使用默认 30 秒
+}
+
+// ProxyCheckResult 账号代理健康检查结果
+type ProxyCheckResult struct {
+	AccountID int    `json:"accountId"`
+	Server    string `json:"server"`
+	OK        bool   `json:"ok"`
+	IP        string `json:"ip"`        // 经代理访问到的出口 IP
+	LatencyMs int64  `json:"latencyMs"` // 检查请求耗时（毫秒）
+	Error     string `json:"error,omitempty"`
+	CheckedAt string `json:"checkedAt"` // RFC3339
 }
@@ -0,0 +1,54 @@
+package types
+
+// 日历条目类型
+const (
+	CalendarKindScheduled = "scheduled" // 待发布（本地等待执行或已提交平台定时）
+	CalendarKindPublished = "published" // 已发布
+	CalendarKindFailed    = "failed"    // 发布失败
+)
+
+// 日历冲突类型
+const (
+	ConflictTooClose      = "too_close"      // 同一账号两次发布间隔过近
+	ConflictDailyLimit    = "daily_limit"    // 超出平台每日上传限制
+	ConflictCookieExpired = "cookie_expired" // 账号登录已失效
+)
+
+// CalendarItem 日历条目
+type CalendarItem struct {
+	TaskID       int      `json:"taskId"`
+	VideoID      int      `json:"videoId"`
+	Title        string   `json:"title"`
+	Time         string   `json:"time"` // 发布时间（RFC3339）
+	Kind         string   `json:"kind"`
+	Status       string   `json:"status"`
+	ScheduleMode string   `json:"scheduleMode"`
+	Conflicts    []string `json:"conflicts,omitempty"` // 该条目涉及的冲突类型
+}
+
+// CalendarAccount 按账号分组的日历条目
+type CalendarAccount struct {
+	AccountID   int            `json:"accountId"`
+	AccountName string         `json:"accountName"`
+	Platform    string         `json:"platform"`
+	CookieValid bool           `json:"cookieValid"`
+	Items       []CalendarItem `json:"items"`
+}
+
+// CalendarConflict 日历冲突
+type CalendarConflict struct {
+	Type      string `json:"type"`
+	AccountID int    `json:"accountId,omitempty"`
+	Platform  string `json:"platform"`
+	Date      string `json:"date"` // 2006-01-02
+	TaskIDs   []int  `json:"taskIds"`
+	Message   string `json:"message"`
+}
+
+// PublishCalendar 发布日历
+type PublishCalendar struct {
+	StartDate string             `json:"startDate"`
+	EndDate   string             `json:"endDate"`
+	Accounts  []CalendarAccount  `json:"accounts"`
+	Conflicts []CalendarConflict `json:"conflicts"`
+}
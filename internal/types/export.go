@@ -0,0 +1,53 @@
+package types
+
+// 导出格式
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// TaskExportQuery 任务历史导出参数
+type TaskExportQuery struct {
+	Format     string `json:"format"`               // 导出格式：csv/json，默认 csv
+	StartDate  string `json:"startDate,omitempty"`  // 开始日期（按任务创建时间），格式：2006-01-02
+	EndDate    string `json:"endDate,omitempty"`    // 结束日期（含当天），格式：2006-01-02
+	Platform   string `json:"platform,omitempty"`   // 平台筛选
+	AccountID  int    `json:"accountId,omitempty"`  // 账号筛选
+	Status     string `json:"status,omitempty"`     // 最终状态筛选
+	OutputPath string `json:"outputPath,omitempty"` // 输出文件路径，为空时写入默认导出目录
+}
+
+// TaskExportStep 单个步骤耗时
+type TaskExportStep struct {
+	Step       string `json:"step"`       // 步骤名称
+	Status     string `json:"status"`     // 步骤状态
+	DurationMs int64  `json:"durationMs"` // 耗时（毫秒）
+}
+
+// TaskExportRecord 导出的单条任务记录
+type TaskExportRecord struct {
+	TaskID        int              `json:"taskId"`
+	Platform      string           `json:"platform"`
+	AccountID     int              `json:"accountId"`
+	AccountName   string           `json:"accountName"`
+	VideoID       int              `json:"videoId"`
+	VideoFilename string           `json:"videoFilename"`
+	Title         string           `json:"title"`
+	ScheduleTime  string           `json:"scheduleTime"`
+	Status        string           `json:"status"`
+	ErrorMsg      string           `json:"errorMsg"`
+	RetryCount    int              `json:"retryCount"`
+	PublishURL    string           `json:"publishUrl"`
+	RecordingPath string           `json:"recordingPath"` // 上传会话录屏
+	CreatedAt     string           `json:"createdAt"`
+	UpdatedAt     string           `json:"updatedAt"`
+	TotalMs       int64            `json:"totalMs"` // 所有步骤耗时之和（毫秒）
+	Steps         []TaskExportStep `json:"steps"`
+}
+
+// TaskExportResult 导出结果
+type TaskExportResult struct {
+	Path   string `json:"path"`   // 导出文件路径
+	Format string `json:"format"` // 导出格式
+	Count  int    `json:"count"`  // 导出记录数
+}
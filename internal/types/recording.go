@@ -0,0 +1,43 @@
+package types
+
+import "time"
+
+// RecordingConfig 上传录屏配置
+type RecordingConfig struct {
+	Enabled    bool            `json:"enabled"`    // 是否启用录屏
+	Platforms  map[string]bool `json:"platforms"`  // 平台开关（未配置的平台跟随 Enabled）
+	Dir        string          `json:"dir"`        // 录屏目录（为空时使用 storage/recordings）
+	Width      int             `json:"width"`      // 录屏宽度（0 为按视口缩放）
+	Height     int             `json:"height"`     // 录屏高度（0 为按视口缩放）
+	MaxAgeDays int             `json:"maxAgeDays"` // 最大保留天数（0 为不限）
+	MaxSizeMB  int             `json:"maxSizeMB"`  // 最大总大小(MB)（0 为不限）
+}
+
+// RecordingInfo 录屏文件信息
+type RecordingInfo struct {
+	Filename  string    `json:"filename"`  // 文件名
+	Path      string    `json:"path"`      // 完整路径
+	Size      int64     `json:"size"`      // 文件大小（字节）
+	CreatedAt time.Time `json:"createdAt"` // 创建时间
+}
+
+// DefaultRecordingConfig 返回默认录屏配置
+func DefaultRecordingConfig() *RecordingConfig {
+	return &RecordingConfig{
+		Enabled:    false,
+		Platforms:  map[string]bool{},
+		MaxAgeDays: 14,
+		MaxSizeMB:  2048,
+	}
+}
+
+// EnabledFor 平台是否录屏
+func (c *RecordingConfig) EnabledFor(platform string) bool {
+	if !c.Enabled {
+		return false
+	}
+	if enabled, ok := c.Platforms[platform]; ok {
+		return enabled
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package types
+
+// UpcomingSlot 即将到来的发布时段
+type UpcomingSlot struct {
+	ScheduleID   int    `json:"scheduleId"`
+	ScheduleName string `json:"scheduleName"`
+	SlotTime     string `json:"slotTime"`   // RFC3339
+	VideoID      int    `json:"videoId"`    // 将要发布的视频，0 表示播放列表已用完
+	VideoTitle   string `json:"videoTitle"` // 视频标题（为空时为文件名）
+	AccountIDs   []int  `json:"accountIds"`
+
+	Blackout string `json:"blackout,omitempty"` // 命中全局禁发时段时的说明
+}
+
+// ScheduleTimeCheck 定时时间校验与转换结果
+type ScheduleTimeCheck struct {
+	Platform     string `json:"platform"`
+	Input        string `json:"input"`        // 原始输入
+	AccountZone  string `json:"accountZone"`  // 解析输入使用的账号时区
+	Instant      string `json:"instant"`      // 实际发布时刻（RFC3339）
+	PageTime     string `json:"pageTime"`     // 填写到平台发布页的时间（平台页面时区）
+	PageZone     string `json:"pageZone"`     // 平台页面时区
+	Rounded      bool   `json:"rounded"`      // 是否按平台粒度调整过
+	MinLead      string `json:"minLead"`      // 平台最少提前量
+	MaxHorizon   string `json:"maxHorizon"`   // 平台最远可定时范围
+	EarliestTime string `json:"earliestTime"` // 当前最早可定时时间（账号时区）
+	LatestTime   string `json:"latestTime"`   // 当前最晚可定时时间（账号时区）
+}
+
+// ReleasePlanRequest 错峰发布计划参数
+type ReleasePlanRequest struct {
+	VideoID       int            `json:"videoId"`
+	AccountIDs    []int          `json:"accountIds"`
+	WindowStart   string         `json:"windowStart"`             // 发布窗口开始，如 2024-03-05 18:00（按发布配置时区解析）
+	WindowEnd     string         `json:"windowEnd"`               // 发布窗口结束
+	MinGapMinutes map[string]int `json:"minGapMinutes,omitempty"` // 同平台账号之间的最小间隔（分钟），未设置的平台使用默认值
+	JitterMinutes int            `json:"jitterMinutes"`           // 随机抖动范围（±分钟），0 表示不抖动
+}
+
+// ReleasePlanItem 单个账号的发布时间
+type ReleasePlanItem struct {
+	AccountID    int    `json:"accountId"`
+	AccountName  string `json:"accountName"`
+	Platform     string `json:"platform"`
+	ScheduleTime string `json:"scheduleTime"` // RFC3339
+	Error        string `json:"error,omitempty"`
+}
+
+// ReleasePlan 错峰发布计划
+type ReleasePlan struct {
+	Items    []ReleasePlanItem `json:"items"`
+	Warnings []string          `json:"warnings"`
+}
+
+// AccountRateLimitStats 账号级限额统计
+type AccountRateLimitStats struct {
+	AccountID         int    `json:"accountId"`
+	AccountName       string `json:"accountName"`
+	Platform          string `json:"platform"`
+	DailyLimit        int    `json:"dailyLimit"`  // 0 表示不限制
+	HourlyLimit       int    `json:"hourlyLimit"` // 0 表示不限制
+	MinSpacingMinutes int    `json:"minSpacingMinutes"`
+	DailyCount        int    `json:"dailyCount"`  // 滚动 24 小时内已发布数
+	HourlyCount       int    `json:"hourlyCount"` // 滚动 1 小时内已发布数
+	PendingCount      int    `json:"pendingCount"`
+	LastPublishedAt   string `json:"lastPublishedAt,omitempty"` // RFC3339
+	NextAllowedAt     string `json:"nextAllowedAt"`             // 最早可发布时间（RFC3339）
+}
@@ -1,6 +1,8 @@
 package types
 
-import "context"
+import (
+	"context"
+)
 
 // VideoTask 视频任务
 type VideoTask struct {
@@ -43,31 +45,76 @@ type Uploader interface {
 }
 
 // PlatformFields 平台特定字段
+// 布尔字段为 nil 表示未填写，合并发布预设时只有填写了的字段和非空字符串会覆盖预设
 type PlatformFields struct {
 	Title               string `json:"title"`
+	Description         string `json:"description"`
 	Collection          string `json:"collection"`
 	ShortTitle          string `json:"shortTitle"`
-	IsOriginal          bool   `json:"isOriginal"`
+	IsOriginal          *bool  `json:"isOriginal,omitempty"`
 	OriginalType        string `json:"originalType"`
 	Location            string `json:"location"`
 	Thumbnail           string `json:"thumbnail"`
-	SyncToutiao         bool   `json:"syncToutiao"`
-	SyncXigua           bool   `json:"syncXigua"`
-	IsDraft             bool   `json:"isDraft"`
-	Copyright           string `json:"copyright"`           // 转载类型（B站）：1=自制，2=转载
-	AllowDownload       bool   `json:"allowDownload"`       // 是否允许下载（抖音/快手）
-	AllowComment        bool   `json:"allowComment"`        // 是否允许评论（抖音/TikTok）
-	AllowDuet           bool   `json:"allowDuet"`           // 是否允许合拍（TikTok）
-	AIDeclaration       bool   `json:"aiDeclaration"`       // AI创作声明（百家号）
-	AutoGenerateAudio   bool   `json:"autoGenerateAudio"`   // 自动生成音频（百家号）
-	CoverType           string `json:"coverType"`           // 封面模式（百家号）：auto/single/triple
-	Category            string `json:"category"`            // 分类（百家号）
-	UseIframe           bool   `json:"useIframe"`           // 是否使用iframe模式（TikTok）
-	UseFileChooser      bool   `json:"useFileChooser"`      // 是否使用文件选择器（快手）
-	SkipNewFeatureGuide bool   `json:"skipNewFeatureGuide"` // 是否跳过新功能引导（快手）
+	SyncToutiao         *bool  `json:"syncToutiao,omitempty"`
+	SyncXigua           *bool  `json:"syncXigua,omitempty"`
+	IsDraft             *bool  `json:"isDraft,omitempty"`
+	Copyright           string `json:"copyright"`                     // 转载类型（B站）：1=自制，2=转载
+	AllowDownload       *bool  `json:"allowDownload,omitempty"`       // 是否允许下载（抖音/快手）
+	AllowComment        *bool  `json:"allowComment,omitempty"`        // 是否允许评论（抖音/TikTok）
+	AllowDuet           *bool  `json:"allowDuet,omitempty"`           // 是否允许合拍（TikTok）
+	AIDeclaration       *bool  `json:"aiDeclaration,omitempty"`       // AI创作声明（百家号）
+	AutoGenerateAudio   *bool  `json:"autoGenerateAudio,omitempty"`   // 自动生成音频（百家号）
+	CoverType           string `json:"coverType"`                     // 封面模式（百家号）：auto/single/triple
+	Category            string `json:"category"`                      // 分类（百家号）
+	UseIframe           *bool  `json:"useIframe,omitempty"`           // 是否使用iframe模式（TikTok）
+	UseFileChooser      *bool  `json:"useFileChooser,omitempty"`      // 是否使用文件选择器（快手）
+	SkipNewFeatureGuide *bool  `json:"skipNewFeatureGuide,omitempty"` // 是否跳过新功能引导（快手）
+}
+
+// MergeOnto 以 base 为基础叠加当前平台字段：非空字符串和已填写的布尔值覆盖 base
+func (f PlatformFields) MergeOnto(base PlatformFields) PlatformFields {
+	merged := base
+	mergeString(&merged.Title, f.Title)
+	mergeString(&merged.Description, f.Description)
+	mergeString(&merged.Collection, f.Collection)
+	mergeString(&merged.ShortTitle, f.ShortTitle)
+	mergeBool(&merged.IsOriginal, f.IsOriginal)
+	mergeString(&merged.OriginalType, f.OriginalType)
+	mergeString(&merged.Location, f.Location)
+	mergeString(&merged.Thumbnail, f.Thumbnail)
+	mergeBool(&merged.SyncToutiao, f.SyncToutiao)
+	mergeBool(&merged.SyncXigua, f.SyncXigua)
+	mergeBool(&merged.IsDraft, f.IsDraft)
+	mergeString(&merged.Copyright, f.Copyright)
+	mergeBool(&merged.AllowDownload, f.AllowDownload)
+	mergeBool(&merged.AllowComment, f.AllowComment)
+	mergeBool(&merged.AllowDuet, f.AllowDuet)
+	mergeBool(&merged.AIDeclaration, f.AIDeclaration)
+	mergeBool(&merged.AutoGenerateAudio, f.AutoGenerateAudio)
+	mergeString(&merged.CoverType, f.CoverType)
+	mergeString(&merged.Category, f.Category)
+	mergeBool(&merged.UseIframe, f.UseIframe)
+	mergeBool(&merged.UseFileChooser, f.UseFileChooser)
+	mergeBool(&merged.SkipNewFeatureGuide, f.SkipNewFeatureGuide)
+	return merged
+}
+
+// mergeString 非空字符串覆盖目标值
+func mergeString(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
+
+// mergeBool 已填写的布尔值覆盖目标值
+func mergeBool(dst **bool, v *bool) {
+	if v != nil {
+		*dst = v
+	}
 }
 
 // CommonMetadata 通用元数据
+// 标题和描述支持模板变量，如 "{series} 第{episode}集 | {date}"
 type CommonMetadata struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
@@ -76,5 +123,100 @@ type CommonMetadata struct {
 // UploadTaskMetadata 上传任务元数据
 type UploadTaskMetadata struct {
 	Common    CommonMetadata            `json:"common"`
+	TagSetID  int                       `json:"tagSetId,omitempty"` // 标签组ID，按目标平台展开
+	PresetID  int                       `json:"presetId,omitempty"` // 发布预设ID，为空时使用账号默认预设
 	Platforms map[string]PlatformFields `json:"platforms"`
+
+	// ScheduleMode 定时方式：native（平台定时）/local（本地定时）/空（自动选择）
+	ScheduleMode string `json:"scheduleMode,omitempty"`
+}
+
+// Clone 深拷贝元数据
+func (m UploadTaskMetadata) Clone() UploadTaskMetadata {
+	clone := m
+	if m.Platforms != nil {
+		clone.Platforms = make(map[string]PlatformFields, len(m.Platforms))
+		for k, v := range m.Platforms {
+			clone.Platforms[k] = v
+		}
+	}
+	return clone
+}
+
+// MergeOnto 以 base（如发布预设）为基础叠加当前元数据
+// 只有非空字符串、非零 ID 和已填写的布尔值会覆盖 base，前端提交的空字段不会清空预设
+func (m *UploadTaskMetadata) MergeOnto(base UploadTaskMetadata) UploadTaskMetadata {
+	merged := base.Clone()
+	if m == nil {
+		return merged
+	}
+	if merged.Platforms == nil {
+		merged.Platforms = make(map[string]PlatformFields)
+	}
+
+	mergeString(&merged.Common.Title, m.Common.Title)
+	mergeString(&merged.Common.Description, m.Common.Description)
+	if m.TagSetID > 0 {
+		merged.TagSetID = m.TagSetID
+	}
+	if m.PresetID > 0 {
+		merged.PresetID = m.PresetID
+	}
+	mergeString(&merged.ScheduleMode, m.ScheduleMode)
+	for platform, fields := range m.Platforms {
+		merged.Platforms[platform] = fields.MergeOnto(merged.Platforms[platform])
+	}
+	return merged
+}
+
+// TextNormalization 平台文本规范化结果
+type TextNormalization struct {
+	Field     string   `json:"field"`     // 字段：title/shortTitle/description
+	Original  string   `json:"original"`  // 原文
+	Result    string   `json:"result"`    // 规范化后的文本
+	Length    int      `json:"length"`    // 按平台方式计算的长度
+	MaxLength int      `json:"maxLength"` // 平台长度上限，0 表示不限制
+	Truncated bool     `json:"truncated"` // 是否被截断
+	Changes   []string `json:"changes"`   // 变换说明
+}
+
+// TaskTextPreview 模板渲染后的任务文案预览
+type TaskTextPreview struct {
+	AccountID   int      `json:"accountId"`
+	AccountName string   `json:"accountName"`
+	Platform    string   `json:"platform"`
+	Title       string   `json:"title"`
+	ShortTitle  string   `json:"shortTitle"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`        // 按平台展开后的标签
+	MissingVars []string `json:"missingVars"` // 未定义的模板变量
+
+	// 按平台规则规范化的结果，只包含发生变化的字段
+	Normalizations []TextNormalization `json:"normalizations"`
+}
+
+// 创建任务结果
+const (
+	TaskReportStarted     = "started"      // 已创建并开始上传
+	TaskReportScheduled   = "scheduled"    // 已创建本地定时任务
+	TaskReportDeferred    = "deferred"     // 因禁发时段推迟
+	TaskReportRateLimited = "rate_limited" // 因平台/账号限流排队
+	TaskReportFailed      = "failed"       // 未创建
+)
+
+// UploadTaskReport 单个账号的任务创建结果
+type UploadTaskReport struct {
+	AccountID    int    `json:"accountId"`
+	AccountName  string `json:"accountName"`
+	Platform     string `json:"platform"`
+	Result       string `json:"result"`
+	TaskID       int    `json:"taskId,omitempty"`
+	ScheduleTime string `json:"scheduleTime,omitempty"` // 实际定时时间（推迟后）
+	Reason       string `json:"reason,omitempty"`
+}
+
+// UploadTaskResult 创建上传任务的结果
+type UploadTaskResult struct {
+	TaskIDs []int              `json:"taskIds"`
+	Reports []UploadTaskReport `json:"reports"`
 }
@@ -17,8 +17,16 @@ var ScheduleTimeFormats = []string{
 
 // ParseScheduleTime 解析定时时间（支持多种格式）
 func ParseScheduleTime(timeStr string) (time.Time, error) {
+	return ParseScheduleTimeIn(timeStr, time.Local)
+}
+
+// ParseScheduleTimeIn 按指定时区解析定时时间，带时区偏移的 RFC3339 时间直接使用其偏移
+func ParseScheduleTimeIn(timeStr string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, timeStr); err == nil {
+		return t, nil
+	}
 	for _, format := range ScheduleTimeFormats {
-		if t, err := time.ParseInLocation(format, timeStr, time.Local); err == nil {
+		if t, err := time.ParseInLocation(format, timeStr, loc); err == nil {
 			return t, nil
 		}
 	}
@@ -31,67 +39,6 @@ func FormatScheduleTime(t time.Time) string {
 	return t.Format("2006-01-02 15:04")
 }
 
-// PlatformTimeFormat 平台时间格式
-type PlatformTimeFormat string
-
-const (
-	// DouyinFormat 抖音格式
-	DouyinFormat PlatformTimeFormat = "2006-01-02 15:04"
-	// XiaohongshuFormat 小红书格式
-	XiaohongshuFormat PlatformTimeFormat = "2006-01-02 15:04"
-	// KuaishouFormat 快手格式
-	KuaishouFormat PlatformTimeFormat = "2006-01-02 15:04"
-	// TencentFormat 视频号格式
-	TencentFormat PlatformTimeFormat = "2006-01-02 15:04"
-	// TiktokFormat TikTok格式（5分钟间隔）
-	TiktokFormat PlatformTimeFormat = "2006-01-02 15:04"
-	// BaijiahaoFormat 百家号格式
-	BaijiahaoFormat PlatformTimeFormat = "2006-01-02T15:04"
-)
-
-// ToPlatformFormat 转换为平台特定格式
-func ToPlatformFormat(t time.Time, platform string) string {
-	switch platform {
-	case "douyin":
-		return t.Format(string(DouyinFormat))
-	case "xiaohongshu":
-		return t.Format(string(XiaohongshuFormat))
-	case "kuaishou":
-		return t.Format(string(KuaishouFormat))
-	case "tencent":
-		return t.Format(string(TencentFormat))
-	case "tiktok":
-		// TikTok 使用5分钟间隔
-		minute := (t.Minute() / 5) * 5
-		adjustedTime := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), minute, 0, 0, t.Location())
-		return adjustedTime.Format(string(TiktokFormat))
-	case "baijiahao":
-		return t.Format(string(BaijiahaoFormat))
-	default:
-		return t.Format("2006-01-02 15:04")
-	}
-}
-
-// ValidateScheduleTime 验证定时时间是否有效
-func ValidateScheduleTime(timeStr string) error {
-	t, err := ParseScheduleTime(timeStr)
-	if err != nil {
-		return err
-	}
-
-	// 检查时间是否在未来
-	if t.Before(time.Now()) {
-		return fmt.Errorf("定时时间必须在未来")
-	}
-
-	// 检查时间是否在合理范围内（最多30天）
-	if t.After(time.Now().Add(30 * 24 * time.Hour)) {
-		return fmt.Errorf("定时时间不能超过30天")
-	}
-
-	return nil
-}
-
 // GetScheduleDelay 获取距离定时时间的延迟
 func GetScheduleDelay(timeStr string) (time.Duration, error) {
 	t, err := ParseScheduleTime(timeStr)
@@ -156,8 +103,3 @@ func GetNextValidScheduleTime(minDelay time.Duration) time.Time {
 
 	return time.Date(minTime.Year(), minTime.Month(), minTime.Day(), minTime.Hour(), nextMinute, 0, 0, minTime.Location())
 }
-
-// IsValidScheduleTime 检查时间字符串是否是有效的定时时间
-func IsValidScheduleTime(timeStr string) bool {
-	return ValidateScheduleTime(timeStr) == nil
-}